@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxChangedKeys 是 LogIfChanged 最多记录的键数量.
+// 超出后会淘汰最早记录的键，避免状态无限增长.
+const maxChangedKeys = 1024
+
+// changedState 记录每个键最后一次输出日志时的值.
+type changedState struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	order  []string
+}
+
+var lastChanged = &changedState{values: make(map[string]interface{})}
+
+// LogIfChanged 仅当 key 对应的值与上一次记录的值不同时，才以 info 级别记录日志.
+// 适用于周期性上报状态（如每 5 秒一次的健康检查）只在状态变化时输出的场景.
+// 这个函数是线程安全的.
+func LogIfChanged(key string, value interface{}, msg string, fields ...zap.Field) {
+	if !lastChanged.update(key, value) {
+		return
+	}
+	if ce := std.Load().WithOptions(zap.AddCallerSkip(1)).Check(zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// update 更新 key 的值，返回值是否发生了变化.
+func (s *changedState) update(key string, value interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.values[key]; ok {
+		if reflect.DeepEqual(last, value) {
+			return false
+		}
+		s.values[key] = value
+		return true
+	}
+
+	// 新的键，超出上限时淘汰最早记录的键
+	if len(s.order) >= maxChangedKeys {
+		delete(s.values, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.values[key] = value
+	s.order = append(s.order, key)
+	return true
+}
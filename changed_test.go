@@ -0,0 +1,45 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogIfChanged 测试只有在值变化时才记录日志.
+func TestLogIfChanged(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	prev := std.Swap(zap.New(core, zap.AddCaller()))
+	t.Cleanup(func() { std.Store(prev) })
+
+	for i := 0; i < 3; i++ {
+		LogIfChanged("test.health", "ok", "health changed")
+	}
+	for i := 0; i < 3; i++ {
+		LogIfChanged("test.health", "degraded", "health changed")
+	}
+
+	if n := logs.Len(); n != 2 {
+		t.Errorf("got %d log lines, want 2", n)
+	}
+	for _, e := range logs.All() {
+		if !strings.HasSuffix(e.Caller.File, "changed_test.go") {
+			t.Errorf("caller = %s, want changed_test.go", e.Caller.File)
+		}
+	}
+}
+
+// TestLogIfChangedBounded 测试键的数量不会无限增长.
+func TestLogIfChangedBounded(t *testing.T) {
+	s := &changedState{values: make(map[string]interface{})}
+	for i := 0; i < maxChangedKeys*2; i++ {
+		s.update(fmt.Sprintf("key-%d", i), i)
+	}
+	if n := len(s.values); n != maxChangedKeys {
+		t.Errorf("got %d keys, want %d", n, maxChangedKeys)
+	}
+}
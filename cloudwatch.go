@@ -0,0 +1,326 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.uber.org/zap/zapcore"
+)
+
+// CloudWatch Logs 的 PutLogEvents 限制，参见 PutLogEvents 的 API 文档.
+const (
+	cloudWatchMaxBatchEvents = 10000
+	cloudWatchMaxBatchBytes  = 1048576
+	cloudWatchEventOverhead  = 26
+	cloudWatchMaxEventBytes  = 256*1024 - cloudWatchEventOverhead
+	cloudWatchMaxBatchSpan   = 24 * time.Hour
+)
+
+const (
+	cloudWatchInterval     = time.Second
+	cloudWatchRetries      = 3
+	cloudWatchBackoff      = 200 * time.Millisecond
+	cloudWatchTimeout      = 10 * time.Second
+	cloudWatchMaxPending   = 10 * cloudWatchMaxBatchEvents
+	cloudWatchInitTimeout  = 5 * time.Second
+	cloudWatchTruncatedEnd = "...(truncated)"
+)
+
+// cloudWatchClient 是 CloudWatch Logs 客户端的接口，由 *cloudwatchlogs.Client 实现，测试中可以替换.
+type cloudWatchClient interface {
+	PutLogEvents(ctx context.Context, in *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogStream(ctx context.Context, in *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+}
+
+// newCloudWatchCore 创建一个 CloudWatch Logs 输出的 zapcore.Core，以及停止后台发送并发送剩余日志的函数.
+// 使用 AWS SDK 的默认凭证链，客户端在后台创建，不会阻塞 Init. 无法获取凭证时日志回退到 stderr.
+func newCloudWatchCore(enc zapcore.Encoder, opts *Options, enab zapcore.LevelEnabler, errOut zapcore.WriteSyncer) (zapcore.Core, func() error) {
+	sink := newCloudWatchSink(newCloudWatchClient, opts.CloudWatchGroup, opts.CloudWatchStream, errOut)
+	return zapcore.NewCore(enc, sink, enab), sink.Close
+}
+
+// newCloudWatchClient 使用默认配置创建 CloudWatch Logs 客户端，并确认可以获取凭证.
+func newCloudWatchClient() (cloudWatchClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudWatchInitTimeout)
+	defer cancel()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Credentials == nil {
+		return nil, errors.New("no aws credentials")
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, err
+	}
+	return cloudwatchlogs.NewFromConfig(cfg), nil
+}
+
+// cloudWatchSink 是将日志按批次发送到 CloudWatch Logs 日志流的 zapcore.WriteSyncer.
+// 客户端的创建和发送都在后台进行，不会阻塞写日志的调用方. 批次遵循 PutLogEvents 的条数、字节数和时间跨度限制，
+// 日志流不存在时自动创建，序列令牌失效时使用服务端返回的令牌重试.
+type cloudWatchSink struct {
+	connect func() (cloudWatchClient, error)
+	group   string
+	stream  string
+	errOut  zapcore.WriteSyncer
+
+	// client 和 fallback 在 ready 关闭之前由后台 goroutine 设置，之后只读.
+	// 创建客户端失败时 client 为 nil，日志写入 fallback
+	ready    chan struct{}
+	client   cloudWatchClient
+	fallback zapcore.WriteSyncer
+
+	mu      sync.Mutex
+	pending []types.InputLogEvent
+	dropped int
+
+	sendMu sync.Mutex // 保证批次按顺序发送，并保护 token
+	token  *string
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newCloudWatchSink 创建一个 cloudWatchSink，并启动使用 connect 创建客户端和后台发送的 goroutine.
+func newCloudWatchSink(connect func() (cloudWatchClient, error), group, stream string, errOut zapcore.WriteSyncer) *cloudWatchSink {
+	s := &cloudWatchSink{
+		connect: connect,
+		group:   group,
+		stream:  stream,
+		errOut:  errOut,
+		ready:   make(chan struct{}),
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Write 实现 io.Writer 接口. 每次写入作为一条日志事件，时间戳为写入时间.
+// 超过单条事件大小限制的日志会在字符边界处截断，待发送的日志超过上限时丢弃新的日志.
+func (s *cloudWatchSink) Write(p []byte) (int, error) {
+	msg := string(p)
+	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
+		msg = msg[:len(msg)-1]
+	}
+	if len(msg) > cloudWatchMaxEventBytes {
+		cut := cloudWatchMaxEventBytes - len(cloudWatchTruncatedEnd)
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		msg = msg[:cut] + cloudWatchTruncatedEnd
+	}
+	event := types.InputLogEvent{Message: aws.String(msg), Timestamp: aws.Int64(time.Now().UnixMilli())}
+
+	s.mu.Lock()
+	if len(s.pending) >= cloudWatchMaxPending {
+		s.dropped++
+		s.mu.Unlock()
+		return len(p), nil
+	}
+	s.pending = append(s.pending, event)
+	full := len(s.pending) >= cloudWatchMaxBatchEvents
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口，同步发送所有待发送的日志.
+func (s *cloudWatchSink) Sync() error {
+	return s.flush()
+}
+
+// Close 停止后台发送并发送剩余的日志.
+func (s *cloudWatchSink) Close() error {
+	s.once.Do(func() { close(s.stopCh) })
+	<-s.done
+	return s.flush()
+}
+
+// loop 创建客户端，之后在批次写满或定时器触发时发送日志.
+func (s *cloudWatchSink) loop() {
+	defer close(s.done)
+	s.init()
+	ticker := time.NewTicker(cloudWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+		_ = s.flush()
+	}
+}
+
+// init 创建客户端，失败时回退到 stderr. 完成后关闭 ready.
+func (s *cloudWatchSink) init() {
+	defer close(s.ready)
+	client, err := s.connect()
+	if err != nil {
+		fmt.Fprintf(s.errOut, "%v create cloudwatch logs client: %v, falling back to stderr\n", time.Now(), err)
+		_ = s.errOut.Sync()
+		s.fallback = newConsoleSyncer(os.Stderr)
+		return
+	}
+	s.client = client
+}
+
+// flush 按批次发送所有待发送的日志，客户端尚未创建完成时等待创建完成.
+func (s *cloudWatchSink) flush() error {
+	<-s.ready
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	events, dropped := s.pending, s.dropped
+	s.pending, s.dropped = nil, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.reportError(fmt.Errorf("dropped %d log events: too many pending", dropped))
+	}
+
+	if s.fallback != nil {
+		var firstErr error
+		for _, e := range events {
+			if _, err := s.fallback.Write([]byte(*e.Message + "\n")); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	// 同一批次中的事件必须按时间排序
+	sort.SliceStable(events, func(i, j int) bool {
+		return *events[i].Timestamp < *events[j].Timestamp
+	})
+	var firstErr error
+	for len(events) > 0 {
+		n := cloudWatchBatchLen(events)
+		if err := s.send(events[:n]); err != nil {
+			s.reportError(fmt.Errorf("dropped batch of %d log events: %w", n, err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		events = events[n:]
+	}
+	return firstErr
+}
+
+// cloudWatchBatchLen 返回从 events 开头可以放入一个批次的事件数.
+func cloudWatchBatchLen(events []types.InputLogEvent) int {
+	size := 0
+	first := *events[0].Timestamp
+	for i, e := range events {
+		size += len(*e.Message) + cloudWatchEventOverhead
+		if i == cloudWatchMaxBatchEvents || size > cloudWatchMaxBatchBytes ||
+			time.Duration(*e.Timestamp-first)*time.Millisecond > cloudWatchMaxBatchSpan {
+			return i
+		}
+	}
+	return len(events)
+}
+
+// send 发送一个批次. 序列令牌失效时使用服务端返回的令牌重试，日志流不存在时创建日志流后重试，
+// 其他错误按指数退避重试. 必须在持有 sendMu 时调用.
+func (s *cloudWatchSink) send(events []types.InputLogEvent) error {
+	backoff := cloudWatchBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudWatchTimeout)
+		out, err := s.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(s.group),
+			LogStreamName: aws.String(s.stream),
+			LogEvents:     events,
+			SequenceToken: s.token,
+		})
+		cancel()
+		if err == nil {
+			s.token = out.NextSequenceToken
+			return nil
+		}
+
+		var invalidToken *types.InvalidSequenceTokenException
+		var accepted *types.DataAlreadyAcceptedException
+		var notFound *types.ResourceNotFoundException
+		switch {
+		case errors.As(err, &accepted):
+			// 批次已经被接收，例如上一次请求超时但实际成功
+			s.token = accepted.ExpectedSequenceToken
+			return nil
+		case errors.As(err, &invalidToken):
+			s.token = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &notFound):
+			if err := s.createStream(); err != nil {
+				return err
+			}
+		}
+		if attempt >= cloudWatchRetries {
+			return err
+		}
+		if invalidToken == nil && notFound == nil {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+// createStream 创建日志流，日志流已经存在时视为成功.
+func (s *cloudWatchSink) createStream() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudWatchTimeout)
+	defer cancel()
+	_, err := s.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(s.group),
+		LogStreamName: aws.String(s.stream),
+	})
+	var exists *types.ResourceAlreadyExistsException
+	if err != nil && !errors.As(err, &exists) {
+		return err
+	}
+	s.token = nil
+	return nil
+}
+
+// reportError 将发送失败的原因写入错误输出.
+func (s *cloudWatchSink) reportError(err error) {
+	fmt.Fprintf(s.errOut, "%v write logs to cloudwatch %s/%s: %v\n", time.Now(), s.group, s.stream, err)
+	_ = s.errOut.Sync()
+}
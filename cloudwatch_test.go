@@ -0,0 +1,179 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeCloudWatchClient 是模拟序列令牌和日志流的 cloudWatchClient.
+type fakeCloudWatchClient struct {
+	streamExists bool
+	token        int
+	created      int
+	batches      [][]types.InputLogEvent
+	tokens       []*string
+}
+
+func (c *fakeCloudWatchClient) PutLogEvents(_ context.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	if !c.streamExists {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("stream not found")}
+	}
+	c.tokens = append(c.tokens, in.SequenceToken)
+	expected := aws.String(string(rune('a' + c.token)))
+	if c.token > 0 && aws.ToString(in.SequenceToken) != *expected {
+		return nil, &types.InvalidSequenceTokenException{ExpectedSequenceToken: expected}
+	}
+	c.batches = append(c.batches, in.LogEvents)
+	c.token++
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String(string(rune('a' + c.token)))}, nil
+}
+
+func (c *fakeCloudWatchClient) CreateLogStream(context.Context, *cloudwatchlogs.CreateLogStreamInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	c.created++
+	c.streamExists = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+// TestCloudWatchSink 测试日志流不存在时自动创建，并在序列令牌失效时使用服务端返回的令牌重试.
+func TestCloudWatchSink(t *testing.T) {
+	client := &fakeCloudWatchClient{}
+	var errOut bytes.Buffer
+	sink := newCloudWatchSink(func() (cloudWatchClient, error) { return client, nil }, "group", "stream", zapcore.AddSync(&errOut))
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(newEncoderConfig(NewOptions())), sink, zapcore.InfoLevel))
+
+	logger.Info("first")
+	if err := sink.Sync(); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	// 模拟其他写入方使序列令牌失效
+	client.token++
+	logger.Info("second")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	if client.created != 1 {
+		t.Errorf("created %d streams, want 1", client.created)
+	}
+	if len(client.batches) != 2 {
+		t.Fatalf("got %d batches, want 2: %s", len(client.batches), errOut.String())
+	}
+	for i, want := range []string{"first", "second"} {
+		if msg := aws.ToString(client.batches[i][0].Message); !strings.Contains(msg, want) || strings.HasSuffix(msg, "\n") {
+			t.Errorf("batch %d message = %q, want %q without newline", i, msg, want)
+		}
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected error output: %s", errOut.String())
+	}
+}
+
+// TestCloudWatchSinkConnect 测试客户端创建完成之前写入不会阻塞，超长的日志在字符边界处截断.
+func TestCloudWatchSinkConnect(t *testing.T) {
+	client := &fakeCloudWatchClient{streamExists: true}
+	release := make(chan struct{})
+	var errOut bytes.Buffer
+	sink := newCloudWatchSink(func() (cloudWatchClient, error) {
+		<-release
+		return client, nil
+	}, "group", "stream", zapcore.AddSync(&errOut))
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		_, _ = sink.Write([]byte(strings.Repeat("中", cloudWatchMaxEventBytes/3+1) + "\n"))
+	}()
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("客户端创建完成之前 Write 不应该阻塞")
+	}
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	if len(client.batches) != 1 {
+		t.Fatalf("got %d batches, want 1: %s", len(client.batches), errOut.String())
+	}
+	msg := aws.ToString(client.batches[0][0].Message)
+	if len(msg) > cloudWatchMaxEventBytes || !strings.HasSuffix(msg, cloudWatchTruncatedEnd) || !utf8.ValidString(msg) {
+		t.Errorf("message of %d bytes should be truncated on a character boundary", len(msg))
+	}
+}
+
+// TestCloudWatchBatchLen 测试批次遵循条数、字节数和时间跨度限制.
+func TestCloudWatchBatchLen(t *testing.T) {
+	event := func(size int, ts time.Duration) types.InputLogEvent {
+		return types.InputLogEvent{Message: aws.String(strings.Repeat("x", size)), Timestamp: aws.Int64(ts.Milliseconds())}
+	}
+	many := make([]types.InputLogEvent, cloudWatchMaxBatchEvents+5)
+	for i := range many {
+		many[i] = event(1, 0)
+	}
+	big := []types.InputLogEvent{event(600000, 0), event(600000, 0)}
+	span := []types.InputLogEvent{event(1, 0), event(1, time.Hour), event(1, 25*time.Hour)}
+
+	tests := []struct {
+		name   string
+		events []types.InputLogEvent
+		want   int
+	}{
+		{"count", many, cloudWatchMaxBatchEvents},
+		{"bytes", big, 1},
+		{"span", span, 2},
+	}
+	for _, tt := range tests {
+		if got := cloudWatchBatchLen(tt.events); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestCloudWatchFallback 测试无法获取凭证时日志回退到 stderr.
+func TestCloudWatchFallback(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	var errOut bytes.Buffer
+	opts := NewOptions()
+	opts.Apply(WithCloudWatchOutput("group", "stream"))
+	_, stop := newCloudWatchCore(zapcore.NewJSONEncoder(newEncoderConfig(opts)), opts, zapcore.InfoLevel, zapcore.AddSync(&errOut))
+	if err := stop(); err != nil {
+		t.Errorf("stop() error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "falling back to stderr") {
+		t.Errorf("got %q, want fallback message", errOut.String())
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// colorCodes 是 LevelColors 中可以使用的颜色和样式名称对应的 ANSI SGR 代码.
+var colorCodes = map[string]int{
+	"default":   39,
+	"black":     30,
+	"red":       31,
+	"green":     32,
+	"yellow":    33,
+	"blue":      34,
+	"magenta":   35,
+	"cyan":      36,
+	"white":     37,
+	"gray":      90,
+	"grey":      90,
+	"bold":      1,
+	"underline": 4,
+}
+
+// defaultLevelColors 是 zap 彩色日志级别使用的默认颜色.
+var defaultLevelColors = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "magenta",
+	zapcore.InfoLevel:   "blue",
+	zapcore.WarnLevel:   "yellow",
+	zapcore.ErrorLevel:  "red",
+	zapcore.DPanicLevel: "red",
+	zapcore.PanicLevel:  "red",
+	zapcore.FatalLevel:  "red",
+}
+
+// parseColor 将以空格分隔的颜色和样式名称转换为 ANSI 转义序列，例如 "bold red".
+func parseColor(spec string) (string, error) {
+	names := strings.Fields(strings.ToLower(spec))
+	if len(names) == 0 {
+		return "", fmt.Errorf("log level color cannot be empty")
+	}
+	codes := make([]string, len(names))
+	for i, name := range names {
+		code, ok := colorCodes[name]
+		if !ok {
+			return "", fmt.Errorf("unknown log level color %q", name)
+		}
+		codes[i] = strconv.Itoa(code)
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m", nil
+}
+
+// validateLevelColors 检查 LevelColors 中的级别和颜色是否有效.
+func validateLevelColors(colors map[string]string) error {
+	for name, spec := range colors {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(name)); err != nil {
+			return fmt.Errorf("log level colors: %w", err)
+		}
+		if _, err := parseColor(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newColorLevelEncoder 创建使用 colors 中颜色的彩色日志级别编码器，未指定的级别和无效的颜色使用 zap 的默认颜色.
+// 每个级别的输出在创建时生成，编码时不分配内存.
+func newColorLevelEncoder(colors map[string]string, lowercase bool) zapcore.LevelEncoder {
+	specs := make(map[zapcore.Level]string, len(defaultLevelColors))
+	for l, spec := range defaultLevelColors {
+		specs[l] = spec
+	}
+	for name, spec := range colors {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(name)); err == nil {
+			specs[l] = spec
+		}
+	}
+	rendered := make(map[zapcore.Level]string, len(specs))
+	for l, spec := range specs {
+		prefix, err := parseColor(spec)
+		if err != nil {
+			prefix, _ = parseColor(defaultLevelColors[l])
+		}
+		s := l.CapitalString()
+		if lowercase {
+			s = l.String()
+		}
+		rendered[l] = prefix + s + "\x1b[0m"
+	}
+	fallback := zapcore.CapitalColorLevelEncoder
+	if lowercase {
+		fallback = zapcore.LowercaseColorLevelEncoder
+	}
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if s, ok := rendered[l]; ok {
+			enc.AppendString(s)
+			return
+		}
+		fallback(l, enc)
+	}
+}
+
+// colorLevelEncoder 返回控制台输出使用的彩色日志级别编码器，设置了 LevelColors 时使用自定义的颜色.
+func colorLevelEncoder(opts *Options) zapcore.LevelEncoder {
+	if len(opts.LevelColors) > 0 {
+		return newColorLevelEncoder(opts.LevelColors, false)
+	}
+	return zapcore.CapitalColorLevelEncoder
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestColorOnlyOnConsole 测试彩色日志级别只出现在控制台输出中，不会写入文件.
+func TestColorOnlyOnConsole(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	logFile := filepath.Join(t.TempDir(), "color.log")
+	opts := NewOptions()
+	opts.Apply(WithColor(true), WithFilename(logFile), WithOutputPaths([]string{"stdout"}))
+	logger := New(opts)
+	logger.Info("colored message")
+	_ = logger.Sync()
+	_ = w.Close()
+
+	console, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("读取 stdout 失败: %v", err)
+	}
+	file, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("读取日志文件失败: %v", err)
+	}
+
+	if !strings.Contains(string(console), "\x1b[") {
+		t.Errorf("stdout 应该包含 ANSI 转义码: %q", console)
+	}
+	if strings.Contains(string(file), "\x1b[") {
+		t.Errorf("日志文件不应该包含 ANSI 转义码: %q", file)
+	}
+	if !strings.Contains(string(file), "colored message") {
+		t.Errorf("日志文件缺少消息: %q", file)
+	}
+}
+
+// TestDevelopmentNoColorWhenPiped 测试开发模式下输出重定向到管道时不会自动启用彩色级别.
+func TestDevelopmentNoColorWhenPiped(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	opts := NewOptions()
+	opts.Apply(WithDevelopment(true), WithOutputPaths([]string{"stdout"}))
+	logger := New(opts)
+	logger.Info("piped message")
+	_ = logger.Sync()
+	_ = w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("读取 stdout 失败: %v", err)
+	}
+	if !strings.Contains(string(out), "piped message") || strings.Contains(string(out), "\x1b[") {
+		t.Errorf("管道输出不应该包含 ANSI 转义码: %q", out)
+	}
+}
+
+// TestLevelColors 测试 WithLevelColors 自定义控制台输出中每个级别的颜色，json 格式不受影响.
+func TestLevelColors(t *testing.T) {
+	colors := map[string]string{"debug": "gray", "info": "default", "error": "bold red"}
+	for _, format := range []string{"console", "json"} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe() error: %v", err)
+		}
+		stdout := os.Stdout
+		os.Stdout = w
+
+		opts := NewOptions()
+		opts.Apply(WithColor(true), WithFormat(format), WithLevel("debug"), WithOutputPaths([]string{"stdout"}), WithLevelColors(colors))
+		logger := New(opts)
+		logger.Debug("debug message")
+		logger.Info("info message")
+		logger.Warn("warn message")
+		logger.Error("error message")
+		_ = logger.Sync()
+		_ = w.Close()
+		os.Stdout = stdout
+
+		out, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("读取 stdout 失败: %v", err)
+		}
+		if format == "json" {
+			if strings.Contains(string(out), "\x1b[") {
+				t.Errorf("json 输出不应该包含 ANSI 转义码: %q", out)
+			}
+			continue
+		}
+		for _, want := range []string{"\x1b[90mDEBUG\x1b[0m", "\x1b[39mINFO\x1b[0m", "\x1b[33mWARN\x1b[0m", "\x1b[1;31mERROR\x1b[0m"} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("console 输出 %q 缺少 %q", out, want)
+			}
+		}
+	}
+
+	// capitalColor 编码方式同样使用自定义的颜色
+	opts := NewOptions()
+	opts.Apply(WithLevelEncoder("capitalColor"), WithLevelColors(map[string]string{"warn": "cyan"}))
+	got := encodeEntry(t, opts, "console", zapcore.Entry{Level: zapcore.WarnLevel, Message: "hello"})
+	if !strings.Contains(got, "\x1b[36mWARN\x1b[0m") {
+		t.Errorf("got %q, want cyan WARN", got)
+	}
+
+	opts = NewOptions()
+	opts.Apply(WithLevelColors(map[string]string{"warn": "purple"}))
+	if err := opts.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown color")
+	}
+}
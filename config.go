@@ -0,0 +1,146 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfig 返回使用 Config 结构体标签中声明的默认值的配置.
+// 未声明默认值的输出路径与 NewOptions 保持一致.
+func defaultConfig() *Config {
+	cfg := &Config{
+		OutputPaths:      []string{"stdout"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if def, ok := v.Type().Field(i).Tag.Lookup("default"); ok {
+			// 默认值由结构体标签声明，解析失败属于编程错误
+			if err := setConfigField(v.Field(i), def); err != nil {
+				panic(fmt.Sprintf("invalid default for Config.%s: %v", v.Type().Field(i).Name, err))
+			}
+		}
+	}
+	return cfg
+}
+
+// ConfigFromEnv 从环境变量中读取日志配置，环境变量的名称由 Config 字段的 env 标签声明，
+// 例如 LOG_LEVEL、LOG_FORMAT 和 LOG_OUTPUT_PATHS. 未设置或为空的环境变量使用 default 标签声明的默认值.
+// 列表类型的环境变量以逗号分隔，布尔类型接受 strconv.ParseBool 支持的值.
+// 返回的配置已经通过 Validate 验证.
+func ConfigFromEnv() (*Config, error) {
+	cfg := defaultConfig()
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name, ok := v.Type().Field(i).Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		raw := strings.TrimSpace(os.Getenv(name))
+		if raw == "" {
+			continue
+		}
+		if err := setConfigField(v.Field(i), raw); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// InitFromEnv 使用 ConfigFromEnv 读取的配置初始化全局日志记录器.
+// 配置无效时返回错误，全局日志记录器保持不变.
+func InitFromEnv() error {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	initOptions(cfg.ToOptions())
+	return nil
+}
+
+// setConfigField 将字符串 raw 解析为字段的类型并赋值.
+func setConfigField(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// LoadConfig 从 YAML 文件 path 中读取日志配置，字段名称由 Config 字段的 yaml 标签声明.
+// 文件中未设置的字段使用 default 标签声明的默认值，空文件等同于全部使用默认值.
+// 返回的配置已经通过 Validate 验证.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read log config: %w", err)
+	}
+	cfg := defaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse log config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid log config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// InitFromFile 使用 LoadConfig 从 YAML 文件读取的配置初始化全局日志记录器.
+// 配置无效时返回错误，全局日志记录器保持不变.
+func InitFromFile(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	initOptions(cfg.ToOptions())
+	return nil
+}
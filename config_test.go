@@ -0,0 +1,95 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestConfigFromEnv 测试从环境变量读取配置，未设置的字段使用默认值.
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_OUTPUT_PATHS", " stdout, /var/log/app.log ,")
+	t.Setenv("LOG_COMPRESS", "true")
+	t.Setenv("LOG_MAX_AGE", "30")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv error: %v", err)
+	}
+	want := &Config{
+		Level:            "debug",
+		Format:           "console",
+		OutputPaths:      []string{"stdout", "/var/log/app.log"},
+		ErrorOutputPaths: []string{"stderr"},
+		MaxSize:          100,
+		MaxAge:           30,
+		MaxBackups:       3,
+		Compress:         true,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("cfg = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestConfigFromEnvInvalid 测试无法解析或验证失败的环境变量返回错误.
+func TestConfigFromEnvInvalid(t *testing.T) {
+	tests := map[string]string{
+		"LOG_MAX_SIZE":    "large",
+		"LOG_DEVELOPMENT": "maybe",
+		"LOG_FORMAT":      "xml",
+		"LOG_MAX_BACKUPS": "-1",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := ConfigFromEnv(); err == nil {
+				t.Errorf("%s=%s 应该返回错误", name, value)
+			}
+			if err := InitFromEnv(); err == nil {
+				t.Errorf("InitFromEnv 应该返回错误")
+			}
+		})
+	}
+}
+
+// TestLoadConfig 测试从 YAML 文件读取配置，未设置的字段和空文件使用默认值.
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("写入配置文件失败: %v", err)
+		}
+		return path
+	}
+
+	cfg, err := LoadConfig(write("log.yaml", "level: warn\nformat: json\noutput_paths:\n  - stderr\ncompress: true\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	want := defaultConfig()
+	want.Level, want.Format, want.OutputPaths, want.Compress = "warn", "json", []string{"stderr"}, true
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("cfg = %+v, want %+v", cfg, want)
+	}
+
+	cfg, err = LoadConfig(write("empty.yaml", ""))
+	if err != nil || !reflect.DeepEqual(cfg, defaultConfig()) {
+		t.Errorf("空文件应该使用默认值: cfg = %+v, err = %v", cfg, err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(dir, "missing.yaml"),
+		write("malformed.yaml", "level: [debug\n"),
+		write("invalid.yaml", "max_age: -1\n"),
+	} {
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) 应该返回错误", filepath.Base(path))
+		}
+		if err := InitFromFile(path); err == nil {
+			t.Errorf("InitFromFile(%s) 应该返回错误", filepath.Base(path))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// consoleSyncer 是写入 stdout/stderr 的 zapcore.WriteSyncer.
+// 终端、管道等设备不支持 fsync，对它们调用 Sync 会返回无害的错误，consoleSyncer 会忽略这些错误，
+// 这样 Sync 只会返回真正的写入失败，例如文件输出同步失败.
+type consoleSyncer struct {
+	file *os.File
+}
+
+// newConsoleSyncer 创建一个写入 file 的 consoleSyncer.
+func newConsoleSyncer(file *os.File) zapcore.WriteSyncer {
+	return &consoleSyncer{file: file}
+}
+
+// Write 实现 io.Writer 接口.
+func (s *consoleSyncer) Write(p []byte) (int, error) {
+	return s.file.Write(p)
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (s *consoleSyncer) Sync() error {
+	if err := s.file.Sync(); err != nil && !isIgnorableSyncError(err) {
+		return err
+	}
+	return nil
+}
+
+// isIgnorableSyncError 判断是否为控制台设备不支持同步时返回的错误，
+// 例如 "sync /dev/stdout: invalid argument" 或 "inappropriate ioctl for device".
+func isIgnorableSyncError(err error) bool {
+	return errors.Is(err, syscall.EINVAL) ||
+		errors.Is(err, syscall.ENOTTY) ||
+		errors.Is(err, syscall.ENOTSUP)
+}
+
+// isTerminal 判断 file 是否为终端等字符设备.
+// 重定向到管道或普通文件时返回 false.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestIsIgnorableSyncError 测试只忽略控制台设备不支持同步的错误.
+func TestIsIgnorableSyncError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&fs.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL}, true},
+		{&fs.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.ENOTTY}, true},
+		{&fs.PathError{Op: "sync", Path: "/var/log/app.log", Err: syscall.EIO}, false},
+		{errors.New("disk full"), false},
+	}
+	for _, tt := range tests {
+		if got := isIgnorableSyncError(tt.err); got != tt.want {
+			t.Errorf("isIgnorableSyncError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestIsTerminal 测试管道不会被识别为终端.
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if isTerminal(w) {
+		t.Error("管道不应该被识别为终端")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ContextWriteSyncer 是支持 context 的 zapcore.WriteSyncer，通常用于远程输出.
+// 通过 FromContext 获取的 logger 写入日志时，会将请求的 context 传递给 WriteContext，
+// 实现者应在 context 被取消或超时时尽快返回，避免慢速的远程输出阻塞请求.
+type ContextWriteSyncer interface {
+	zapcore.WriteSyncer
+	// WriteContext 在 ctx 的约束下写入一条已编码的日志.
+	WriteContext(ctx context.Context, p []byte) (int, error)
+}
+
+// contextField 返回一个携带 ctx 的字段.
+// 该字段的类型为 zapcore.SkipType，普通的 Encoder 会忽略它，只有 contextCore 会读取其中的 context.
+func contextField(ctx context.Context) zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: ctx}
+}
+
+// contextFromField 如果字段携带 context，则返回该 context.
+func contextFromField(f zapcore.Field) (context.Context, bool) {
+	if f.Type != zapcore.SkipType {
+		return nil, false
+	}
+	ctx, ok := f.Interface.(context.Context)
+	return ctx, ok
+}
+
+// contextCore 是将日志写入 ContextWriteSyncer 的 zapcore.Core.
+// 它从 logger 的字段中取出请求的 context，并在写入时传递给输出目标.
+type contextCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out ContextWriteSyncer
+	ctx context.Context
+}
+
+// newContextCore 创建一个写入 out 的 contextCore.
+func newContextCore(enc zapcore.Encoder, out ContextWriteSyncer, enab zapcore.LevelEnabler) zapcore.Core {
+	return &contextCore{
+		LevelEnabler: enab,
+		enc:          enc,
+		out:          out,
+		ctx:          context.Background(),
+	}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *contextCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &contextCore{
+		LevelEnabler: c.LevelEnabler,
+		enc:          c.enc.Clone(),
+		out:          c.out,
+		ctx:          c.ctx,
+	}
+	for _, f := range fields {
+		if ctx, ok := contextFromField(f); ok {
+			clone.ctx = ctx
+			continue
+		}
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *contextCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *contextCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	if _, err := c.out.WriteContext(c.ctx, buf.Bytes()); err != nil {
+		return err
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// 与 zap 的 ioCore 保持一致，panic/fatal 之前同步输出
+		_ = c.Sync()
+	}
+	return nil
+}
+
+// Sync 实现 zapcore.Core 接口.
+func (c *contextCore) Sync() error {
+	return c.out.Sync()
+}
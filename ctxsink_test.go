@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+)
+
+// blockingSink 模拟一个写入很慢的远程输出，直到 context 结束才返回.
+type blockingSink struct {
+	err chan error
+}
+
+func (s *blockingSink) Write(p []byte) (int, error) { return len(p), nil }
+func (s *blockingSink) Sync() error                 { return nil }
+
+func (s *blockingSink) WriteContext(ctx context.Context, p []byte) (int, error) {
+	select {
+	case <-ctx.Done():
+		s.err <- ctx.Err()
+		return 0, ctx.Err()
+	case <-time.After(5 * time.Second):
+		s.err <- nil
+		return len(p), nil
+	}
+}
+
+// TestContextSinkCancellation 测试取消 context 会中止正在进行的远程写入.
+func TestContextSinkCancellation(t *testing.T) {
+	sink := &blockingSink{err: make(chan error, 1)}
+	log.Init(
+		log.WithOutputPaths([]string{}),
+		log.WithContextSink(sink),
+	)
+	defer log.Init(log.WithLevel("info"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	log.FromContext(ctx).Info("slow remote write")
+
+	if err := <-sink.err; !errors.Is(err, context.Canceled) {
+		t.Errorf("WriteContext() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("写入耗时 %v，取消 context 后应尽快返回", elapsed)
+	}
+}
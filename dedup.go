@@ -0,0 +1,134 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 重复字段的处理策略.
+const (
+	// DedupLast 保留最后出现的字段，例如 With 添加的字段覆盖之前的默认字段.
+	DedupLast = "last"
+	// DedupFirst 保留最先出现的字段.
+	DedupFirst = "first"
+	// DedupError 保留最后出现的字段，并将重复的字段名写入错误输出.
+	DedupError = "error"
+)
+
+// dedupCore 是去除重复字段的 zapcore.Core 包装器.
+// 为了在写入时比较所有字段，通过 With 添加的字段会被保存下来，在每次写入时与调用传入的字段一起交给内部的 Core.
+type dedupCore struct {
+	zapcore.Core
+	strategy string
+	fields   []zapcore.Field
+	errOut   zapcore.WriteSyncer
+}
+
+// newDedupCore 创建一个按 strategy 去除重复字段的 dedupCore.
+func newDedupCore(core zapcore.Core, strategy string, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &dedupCore{Core: core, strategy: strategy, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+// 没有字段名的 context 字段直接传递给内部的 Core，其他字段保存到写入时再去重.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	core := c.Core
+	var keep []zapcore.Field
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType {
+			core = core.With([]zapcore.Field{f})
+			continue
+		}
+		keep = append(keep, f)
+	}
+	return &dedupCore{
+		Core:     core,
+		strategy: c.strategy,
+		fields:   append(c.fields[:len(c.fields):len(c.fields)], keep...),
+		errOut:   c.errOut,
+	}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.fields) > 0 {
+		all = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	}
+	writeThrough(c.Core, c.errOut, ent, c.dedup(ent, all))
+	return nil
+}
+
+// dedup 返回去除重复字段后的字段，不修改传入的切片.
+// 字段名在所在的命名空间内比较，不同命名空间中的同名字段不视为重复.
+// zap.Inline 添加的字段没有字段名，总是保留.
+func (c *dedupCore) dedup(ent zapcore.Entry, fields []zapcore.Field) []zapcore.Field {
+	if len(fields) < 2 {
+		return fields
+	}
+
+	// 记录每个字段名保留的字段位置
+	keys := make([]string, len(fields))
+	chosen := make(map[string]int, len(fields))
+	var duplicates []string
+	scope := ""
+	for i, f := range fields {
+		if f.Type == zapcore.SkipType || f.Type == zapcore.InlineMarshalerType {
+			continue
+		}
+		key := scope + f.Key
+		if f.Type == zapcore.NamespaceType {
+			scope = key + "."
+			continue
+		}
+		keys[i] = key
+		if _, ok := chosen[key]; ok {
+			duplicates = append(duplicates, f.Key)
+			if c.strategy == DedupFirst {
+				continue
+			}
+		}
+		chosen[key] = i
+	}
+	if len(duplicates) == 0 {
+		return fields
+	}
+	if c.strategy == DedupError {
+		fmt.Fprintf(c.errOut, "%v duplicate log fields %q in %q\n", ent.Time, duplicates, ent.Message)
+		_ = c.errOut.Sync()
+	}
+
+	out := make([]zapcore.Field, 0, len(fields)-len(duplicates))
+	for i, f := range fields {
+		if keys[i] != "" && chosen[keys[i]] != i {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
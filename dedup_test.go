@@ -0,0 +1,113 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestDedupFields 测试按策略去除 With 添加的字段和调用时传入的字段中的重复字段.
+func TestDedupFields(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     string
+		errOut   bool
+	}{
+		{DedupLast, "call", false},
+		{DedupFirst, "default", false},
+		{DedupError, "call", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			var errOut bytes.Buffer
+			inner, logs := observer.New(zapcore.DebugLevel)
+			logger := zap.New(newDedupCore(inner, tt.strategy, zapcore.AddSync(&errOut)))
+
+			logger.With(zap.String("source", "default"), zap.Int("n", 1)).
+				Info("hello", zap.String("source", "call"), zap.Namespace("detail"), zap.String("source", "nested"))
+
+			fields := logs.All()[0].ContextMap()
+			if fields["source"] != tt.want || fields["n"] != int64(1) {
+				t.Errorf("fields = %v, want source %s", fields, tt.want)
+			}
+			if detail, _ := fields["detail"].(map[string]interface{}); detail["source"] != "nested" {
+				t.Errorf("detail = %v, namespaced field should be kept", fields["detail"])
+			}
+			if got := strings.Contains(errOut.String(), `duplicate log fields ["source"]`); got != tt.errOut {
+				t.Errorf("error output = %q, want reported %v", errOut.String(), tt.errOut)
+			}
+		})
+	}
+}
+
+// TestDedupFromContext 测试调用时传入的字段覆盖 FromContext 添加的字段.
+func TestDedupFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	initStd(t, WithOutputPaths(nil), WithDedupFields("last"),
+		WithLevelSink("debug", SinkConfig{Writer: zapcore.AddSync(&buf), Format: "json"}))
+
+	ctx := ContextWithRequestID(context.Background(), "from-context")
+	FromContext(ctx).Info("handled", zap.String("requestID", "override"))
+
+	if out := buf.String(); strings.Count(out, `"requestID"`) != 1 || !strings.Contains(out, `"requestID":"override"`) {
+		t.Errorf("got %q, want a single overridden requestID", out)
+	}
+}
+
+// TestWithDedupFieldsInvalid 测试无效的策略被忽略.
+func TestWithDedupFieldsInvalid(t *testing.T) {
+	opts := NewOptions()
+	opts.Apply(WithDedupFields("last"), WithDedupFields("invalid"))
+	if opts.DedupFields != DedupLast {
+		t.Errorf("DedupFields = %q, want last", opts.DedupFields)
+	}
+}
+
+// TestDedupInline 测试 zap.Inline 添加的没有字段名的字段不会被当作重复字段.
+func TestDedupInline(t *testing.T) {
+	var errOut bytes.Buffer
+	inner, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newDedupCore(inner, DedupError, zapcore.AddSync(&errOut)))
+
+	user := zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("user", "alice")
+		return nil
+	})
+	order := zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt("order", 7)
+		return nil
+	})
+	logger.Info("hello", zap.Inline(user), zap.Inline(order), zap.Namespace("detail"), zap.Inline(user), zap.Inline(order))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["user"] != "alice" || fields["order"] != 7 {
+		t.Errorf("fields = %v, want both inline fields", fields)
+	}
+	if detail, _ := fields["detail"].(map[string]interface{}); detail["user"] != "alice" || detail["order"] != 7 {
+		t.Errorf("detail = %v, want both inline fields", fields["detail"])
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("error output = %q, inline fields should not be reported", errOut.String())
+	}
+}
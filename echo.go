@@ -0,0 +1,228 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build echo
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	gommonlog "github.com/labstack/gommon/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// EchoRequestIDKey 是 EchoMiddleware 在 echo.Context 中保存 requestID 的键.
+const EchoRequestIDKey = "requestID"
+
+// EchoMiddleware 返回一个记录每个请求的 echo 中间件.
+// 它按照与 HTTPMiddleware 相同的规则确定 requestID，注入请求的 context 并保存到 echo.Context
+// （键为 EchoRequestIDKey），同时写回响应头. 请求处理完成后，使用 FromContext 记录方法、URI、
+// 状态码、耗时和处理函数返回的错误，日志级别与 HTTPMiddleware 一致.
+// 这个文件需要使用 echo 构建标签编译.
+func EchoMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			req := c.Request()
+			requestID := requestIDForRequest(req)
+			ctx := ContextWithRequestID(req.Context(), requestID)
+			c.SetRequest(req.WithContext(ctx))
+			c.Set(EchoRequestIDKey, requestID)
+			c.Response().Header().Set(RequestIDHeader, requestID)
+
+			err := next(c)
+			if err != nil {
+				// 交给 echo 的错误处理函数写入响应，这样记录的状态码与实际返回的一致
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			fields := []zap.Field{
+				zap.String("method", req.Method),
+				zap.String("uri", req.RequestURI),
+				zap.Int("status", status),
+				zap.Duration("latency", time.Since(start)),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+			if ce := FromContext(c.Request().Context()).Check(statusLevel(status), "http request"); ce != nil {
+				ce.Write(fields...)
+			}
+			return nil
+		}
+	}
+}
+
+// EchoLogger 返回一个将 echo 内部日志输出到全局日志记录器的 echo.Logger.
+// 日志的输出目标由全局日志记录器决定，SetOutput 和 SetHeader 不起作用.
+func EchoLogger() echo.Logger {
+	return &echoLogger{
+		logger: std.Load().WithOptions(zap.AddCallerSkip(1)).Sugar(),
+		level:  gommonlog.DEBUG,
+	}
+}
+
+// echoLogger 是基于 zap 的 echo.Logger 实现.
+type echoLogger struct {
+	logger *zap.SugaredLogger
+	level  gommonlog.Lvl
+	prefix string
+}
+
+// Output 返回一个将写入内容以 info 级别记录的 io.Writer.
+func (l *echoLogger) Output() io.Writer {
+	return &zapWriter{logger: l.logger.Desugar(), level: zapcore.InfoLevel}
+}
+
+// SetOutput 不起作用，日志总是输出到 zap.
+func (l *echoLogger) SetOutput(io.Writer) {}
+
+// Prefix 返回日志前缀.
+func (l *echoLogger) Prefix() string { return l.prefix }
+
+// SetPrefix 设置日志前缀，作为 zap logger 的名称.
+func (l *echoLogger) SetPrefix(p string) {
+	l.prefix = p
+	l.logger = l.logger.Named(p)
+}
+
+// Level 返回日志级别.
+func (l *echoLogger) Level() gommonlog.Lvl { return l.level }
+
+// SetLevel 设置日志级别，低于该级别的日志会被丢弃.
+func (l *echoLogger) SetLevel(v gommonlog.Lvl) { l.level = v }
+
+// SetHeader 不起作用，日志格式由 zap 的 Encoder 决定.
+func (l *echoLogger) SetHeader(string) {}
+
+// Print 以 info 级别记录日志.
+func (l *echoLogger) Print(i ...interface{}) { l.log(gommonlog.INFO, fmt.Sprint(i...)) }
+
+// Printf 以 info 级别记录日志.
+func (l *echoLogger) Printf(format string, args ...interface{}) {
+	l.log(gommonlog.INFO, fmt.Sprintf(format, args...))
+}
+
+// Printj 以 info 级别记录日志.
+func (l *echoLogger) Printj(j gommonlog.JSON) { l.logj(gommonlog.INFO, j) }
+
+// Debug 以 debug 级别记录日志.
+func (l *echoLogger) Debug(i ...interface{}) { l.log(gommonlog.DEBUG, fmt.Sprint(i...)) }
+
+// Debugf 以 debug 级别记录日志.
+func (l *echoLogger) Debugf(format string, args ...interface{}) {
+	l.log(gommonlog.DEBUG, fmt.Sprintf(format, args...))
+}
+
+// Debugj 以 debug 级别记录日志.
+func (l *echoLogger) Debugj(j gommonlog.JSON) { l.logj(gommonlog.DEBUG, j) }
+
+// Info 以 info 级别记录日志.
+func (l *echoLogger) Info(i ...interface{}) { l.log(gommonlog.INFO, fmt.Sprint(i...)) }
+
+// Infof 以 info 级别记录日志.
+func (l *echoLogger) Infof(format string, args ...interface{}) {
+	l.log(gommonlog.INFO, fmt.Sprintf(format, args...))
+}
+
+// Infoj 以 info 级别记录日志.
+func (l *echoLogger) Infoj(j gommonlog.JSON) { l.logj(gommonlog.INFO, j) }
+
+// Warn 以 warn 级别记录日志.
+func (l *echoLogger) Warn(i ...interface{}) { l.log(gommonlog.WARN, fmt.Sprint(i...)) }
+
+// Warnf 以 warn 级别记录日志.
+func (l *echoLogger) Warnf(format string, args ...interface{}) {
+	l.log(gommonlog.WARN, fmt.Sprintf(format, args...))
+}
+
+// Warnj 以 warn 级别记录日志.
+func (l *echoLogger) Warnj(j gommonlog.JSON) { l.logj(gommonlog.WARN, j) }
+
+// Error 以 error 级别记录日志.
+func (l *echoLogger) Error(i ...interface{}) { l.log(gommonlog.ERROR, fmt.Sprint(i...)) }
+
+// Errorf 以 error 级别记录日志.
+func (l *echoLogger) Errorf(format string, args ...interface{}) {
+	l.log(gommonlog.ERROR, fmt.Sprintf(format, args...))
+}
+
+// Errorj 以 error 级别记录日志.
+func (l *echoLogger) Errorj(j gommonlog.JSON) { l.logj(gommonlog.ERROR, j) }
+
+// Fatal 以 fatal 级别记录日志，然后退出程序.
+func (l *echoLogger) Fatal(i ...interface{}) { l.logger.Fatal(i...) }
+
+// Fatalf 以 fatal 级别记录日志，然后退出程序.
+func (l *echoLogger) Fatalf(format string, args ...interface{}) { l.logger.Fatalf(format, args...) }
+
+// Fatalj 以 fatal 级别记录日志，然后退出程序.
+func (l *echoLogger) Fatalj(j gommonlog.JSON) { l.logger.Fatalw("", jsonKeysAndValues(j)...) }
+
+// Panic 以 panic 级别记录日志，然后 panic.
+func (l *echoLogger) Panic(i ...interface{}) { l.logger.Panic(i...) }
+
+// Panicf 以 panic 级别记录日志，然后 panic.
+func (l *echoLogger) Panicf(format string, args ...interface{}) { l.logger.Panicf(format, args...) }
+
+// Panicj 以 panic 级别记录日志，然后 panic.
+func (l *echoLogger) Panicj(j gommonlog.JSON) { l.logger.Panicw("", jsonKeysAndValues(j)...) }
+
+// log 在级别不低于 l.level 时记录一条日志.
+func (l *echoLogger) log(lvl gommonlog.Lvl, msg string) {
+	if lvl < l.level {
+		return
+	}
+	l.logger.Logw(echoToZapLevel(lvl), msg)
+}
+
+// logj 在级别不低于 l.level 时将 JSON 的键值记录为字段.
+func (l *echoLogger) logj(lvl gommonlog.Lvl, j gommonlog.JSON) {
+	if lvl < l.level {
+		return
+	}
+	l.logger.Logw(echoToZapLevel(lvl), "", jsonKeysAndValues(j)...)
+}
+
+// echoToZapLevel 将 echo 的日志级别转换为 zap 的日志级别.
+func echoToZapLevel(lvl gommonlog.Lvl) zapcore.Level {
+	switch lvl {
+	case gommonlog.DEBUG:
+		return zapcore.DebugLevel
+	case gommonlog.WARN:
+		return zapcore.WarnLevel
+	case gommonlog.ERROR:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// jsonKeysAndValues 将 JSON 转换为 SugaredLogger 使用的键值对.
+func jsonKeysAndValues(j gommonlog.JSON) []interface{} {
+	kv := make([]interface{}, 0, len(j)*2)
+	for k, v := range j {
+		kv = append(kv, k, v)
+	}
+	return kv
+}
@@ -0,0 +1,79 @@
+//go:build echo
+
+package log_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"github.com/labstack/echo/v4"
+	gommonlog "github.com/labstack/gommon/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestEchoMiddleware 测试请求日志包含 requestID，并按处理函数返回的错误记录状态码.
+func TestEchoMiddleware(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	e := echo.New()
+	e.Use(log.EchoMiddleware())
+	var requestID string
+	e.GET("/users/:id", func(c echo.Context) error {
+		requestID, _ = c.Get(log.EchoRequestIDKey).(string)
+		if log.RequestIDFromContext(c.Request().Context()) != requestID {
+			t.Error("请求的 context 应该携带 requestID")
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		return errors.New("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(log.RequestIDHeader, "req-1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	if requestID != "req-1" {
+		t.Errorf("requestID = %q, want req-1", requestID)
+	}
+	entries := logs.FilterMessage("http request").All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["requestID"] != "req-1" || fields["uri"] != "/users/1" || fields["status"] != int64(200) {
+		t.Errorf("fields = %v", fields)
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].ContextMap()["error"] != "boom" || rec.Code != http.StatusInternalServerError {
+		t.Errorf("error entry = %v, status = %d", entries[1].ContextMap(), rec.Code)
+	}
+}
+
+// TestEchoLogger 测试 echo 的内部日志输出到 zap 并遵循设置的级别.
+func TestEchoLogger(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	l := log.EchoLogger()
+	l.SetLevel(gommonlog.WARN)
+	l.Infof("dropped %d", 1)
+	l.Warnf("server %s", "started")
+	l.Errorj(gommonlog.JSON{"code": 500})
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Message != "server started" || entries[0].Level != zapcore.WarnLevel {
+		t.Errorf("entry = %v", entries[0])
+	}
+	if entries[1].ContextMap()["code"] != int64(500) {
+		t.Errorf("fields = %v", entries[1].ContextMap())
+	}
+}
@@ -0,0 +1,133 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeEntry 使用 opts 对应的编码器配置编码一条日志.
+func encodeEntry(t *testing.T, opts *Options, format string, ent zapcore.Entry) string {
+	t.Helper()
+	buf, err := newEncoder(format, newEncoderConfig(opts), opts).EncodeEntry(ent, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry error: %v", err)
+	}
+	defer buf.Free()
+	return buf.String()
+}
+
+// TestTimeFormat 测试时间格式和时区同时作用于 json 和 console 编码器.
+func TestTimeFormat(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	ts := time.Date(2025, 1, 2, 8, 4, 5, 123456789, shanghai)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"rfc3339nano utc", []Option{WithTimeFormat(time.RFC3339Nano), WithUTC(true)}, "2025-01-02T00:04:05.123456789Z"},
+		{"shortcut", []Option{WithTimeFormat("rfc3339"), WithTimeZone(time.UTC)}, "2025-01-02T00:04:05Z"},
+		{"iso8601 utc", []Option{WithUTC(true)}, "2025-01-02T00:04:05.123Z"},
+		{"default", nil, "2025-01-02T08:04:05.123+0800"},
+		{"epochNanos", []Option{WithTimeFormat("epochNanos"), WithUTC(true)}, "1735776245123456789"},
+		{"iso8601millis", []Option{WithISO8601Millis(), WithTimeZone(shanghai)}, "2025-01-02T08:04:05.123+08:00"},
+		{"iso8601millis utc", []Option{WithISO8601Millis(), WithUTC(true)}, "2025-01-02T00:04:05.123Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.Apply(tt.opts...)
+			ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: ts, Message: "hello"}
+			for _, format := range []string{"json", "console"} {
+				if got := encodeEntry(t, opts, format, ent); !strings.Contains(got, tt.want) {
+					t.Errorf("%s: got %q, want time %q", format, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestLevelEncoder 测试 WithLevelEncoder 选择对应的级别编码方式，无效的值被忽略.
+func TestLevelEncoder(t *testing.T) {
+	tests := []struct {
+		kind string
+		want string
+	}{
+		{"capital", `"level":"WARN"`},
+		{"lowercase", `"level":"warn"`},
+		{"capitalColor", `"level":"\u001b[33mWARN\u001b[0m"`},
+		{"color", `"level":"\u001b[33mwarn\u001b[0m"`},
+		{"unknown", `"level":"WARN"`},
+	}
+	for _, tt := range tests {
+		opts := NewOptions()
+		opts.Apply(WithLevelEncoder(tt.kind))
+		got := encodeEntry(t, opts, "json", zapcore.Entry{Level: zapcore.WarnLevel, Message: "hello"})
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+// TestEncoderKeys 测试自定义字段名，空字段名的字段不会输出.
+func TestEncoderKeys(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:      zapcore.ErrorLevel,
+		Time:       time.Date(2025, 1, 2, 0, 4, 5, 0, time.UTC),
+		LoggerName: "api",
+		Message:    "hello",
+		Caller:     zapcore.NewEntryCaller(0, "pkg/file.go", 10, true),
+		Stack:      "goroutine 1",
+	}
+
+	opts := NewOptions()
+	opts.Apply(WithMessageKey("message"), WithLevelKey("severity"), WithTimeKey("timestamp"),
+		WithCallerKey(""), WithNameKey("component"), WithStacktraceKey("stack"))
+	got := encodeEntry(t, opts, "json", ent)
+	for _, want := range []string{`"message":"hello"`, `"severity":"ERROR"`, `"timestamp":"`, `"component":"api"`, `"stack":"goroutine 1"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %q, want %s", got, want)
+		}
+	}
+	for _, omitted := range []string{`"msg"`, `"level"`, `"ts"`, `"caller"`, "file.go"} {
+		if strings.Contains(got, omitted) {
+			t.Errorf("got %q, should not contain %s", got, omitted)
+		}
+	}
+
+	opts = NewOptions()
+	opts.Apply(WithEncoderKeys(EncoderKeys{MessageKey: "m"}))
+	if got := encodeEntry(t, opts, "json", ent); got != `{"m":"hello"}`+"\n" {
+		t.Errorf("got %q, want only the message key", got)
+	}
+}
+
+// TestConsoleSeparator 测试 console 格式使用自定义的分隔符，json 格式不受影响.
+func TestConsoleSeparator(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Time:    time.Date(2025, 1, 2, 0, 4, 5, 0, time.UTC),
+		Message: "hello",
+		Caller:  zapcore.NewEntryCaller(0, "pkg/file.go", 10, true),
+	}
+	opts := NewOptions()
+	opts.Apply(WithUTC(true), WithConsoleSeparator(" | "))
+
+	buf, err := newEncoder("console", newEncoderConfig(opts), opts).EncodeEntry(ent, []zapcore.Field{{Key: "user", Type: zapcore.StringType, String: "alice"}})
+	if err != nil {
+		t.Fatalf("EncodeEntry error: %v", err)
+	}
+	defer buf.Free()
+	want := `2025-01-02T00:04:05.000Z | INFO | pkg/file.go:10 | hello | {"user": "alice"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("console: got %q, want %q", got, want)
+	}
+
+	if got := encodeEntry(t, opts, "json", ent); strings.Contains(got, " | ") {
+		t.Errorf("json: got %q, want the separator to be ignored", got)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap/zapcore"
+)
+
+// encryptSchemeAESGCM 表示使用 AES-GCM 和随机 12 字节 nonce 加密的记录.
+const encryptSchemeAESGCM byte = 1
+
+// encryptHeaderSize 是加密记录头部的长度: 1 字节方案、12 字节 nonce 和 4 字节大端序的密文长度.
+const encryptHeaderSize = 1 + 12 + 4
+
+// encryptWriter 将每次写入加密为一条独立的记录后写入 ws 的 zapcore.WriteSyncer.
+// 每条记录都带有头部，轮转、压缩后的文件以及追加写入的文件都可以逐条解密.
+type encryptWriter struct {
+	ws   zapcore.WriteSyncer
+	aead cipher.AEAD
+}
+
+// newEncryptWriter 创建一个使用 key 加密的 encryptWriter. key 的长度必须为 16、24 或 32 字节.
+func newEncryptWriter(ws zapcore.WriteSyncer, key []byte) (zapcore.WriteSyncer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptWriter{ws: ws, aead: aead}, nil
+}
+
+// newAEAD 使用 key 创建 AES-GCM.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("log encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Write 实现 io.Writer 接口，整条记录通过一次写入完成，轮转不会把记录拆分到两个文件中.
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	nonceSize := w.aead.NonceSize()
+	record := make([]byte, encryptHeaderSize, encryptHeaderSize+len(p)+w.aead.Overhead())
+	record[0] = encryptSchemeAESGCM
+	nonce := record[1 : 1+nonceSize]
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, err
+	}
+	record = w.aead.Seal(record, nonce, p, nil)
+	binary.BigEndian.PutUint32(record[1+nonceSize:encryptHeaderSize], uint32(len(record)-encryptHeaderSize))
+	if _, err := w.ws.Write(record); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (w *encryptWriter) Sync() error {
+	return w.ws.Sync()
+}
+
+// keyErrorSyncer 是每次写入都返回 err 的 zapcore.WriteSyncer.
+// 加密配置无效时代替文件输出，避免将明文写入磁盘.
+type keyErrorSyncer struct {
+	err error
+}
+
+// Write 实现 io.Writer 接口.
+func (s keyErrorSyncer) Write([]byte) (int, error) { return 0, s.err }
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (s keyErrorSyncer) Sync() error { return nil }
+
+// encryptFileSyncer 在配置了加密密钥时返回加密写入 ws 的 zapcore.WriteSyncer，否则直接返回 ws.
+func encryptFileSyncer(ws zapcore.WriteSyncer, opts *Options) zapcore.WriteSyncer {
+	if len(opts.EncryptionKey) == 0 {
+		return ws
+	}
+	enc, err := newEncryptWriter(ws, opts.EncryptionKey)
+	if err != nil {
+		return keyErrorSyncer{err: err}
+	}
+	return enc
+}
+
+// Decrypt 从 r 中读取 WithEncryptedFile 写入的加密记录，将解密后的日志写入 w.
+func Decrypt(r io.Reader, w io.Writer, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	br := bufio.NewReader(r)
+	header := make([]byte, encryptHeaderSize)
+	var ciphertext, plaintext []byte
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read encrypted log record: %w", err)
+		}
+		if header[0] != encryptSchemeAESGCM {
+			return fmt.Errorf("unknown log encryption scheme %d", header[0])
+		}
+		n := binary.BigEndian.Uint32(header[1+aead.NonceSize():])
+		if cap(ciphertext) < int(n) {
+			ciphertext = make([]byte, n)
+		}
+		ciphertext = ciphertext[:n]
+		if _, err := io.ReadFull(br, ciphertext); err != nil {
+			return fmt.Errorf("read encrypted log record: %w", err)
+		}
+		plaintext, err = aead.Open(plaintext[:0], header[1:1+aead.NonceSize()], ciphertext, nil)
+		if err != nil {
+			return fmt.Errorf("decrypt log record: %w", err)
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+	}
+}
+
+// DecryptFile 解密 WithEncryptedFile 写入的日志文件 in，将明文写入文件 out.
+// in 以 .gz 或 .zst 结尾时先解压，因此可以直接解密轮转后压缩的文件.
+func DecryptFile(in, out string, key []byte) (err error) {
+	src, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var r io.Reader = src
+	switch {
+	case strings.HasSuffix(in, ".gz"):
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	case strings.HasSuffix(in, ".zst"):
+		zr, err := zstd.NewReader(src)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	dst, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := dst.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return Decrypt(r, dst, key)
+}
@@ -0,0 +1,135 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestEncryptedFile 测试加密写入日志文件，文件中不包含明文，并可以使用 DecryptFile 解密.
+func TestEncryptedFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	key := bytes.Repeat([]byte{7}, 32)
+
+	l := NewLogger(WithOutputPaths(nil), WithFilename(filename), WithFormat("json"), WithEncryptedFile(key))
+	l.Info("secret message", zap.String("user", "alice"))
+	l.Info("another message")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret message")) || bytes.Contains(raw, []byte("alice")) {
+		t.Fatalf("encrypted file contains plaintext: %q", raw)
+	}
+
+	out := filepath.Join(dir, "app.txt")
+	if err := DecryptFile(filename, out, key); err != nil {
+		t.Fatal(err)
+	}
+	plain, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(plain)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"msg":"secret message"`) || !strings.Contains(lines[0], `"user":"alice"`) ||
+		!strings.Contains(lines[1], `"msg":"another message"`) {
+		t.Errorf("decrypted = %q", plain)
+	}
+
+	// 错误的密钥无法解密
+	if err := DecryptFile(filename, out, bytes.Repeat([]byte{8}, 32)); err == nil {
+		t.Error("DecryptFile() with wrong key should fail")
+	}
+}
+
+// TestEncryptedFileRotation 测试轮转并压缩后的加密文件可以解压后解密.
+func TestEncryptedFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	key := bytes.Repeat([]byte{1}, 16)
+
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.Apply(WithCompressAlgorithm(CompressZstd), WithEncryptedFile(key))
+	w := newRotatingWriter(filename, opts)
+	w.now = func() time.Time { return now }
+	w.maxSize = 10
+	defer w.Close()
+	ws, err := newEncryptWriter(zapcore.AddSync(w), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first line\n", "second line\n"} {
+		now = now.Add(time.Second)
+		if _, err := ws.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	var backup string
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w.millMu.Lock()
+		backups := w.backups()
+		w.millMu.Unlock()
+		if len(backups) == 1 && strings.HasSuffix(backups[0].path, ".zst") {
+			backup = backups[0].path
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backups = %v, want 1 zstd file", backups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for path, want := range map[string]string{backup: "first line\n", filename: "second line\n"} {
+		out := filepath.Join(dir, "out.txt")
+		if err := DecryptFile(path, out, key); err != nil {
+			t.Fatalf("DecryptFile(%s) error: %v", filepath.Base(path), err)
+		}
+		got, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("decrypted %s = %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+}
+
+// TestEncryptedFileInvalidKey 测试无效的密钥被 Validate 拒绝，并且不会写入明文.
+func TestEncryptedFileInvalidKey(t *testing.T) {
+	opts := NewOptions()
+	opts.Apply(WithEncryptedFile([]byte("short")))
+	if err := opts.Validate(); err == nil {
+		t.Error("Validate() should reject a 5-byte key")
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	var errOut bytes.Buffer
+	l := NewLogger(WithOutputPaths(nil), WithFilename(filename), WithErrorWriter(&errOut),
+		WithErrorOutputPaths(nil), WithEncryptedFile([]byte("short")))
+	l.Info("secret message")
+	_ = l.Close()
+
+	raw, _ := os.ReadFile(filename)
+	if bytes.Contains(raw, []byte("secret message")) {
+		t.Errorf("file contains plaintext: %q", raw)
+	}
+	if !strings.Contains(errOut.String(), "encryption key") {
+		t.Errorf("error output = %q, want the key error", errOut.String())
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxErrorCauses 是 errorCauses 中最多记录的错误数量.
+const maxErrorCauses = 32
+
+// ErrorField 返回记录错误详情的字段，包括:
+//
+//   - error: 错误信息
+//   - errorType: 错误的类型，例如 *fs.PathError
+//   - errorVerbose: %+v 格式的错误，只在与错误信息不同时记录，例如 pkg/errors 的错误会包含堆栈
+//   - errorCauses: 通过 Unwrap 或 Cause 展开的错误链，不包括 err 本身
+//   - errorStack: 调用 ErrorField 处的堆栈，DisableStacktrace 关闭了自动堆栈时也会记录
+//
+// err 为 nil 时返回的字段不会输出任何内容.
+func ErrorField(err error) zap.Field {
+	return errorField(err, 2)
+}
+
+// LogError 使用 FromContext(ctx) 记录一条带有 ErrorField 的 error 级别日志，
+// 调用者信息和堆栈指向调用 LogError 的位置. err 为 nil 时不记录日志.
+func LogError(ctx context.Context, msg string, err error, fields ...zap.Field) {
+	if err == nil {
+		return
+	}
+	if ce := checkContext(ctx, zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(append(fields[:len(fields):len(fields)], errorField(err, 2))...)
+	}
+}
+
+// errorField 创建 ErrorField 返回的字段，堆栈跳过 errorField 以上 skip-1 层调用.
+func errorField(err error, skip int) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Inline(errorDetails{err: err, stack: zap.StackSkip("", skip).String})
+}
+
+// errorDetails 是将错误详情添加到日志中的 zapcore.ObjectMarshaler.
+type errorDetails struct {
+	err   error
+	stack string
+}
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler 接口.
+func (d errorDetails) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	msg := d.err.Error()
+	enc.AddString("error", msg)
+	enc.AddString("errorType", fmt.Sprintf("%T", d.err))
+	if verbose := fmt.Sprintf("%+v", d.err); verbose != msg {
+		enc.AddString("errorVerbose", verbose)
+	}
+	if causes := newErrorCauses(d.err); len(causes) > 0 {
+		if err := enc.AddArray("errorCauses", causes); err != nil {
+			return err
+		}
+	}
+	if d.stack != "" {
+		enc.AddString("errorStack", d.stack)
+	}
+	return nil
+}
+
+// errorCauses 按深度优先的顺序展开 err 的错误链，不包括 err 本身.
+// 支持 Unwrap() error、errors.Join 等返回的 Unwrap() []error，以及 pkg/errors 的 Cause() error.
+type errorCauses []error
+
+// newErrorCauses 返回 err 展开后的错误链.
+func newErrorCauses(err error) errorCauses {
+	var causes errorCauses
+	var walk func(err error)
+	walk = func(err error) {
+		var next []error
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			next = e.Unwrap()
+		case interface{ Unwrap() error }:
+			next = []error{e.Unwrap()}
+		case interface{ Cause() error }:
+			next = []error{e.Cause()}
+		}
+		for _, cause := range next {
+			// 限制展开的数量，防止 Cause 返回自身等情况导致无限循环
+			if cause == nil || len(causes) >= maxErrorCauses {
+				continue
+			}
+			causes = append(causes, cause)
+			walk(cause)
+		}
+	}
+	walk(err)
+	return causes
+}
+
+// MarshalLogArray 实现 zapcore.ArrayMarshaler 接口.
+func (c errorCauses) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range c {
+		enc.AppendString(cause.Error())
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestErrorField 测试 ErrorField 记录错误信息、类型、展开的错误链和调用处的堆栈.
+func TestErrorField(t *testing.T) {
+	err := fmt.Errorf("query: %w", errors.Join(io.EOF, fs.ErrNotExist))
+	enc := zapcore.NewMapObjectEncoder()
+	ErrorField(err).AddTo(enc)
+
+	if enc.Fields["error"] != err.Error() {
+		t.Errorf("error = %v, want %q", enc.Fields["error"], err.Error())
+	}
+	if enc.Fields["errorType"] != "*fmt.wrapError" {
+		t.Errorf("errorType = %v, want *fmt.wrapError", enc.Fields["errorType"])
+	}
+	if _, ok := enc.Fields["errorVerbose"]; ok {
+		t.Error("errorVerbose 与错误信息相同时不应该记录")
+	}
+	causes, _ := enc.Fields["errorCauses"].([]interface{})
+	want := []interface{}{"EOF\nfile does not exist", "EOF", "file does not exist"}
+	if fmt.Sprint(causes) != fmt.Sprint(want) {
+		t.Errorf("errorCauses = %q, want %q", causes, want)
+	}
+	stack, _ := enc.Fields["errorStack"].(string)
+	if first, _, _ := strings.Cut(stack, "\n"); !strings.Contains(first, "TestErrorField") {
+		t.Errorf("errorStack should start at the caller, got %q", stack)
+	}
+
+	enc = zapcore.NewMapObjectEncoder()
+	ErrorField(nil).AddTo(enc)
+	if len(enc.Fields) != 0 {
+		t.Errorf("ErrorField(nil) fields = %v, want none", enc.Fields)
+	}
+}
+
+// verboseError 是 %+v 格式与错误信息不同的错误，类似 pkg/errors 的错误.
+type verboseError struct{ cause error }
+
+// Error 实现 error 接口.
+func (e verboseError) Error() string { return "verbose" }
+
+// Cause 返回被包装的错误.
+func (e verboseError) Cause() error { return e.cause }
+
+// Format 实现 fmt.Formatter 接口.
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "verbose\nwith details")
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+// TestLogError 测试 LogError 记录 error 级别的日志，支持 Cause 展开错误链，err 为 nil 时不记录.
+func TestLogError(t *testing.T) {
+	logs := observeStd(t)
+
+	LogError(context.Background(), "save failed", nil)
+	if logs.Len() != 0 {
+		t.Fatalf("err 为 nil 时不应该记录日志, got %d", logs.Len())
+	}
+
+	LogError(context.Background(), "save failed", verboseError{cause: io.ErrUnexpectedEOF}, zap.String("table", "users"))
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("entries = %v, want one error entry", entries)
+	}
+	fields := entries[0].ContextMap()
+	if fields["table"] != "users" || fields["errorVerbose"] != "verbose\nwith details" {
+		t.Errorf("fields = %v", fields)
+	}
+	if causes, _ := fields["errorCauses"].([]interface{}); len(causes) != 1 || causes[0] != io.ErrUnexpectedEOF.Error() {
+		t.Errorf("errorCauses = %v", fields["errorCauses"])
+	}
+	stack, _ := fields["errorStack"].(string)
+	if first, _, _ := strings.Cut(stack, "\n"); !strings.Contains(first, "TestLogError") {
+		t.Errorf("errorStack should start at the caller of LogError, got %q", stack)
+	}
+}
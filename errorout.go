@@ -0,0 +1,69 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// errorOutputSyncer 将 zap 写入错误输出的纯文本行编码为结构化日志.
+type errorOutputSyncer struct {
+	mu      sync.Mutex
+	encoder zapcore.Encoder
+	out     zapcore.WriteSyncer
+}
+
+// newErrorOutputSyncer 创建一个使用 encoder 编码内部错误的 zapcore.WriteSyncer.
+func newErrorOutputSyncer(encoder zapcore.Encoder, out zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return &errorOutputSyncer{encoder: encoder, out: out}
+}
+
+// Write 实现 io.Writer 接口.
+// zap 每次写入一行内部错误，每一行都会被编码为一条 error 级别的日志.
+func (s *errorOutputSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		buf, err := s.encoder.EncodeEntry(zapcore.Entry{
+			Level:   zapcore.ErrorLevel,
+			Time:    time.Now(),
+			Message: line,
+		}, nil)
+		if err != nil {
+			return 0, err
+		}
+		_, err = s.out.Write(buf.Bytes())
+		buf.Free()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (s *errorOutputSyncer) Sync() error {
+	return s.out.Sync()
+}
@@ -0,0 +1,43 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingSyncer 是一个总是写入失败的 zapcore.WriteSyncer.
+type failingSyncer struct{}
+
+func (failingSyncer) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+func (failingSyncer) Sync() error               { return nil }
+
+// TestErrorOutputEncoder 测试 logger 内部错误按指定格式输出.
+func TestErrorOutputEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	opts := NewOptions()
+	opts.ErrorOutputFormat = "json"
+
+	errorWS := newErrorOutputSyncer(newEncoder("json", newEncoderConfig(opts), opts), zapcore.AddSync(&buf))
+	core := zapcore.NewCore(newEncoder("json", newEncoderConfig(opts), opts), failingSyncer{}, zapcore.InfoLevel)
+	logger := zap.New(core, zap.ErrorOutput(errorWS))
+
+	logger.Info("will fail")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("错误输出不是合法的 json: %v, output: %q", err, buf.String())
+	}
+	if entry["level"] != "ERROR" {
+		t.Errorf("level = %v, want ERROR", entry["level"])
+	}
+	msg, _ := entry["msg"].(string)
+	if !strings.Contains(msg, "write error: disk full") {
+		t.Errorf("msg = %q, want to contain write error", msg)
+	}
+}
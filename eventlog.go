@@ -0,0 +1,35 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newEventLogOutputCore 创建写入 Windows 事件日志的 zapcore.Core，以及关闭事件日志的函数.
+// 事件日志不可用（例如在非 Windows 平台上）时，如果没有配置其他输出，则回退到 stderr.
+func newEventLogOutputCore(enc zapcore.Encoder, source string, enab zapcore.LevelEnabler, opts *Options) (zapcore.Core, func() error) {
+	if core, stop, err := newEventLogCore(enc, source, enab); err == nil {
+		return core, stop
+	}
+	if opts.Filename == "" && len(opts.OutputPaths) == 0 {
+		return zapcore.NewCore(enc, newConsoleSyncer(os.Stderr), enab), nil
+	}
+	return zapcore.NewNopCore(), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build windows
+
+package log
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventID 是写入事件日志时使用的事件 ID.
+const eventID = 1
+
+// eventWriter 是写入 Windows 事件日志的接口，*eventlog.Log 实现了该接口.
+type eventWriter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+}
+
+// newEventLogCore 打开事件源 source 并创建写入事件日志的 zapcore.Core，以及关闭事件日志的函数.
+// 事件源未注册时会尝试注册，注册需要管理员权限，已注册时注册错误会被忽略.
+func newEventLogCore(enc zapcore.Encoder, source string, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	_ = eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &eventLogCore{LevelEnabler: enab, enc: enc, out: l}, l.Close, nil
+}
+
+// eventLogCore 是写入 Windows 事件日志的 zapcore.Core.
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out eventWriter
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &eventLogCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), out: c.out}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *eventLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+// error 及以上级别写入错误事件，warn 写入警告事件，其他级别写入信息事件.
+func (c *eventLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		return c.out.Error(eventID, msg)
+	case ent.Level == zapcore.WarnLevel:
+		return c.out.Warning(eventID, msg)
+	default:
+		return c.out.Info(eventID, msg)
+	}
+}
+
+// Sync 实现 zapcore.Core 接口. 事件日志没有需要刷新的缓冲.
+func (c *eventLogCore) Sync() error {
+	return nil
+}
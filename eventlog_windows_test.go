@@ -0,0 +1,45 @@
+//go:build windows
+
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeEventWriter 记录写入的事件类型.
+type fakeEventWriter struct {
+	types []string
+}
+
+func (w *fakeEventWriter) Info(uint32, string) error { w.types = append(w.types, "info"); return nil }
+func (w *fakeEventWriter) Warning(uint32, string) error {
+	w.types = append(w.types, "warning")
+	return nil
+}
+func (w *fakeEventWriter) Error(uint32, string) error { w.types = append(w.types, "error"); return nil }
+
+// TestEventLogLevelMapping 测试日志级别到事件类型的映射.
+func TestEventLogLevelMapping(t *testing.T) {
+	out := &fakeEventWriter{}
+	enc := newEncoder("console", newEncoderConfig(NewOptions()), NewOptions())
+	logger := zap.New(&eventLogCore{LevelEnabler: zapcore.DebugLevel, enc: enc, out: out})
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	want := []string{"info", "info", "warning", "error"}
+	if len(out.types) != len(want) {
+		t.Fatalf("types = %v, want %v", out.types, want)
+	}
+	for i := range want {
+		if out.types[i] != want[i] {
+			t.Errorf("types = %v, want %v", out.types, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ContextExtractor 从 context 中提取需要附加到日志中的字段.
+type ContextExtractor func(ctx context.Context) []zap.Field
+
+var (
+	extractors   []ContextExtractor
+	extractorsMu sync.RWMutex
+)
+
+// RegisterContextExtractor 注册一个 context 字段提取器.
+// FromContext 会按注册顺序调用所有提取器，并将返回的字段追加在内置的 traceID/requestID 字段之后.
+// 这个函数是线程安全的.
+func RegisterContextExtractor(fn ContextExtractor) {
+	if fn == nil {
+		return
+	}
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// ResetContextExtractors 清空所有已注册的 context 字段提取器.
+// 主要用于测试之间的清理.
+func ResetContextExtractors() {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = nil
+}
+
+// extractContextFields 依次调用所有已注册的提取器，返回合并后的字段.
+func extractContextFields(ctx context.Context) []zap.Field {
+	extractorsMu.RLock()
+	fns := extractors
+	extractorsMu.RUnlock()
+
+	// 在锁外调用提取器，避免提取器内部注册新的提取器时死锁
+	var fields []zap.Field
+	for _, fn := range fns {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+// fieldsKey 是在 context 中保存累积日志字段的键.
+const fieldsKey = contextKey("fields")
+
+// ContextWithFields 返回一个附加了日志字段的新 context.
+// 字段会在嵌套调用之间累积，子 context 在父 context 的字段基础上追加;
+// 键相同的字段只保留最后添加的值.
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent, _ := ctx.Value(fieldsKey).([]zap.Field)
+
+	// 总是分配新的切片，避免修改父 context 中的字段
+	merged := make([]zap.Field, 0, len(parent)+len(fields))
+	merged = append(merged, parent...)
+	for _, f := range fields {
+		merged = mergeField(merged, f)
+	}
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+// FieldsFromContext 返回通过 ContextWithFields 累积在 context 中的字段.
+func FieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).([]zap.Field)
+	return append([]zap.Field(nil), fields...)
+}
+
+// mergeField 将字段合并到切片中，如果已存在同名字段则原位替换.
+func mergeField(fields []zap.Field, f zap.Field) []zap.Field {
+	for i := range fields {
+		if fields[i].Key == f.Key {
+			fields[i] = f
+			return fields
+		}
+	}
+	return append(fields, f)
+}
+
+// loggerKey 是在 context 中缓存 logger 的键.
+const loggerKey = contextKey("logger")
+
+// cachedLogger 是缓存在 context 中的 logger，以及缓存时所在的 span.
+type cachedLogger struct {
+	logger *zap.Logger
+	// skipped 是跳过两层调用者的 logger，供 DebugContext 等全局函数使用，避免每次调用都复制 logger
+	skipped *zap.Logger
+	span    trace.SpanContext
+}
+
+// ContextWithLogger 返回一个缓存了 logger 的新 context.
+// 之后 FromContext 会直接返回缓存的 logger，避免重复提取字段. 如果此后在该 context 上开启了
+// 新的 OpenTelemetry span（例如子 span），FromContext 会使用当前 span 的 traceID 和 spanID，
+// 保证日志反映的是当前所在的 span. logger 由 FromContext 生成时替换其中的追踪字段，否则附加 spanID，
+// 当 trace 也不同时还会附加 traceID.
+func ContextWithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	cached := cachedLogger{logger: logger, span: trace.SpanContextFromContext(ctx)}
+	if logger != nil {
+		cached.skipped = logger.WithOptions(zap.AddCallerSkip(2))
+	}
+	return context.WithValue(ctx, loggerKey, cached)
+}
+
+// loggerFromContext 返回 context 中缓存的 logger，并在当前 span 与缓存时不同时使用当前 span 的信息.
+// callerSkipped 为 true 时返回跳过两层调用者的 logger.
+func loggerFromContext(ctx context.Context, callerSkipped bool) (*zap.Logger, bool) {
+	cached, ok := ctx.Value(loggerKey).(cachedLogger)
+	if !ok || cached.logger == nil {
+		return nil, false
+	}
+	logger := cached.logger
+	if callerSkipped {
+		logger = cached.skipped
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || sc.Equal(cached.span) {
+		return logger, true
+	}
+
+	opts := stdOpts.Load()
+	if core, ok := logger.Core().(*traceFieldsCore); ok {
+		return logger.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return core.retrace(ctx, opts)
+		})), true
+	}
+
+	var fields []zap.Field
+	if sc.TraceID() != cached.span.TraceID() {
+		fields = append(fields, zap.String("traceID", sc.TraceID().String()))
+	}
+	fields = append(fields, zap.String("spanID", sc.SpanID().String()))
+	if opts.TraceFieldNamespace != "" {
+		fields = []zap.Field{zap.Object(opts.TraceFieldNamespace, traceFields(fields))}
+	}
+	if len(opts.ContextSinks) > 0 || opts.SpanEvents {
+		fields = append(fields, contextField(ctx))
+	}
+	return logger.With(fields...), true
+}
+
+// traceFieldsCore 是 FromContext 生成的 logger 使用的 zapcore.Core 包装器.
+// 它记录添加追踪字段之前的 Core 以及之后添加的字段，缓存的 logger 所在的 span 变化时，
+// 可以用当前 span 重新生成追踪字段，而不是在原有字段之后重复追加.
+type traceFieldsCore struct {
+	zapcore.Core
+	// base 是添加追踪字段之前的 Core
+	base zapcore.Core
+	// fields 是追踪字段之后添加的字段，包括 context 中的其他字段和之后通过 With 添加的字段
+	fields []zapcore.Field
+}
+
+// newTraceFieldsCore 创建一个在 base 上依次添加 traced 和 fields 的 traceFieldsCore.
+func newTraceFieldsCore(base zapcore.Core, traced, fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(traced)+len(fields))
+	all = append(append(all, traced...), fields...)
+	return &traceFieldsCore{Core: base.With(all), base: base, fields: fields}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *traceFieldsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &traceFieldsCore{
+		Core:   c.Core.With(fields),
+		base:   c.base,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// retrace 返回使用 ctx 中的追踪字段替换原有追踪字段的 Core.
+func (c *traceFieldsCore) retrace(ctx context.Context, opts *Options) zapcore.Core {
+	fields := c.fields
+	if len(opts.ContextSinks) > 0 || opts.SpanEvents {
+		fields = append(fields[:len(fields):len(fields)], contextField(ctx))
+	}
+	return newTraceFieldsCore(c.base, traceContextFields(ctx, opts), fields)
+}
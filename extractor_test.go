@@ -0,0 +1,198 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type userIDKey struct{}
+
+// TestRegisterContextExtractor 测试注册的提取器按顺序追加在内置字段之后.
+func TestRegisterContextExtractor(t *testing.T) {
+	logs := observeStd(t)
+	t.Cleanup(ResetContextExtractors)
+
+	RegisterContextExtractor(func(ctx context.Context) []zap.Field {
+		if userID, ok := ctx.Value(userIDKey{}).(string); ok {
+			return []zap.Field{zap.String("userID", userID)}
+		}
+		return nil
+	})
+	RegisterContextExtractor(func(ctx context.Context) []zap.Field {
+		return []zap.Field{zap.String("tenant", "t1")}
+	})
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = context.WithValue(ctx, userIDKey{}, "u-42")
+	FromContext(ctx).Info("hello")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	var keys []string
+	for _, f := range entries[0].Context {
+		keys = append(keys, f.Key)
+	}
+	want := []string{"requestID", "userID", "tenant"}
+	if len(keys) != len(want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("keys = %v, want %v", keys, want)
+		}
+	}
+}
+
+// TestResetContextExtractors 测试清空提取器后不再追加字段.
+func TestResetContextExtractors(t *testing.T) {
+	logs := observeStd(t)
+
+	RegisterContextExtractor(func(ctx context.Context) []zap.Field {
+		return []zap.Field{zap.String("tenant", "t1")}
+	})
+	ResetContextExtractors()
+
+	FromContext(context.Background()).Info("hello")
+
+	if got := len(logs.All()[0].Context); got != 0 {
+		t.Errorf("got %d context fields, want 0", got)
+	}
+}
+
+// TestContextWithFields 测试字段在嵌套的 ContextWithFields 调用之间累积.
+func TestContextWithFields(t *testing.T) {
+	logs := observeStd(t)
+
+	ctx := ContextWithFields(context.Background(), zap.String("layer", "http"), zap.String("route", "/users"))
+	ctx = ContextWithFields(ctx, zap.String("layer", "service"), zap.Int("userID", 7))
+
+	FromContext(ctx).Info("hello")
+
+	got := logs.All()[0].ContextMap()
+	want := map[string]interface{}{"layer": "service", "route": "/users", "userID": int64(7)}
+	if len(got) != len(want) {
+		t.Fatalf("fields = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("field %s = %v, want %v", k, got[k], v)
+		}
+	}
+	if n := len(logs.All()[0].Context); n != 3 {
+		t.Errorf("got %d fields, want 3 after de-duplication", n)
+	}
+}
+
+// TestContextWithFieldsDoesNotMutateParent 测试子 context 不会修改父 context 的字段.
+func TestContextWithFieldsDoesNotMutateParent(t *testing.T) {
+	parent := ContextWithFields(context.Background(), zap.String("layer", "http"))
+	_ = ContextWithFields(parent, zap.String("layer", "service"))
+
+	fields := FieldsFromContext(parent)
+	if len(fields) != 1 || fields[0].String != "http" {
+		t.Errorf("parent fields = %v, want [layer=http]", fields)
+	}
+}
+
+// TestContextWithLoggerChildSpan 测试缓存的 logger 会反映子 span 的 spanID.
+func TestContextWithLoggerChildSpan(t *testing.T) {
+	logger, logs := NewObserver()
+
+	traceID := trace.TraceID{1, 2, 3}
+	parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+	ctx := ContextWithLogger(parent, logger.With(zap.String("component", "api")))
+
+	// 缓存时所在的 span 不附加额外字段
+	FromContext(ctx).Info("parent span")
+
+	childSpanID := trace.SpanID{2}
+	child := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  childSpanID,
+	}))
+	FromContext(child).Info("child span")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()["spanID"]; ok {
+		t.Errorf("parent entry should not have spanID: %v", entries[0].ContextMap())
+	}
+	fields := entries[1].ContextMap()
+	if fields["spanID"] != childSpanID.String() {
+		t.Errorf("spanID = %v, want %s", fields["spanID"], childSpanID)
+	}
+	if fields["component"] != "api" {
+		t.Errorf("component = %v, want api", fields["component"])
+	}
+	if _, ok := fields["traceID"]; ok {
+		t.Errorf("同一个 trace 的子 span 不应重复附加 traceID: %v", fields)
+	}
+}
+
+// TestContextWithLoggerChildSpanFromContext 测试缓存 FromContext 生成的 logger 后，子 span 的日志只有一个 spanID.
+func TestContextWithLoggerChildSpanFromContext(t *testing.T) {
+	for _, namespace := range []string{"", "trace"} {
+		t.Run("namespace="+namespace, func(t *testing.T) {
+			var buf bytes.Buffer
+			initStd(t, WithOutputPaths(nil), WithWriter(&buf), WithFormat("json"), WithProcessFields(false),
+				WithTraceFieldNamespace(namespace))
+
+			traceID := trace.TraceID{1, 2, 3}
+			parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: traceID,
+				SpanID:  trace.SpanID{1},
+			}))
+			parent = ContextWithRequestID(parent, "req-1")
+			ctx := ContextWithLogger(parent, FromContext(parent).With(zap.String("component", "api")))
+
+			child := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: traceID,
+				SpanID:  trace.SpanID{2},
+			}))
+			FromContext(child).Info("child span")
+
+			got := buf.String()
+			if n := strings.Count(got, `"spanID"`); n != 1 {
+				t.Fatalf("got %d spanID keys, want 1: %s", n, got)
+			}
+			want := `"traceID":"01020300000000000000000000000000","spanID":"0200000000000000","requestID":"req-1"`
+			if namespace != "" {
+				want = `"trace":{` + want + `}`
+			}
+			if !strings.Contains(got, want) || !strings.Contains(got, `"component":"api"`) {
+				t.Errorf("output = %s, want %s and the component field", got, want)
+			}
+		})
+	}
+}
+
+// TestCallerSkippedLoggerCached 测试全局日志函数复用跳过调用者的 logger，全局日志记录器被替换时重新创建.
+func TestCallerSkippedLoggerCached(t *testing.T) {
+	observeStd(t)
+
+	skipped := stdCallerSkipped()
+	if stdCallerSkipped() != skipped {
+		t.Error("stdCallerSkipped() should reuse the cached logger")
+	}
+	ctx := ContextWithLogger(context.Background(), FromContext(context.Background()))
+	if fromContext(ctx, true) != fromContext(ctx, true) {
+		t.Error("fromContext(ctx, true) should reuse the logger cached by ContextWithLogger")
+	}
+
+	observeStd(t)
+	if stdCallerSkipped() == skipped {
+		t.Error("stdCallerSkipped() should follow the replaced global logger")
+	}
+}
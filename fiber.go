@@ -0,0 +1,118 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build fiber
+
+package log
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceIDHeader 是 FiberMiddleware 在没有 OpenTelemetry 传播信息时读取 traceID 的请求头.
+const TraceIDHeader = "X-Trace-ID"
+
+// FiberMiddleware 返回一个记录每个请求的 fiber 中间件.
+// fiber 基于 fasthttp，请求没有 context.Context，中间件会构建一个标准的 context 并通过
+// SetUserContext 保存，处理函数使用 FiberContext(c) 取出后即可传给 FromContext:
+//   - 通过全局的 OpenTelemetry 传播器从请求头中提取 span，没有时从 X-Trace-ID 请求头读取 traceID;
+//   - 按照与 HTTPMiddleware 相同的规则确定 requestID，并写回响应头.
+//
+// 请求处理完成后记录方法、路径、状态码、耗时和处理函数返回的错误，日志级别与 HTTPMiddleware 一致.
+// 这个文件需要使用 fiber 构建标签编译.
+func FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// fasthttp 返回的字符串引用会被复用的缓冲区，请求结束后仍然使用的值都需要复制
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), fiberCarrier{c})
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			if traceID := c.Get(TraceIDHeader); traceID != "" {
+				ctx = ContextWithTraceID(ctx, strings.Clone(traceID))
+			}
+		}
+		requestID := RequestIDFromContext(ctx)
+		if requestID == "" {
+			requestID = strings.Clone(c.Get(RequestIDHeader))
+		}
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		ctx = ContextWithRequestID(ctx, requestID)
+		c.SetUserContext(ctx)
+		c.Set(RequestIDHeader, requestID)
+
+		err := c.Next()
+		if err != nil {
+			// 交给 fiber 的错误处理函数写入响应，这样记录的状态码与实际返回的一致
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		fields := []zap.Field{
+			zap.String("method", strings.Clone(c.Method())),
+			zap.String("path", strings.Clone(c.Path())),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		if ce := FromContext(ctx).Check(statusLevel(status), "http request"); ce != nil {
+			ce.Write(fields...)
+		}
+		return nil
+	}
+}
+
+// FiberContext 返回 FiberMiddleware 为请求构建的标准 context，其中包含 traceID 和 requestID.
+// 没有使用 FiberMiddleware 时返回 fiber 默认的 context.
+func FiberContext(c *fiber.Ctx) context.Context {
+	return c.UserContext()
+}
+
+// fiberCarrier 将 fiber 的请求头适配为 OpenTelemetry 的 propagation.TextMapCarrier.
+type fiberCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get 返回请求头的值.
+func (fc fiberCarrier) Get(key string) string {
+	return strings.Clone(fc.c.Get(key))
+}
+
+// Set 设置请求头的值.
+func (fc fiberCarrier) Set(key, value string) {
+	fc.c.Request().Header.Set(key, value)
+}
+
+// Keys 返回所有请求头的名称.
+func (fc fiberCarrier) Keys() []string {
+	var keys []string
+	fc.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
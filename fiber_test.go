@@ -0,0 +1,58 @@
+//go:build fiber
+
+package log_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestFiberMiddleware 测试请求日志包含 traceID 和 requestID，并且处理函数可以通过 FiberContext 使用 FromContext.
+func TestFiberMiddleware(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	app := fiber.New()
+	app.Use(log.FiberMiddleware())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		log.FromContext(log.FiberContext(c)).Info("in handler")
+		return c.SendString("ok")
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/users/1", nil)
+	req.Header.Set(log.RequestIDHeader, "req-1")
+	req.Header.Set(log.TraceIDHeader, "trace-1")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.Header.Get(log.RequestIDHeader) != "req-1" {
+		t.Errorf("response header = %q, want req-1", resp.Header.Get(log.RequestIDHeader))
+	}
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil)); err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+
+	handler := logs.FilterMessage("in handler").All()
+	if len(handler) != 1 || handler[0].ContextMap()["traceID"] != "trace-1" || handler[0].ContextMap()["requestID"] != "req-1" {
+		t.Errorf("handler entries = %v", handler)
+	}
+	entries := logs.FilterMessage("http request").All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if fields := entries[0].ContextMap(); fields["path"] != "/users/1" || fields["status"] != int64(200) {
+		t.Errorf("fields = %v", fields)
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].ContextMap()["status"] != int64(500) {
+		t.Errorf("error entry = %v", entries[1].ContextMap())
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// EntryFilter 判断一条日志是否应该被记录，返回 false 时整条日志被丢弃.
+// fields 包含通过 With 添加的字段和本次调用传入的字段.
+type EntryFilter func(ent zapcore.Entry, fields []zapcore.Field) bool
+
+// filterCore 是按 EntryFilter 丢弃整条日志的 zapcore.Core 包装器.
+type filterCore struct {
+	zapcore.Core
+	filters []EntryFilter
+	errOut  zapcore.WriteSyncer
+	fields  []zapcore.Field
+}
+
+// newFilterCore 创建一个使用 filters 过滤日志的 filterCore.
+func newFilterCore(core zapcore.Core, filters []EntryFilter, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &filterCore{Core: core, filters: filters, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	accumulated := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	accumulated = append(accumulated, c.fields...)
+	accumulated = append(accumulated, fields...)
+	return &filterCore{
+		Core:    c.Core.With(fields),
+		filters: c.filters,
+		errOut:  c.errOut,
+		fields:  accumulated,
+	}
+}
+
+// Check 实现 zapcore.Core 接口.
+// 是否丢弃需要结合字段判断，因此推迟到 Write 中进行.
+func (c *filterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+// dpanic, panic 和 fatal 级别的日志不会被丢弃.
+func (c *filterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < zapcore.DPanicLevel {
+		all := fields
+		if len(c.fields) > 0 {
+			all = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+			all = append(all, c.fields...)
+			all = append(all, fields...)
+		}
+		for _, filter := range c.filters {
+			if !filter(ent, all) {
+				return nil
+			}
+		}
+	}
+	writeThrough(c.Core, c.errOut, ent, fields)
+	return nil
+}
+
+// writeThrough 将日志交给 core 重新检查并写入.
+// 包装器在 Write 中才能决定是否写入，通过重新 Check 保留 core 内部各自的级别判断和采样逻辑.
+func writeThrough(core zapcore.Core, errOut zapcore.WriteSyncer, ent zapcore.Entry, fields []zapcore.Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.ErrorOutput = errOut
+		ce.Write(fields...)
+	}
+}
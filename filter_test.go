@@ -0,0 +1,61 @@
+package log
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestEntryFilter 测试匹配过滤函数的日志被丢弃，其他日志正常记录.
+func TestEntryFilter(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	dropHealth := func(ent zapcore.Entry, fields []zapcore.Field) bool {
+		return !strings.Contains(ent.Message, "health")
+	}
+	dropInternal := func(ent zapcore.Entry, fields []zapcore.Field) bool {
+		for _, f := range fields {
+			if f.Key == "internal" {
+				return false
+			}
+		}
+		return true
+	}
+	logger := zap.New(newFilterCore(inner, []EntryFilter{dropHealth, dropInternal}, nopSyncer{}))
+
+	logger.Info("GET /health 200")
+	logger.Info("GET /users 200")
+	logger.With(zap.Bool("internal", true)).Info("internal request")
+	logger.Info("request", zap.Bool("internal", true))
+
+	if n := logs.Len(); n != 1 {
+		t.Fatalf("got %d entries, want 1", n)
+	}
+	if msg := logs.All()[0].Message; msg != "GET /users 200" {
+		t.Errorf("message = %q, want %q", msg, "GET /users 200")
+	}
+}
+
+// TestEntryFilterKeepsPanic 测试 panic 级别的日志不会被丢弃.
+func TestEntryFilterKeepsPanic(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	dropAll := func(zapcore.Entry, []zapcore.Field) bool { return false }
+	logger := zap.New(newFilterCore(inner, []EntryFilter{dropAll}, nopSyncer{}))
+
+	func() {
+		defer func() { _ = recover() }()
+		logger.Panic("must be kept")
+	}()
+
+	if n := logs.Len(); n != 1 {
+		t.Errorf("got %d entries, want 1", n)
+	}
+}
+
+// nopSyncer 是丢弃所有写入的 zapcore.WriteSyncer.
+type nopSyncer struct{}
+
+func (nopSyncer) Write(p []byte) (int, error) { return len(p), nil }
+func (nopSyncer) Sync() error                 { return nil }
@@ -0,0 +1,77 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// forceDebugKey 是在 context 中标记强制记录 debug 日志的键.
+const forceDebugKey = contextKey("forceDebug")
+
+// ContextWithForceDebug 返回标记了是否强制记录该请求所有日志的新 context，
+// 例如请求头要求"调试这个请求"时使用.
+// FromContext 为标记的 context 返回的 logger 忽略全局级别、按名称的级别、采样和重复日志折叠，
+// 包括 debug 在内的所有日志都会写入主输出（stdout/stderr、文件和 WithWriter 添加的输出），
+// 按级别路由的输出和远程输出仍然使用各自的级别.
+func ContextWithForceDebug(ctx context.Context, force bool) context.Context {
+	return context.WithValue(ctx, forceDebugKey, force)
+}
+
+// forceDebug 判断 context 是否被标记为强制记录 debug 日志.
+func forceDebug(ctx context.Context) bool {
+	force, _ := ctx.Value(forceDebugKey).(bool)
+	return force
+}
+
+// forceDebugMarker 是强制记录 debug 日志的字段携带的值.
+type forceDebugMarker struct{}
+
+// forceDebugField 返回切换到强制记录 debug 日志的 Core 的字段.
+// 该字段的类型为 zapcore.SkipType，Encoder 会忽略它，只有 forceCore 会识别它.
+func forceDebugField() zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: forceDebugMarker{}}
+}
+
+// forceCore 是可以切换到强制记录 debug 日志的 Core 的 zapcore.Core 包装器.
+// With 添加的字段包含 forceDebugField 时，返回带有之前所有字段的 forced，之后的日志不再经过级别和采样.
+type forceCore struct {
+	zapcore.Core
+	forced zapcore.Core
+	// fields 是通过 With 添加的字段，切换时添加到 forced，避免每次 With 都创建两份 Core
+	fields []zapcore.Field
+}
+
+// newForceCore 创建一个 forceCore.
+func newForceCore(core, forced zapcore.Core) zapcore.Core {
+	return &forceCore{Core: core, forced: forced}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *forceCore) With(fields []zapcore.Field) zapcore.Core {
+	// 避免修改其他派生 Core 共享的切片
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	for _, f := range fields {
+		if _, ok := f.Interface.(forceDebugMarker); ok && f.Type == zapcore.SkipType {
+			return c.forced.With(all)
+		}
+	}
+	return &forceCore{Core: c.Core.With(fields), forced: c.forced, fields: all}
+}
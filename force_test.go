@@ -0,0 +1,52 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestForceDebug 测试标记的请求忽略全局级别和采样，未标记的请求不受影响.
+func TestForceDebug(t *testing.T) {
+	var out bytes.Buffer
+	initStd(t, WithLevel("warn"), WithSampling(1, 0), WithOutputPaths(nil), WithWriter(&out),
+		WithFormat("json"), WithProcessFields(false), WithRedactKeys("password"))
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	FromContext(ctx).Debug("normal")
+	for i := 0; i < 3; i++ {
+		FromContext(ctx).Warn("sampled")
+	}
+
+	forced := ContextWithForceDebug(ctx, true)
+	logger := FromContext(forced).With(zap.String("password", "secret"))
+	for i := 0; i < 3; i++ {
+		logger.Debug("forced")
+	}
+	FromContext(ContextWithLogger(forced, GetLogger().With(zap.String("cached", "yes")))).Debug("cached")
+	DebugContext(forced, "global")
+	DebugContext(ctx, "unforced")
+
+	got := out.String()
+	if strings.Contains(got, "normal") || strings.Count(got, "sampled") != 1 {
+		t.Errorf("未标记的请求应该使用全局级别和采样, output = %q", got)
+	}
+	if n := strings.Count(got, `"msg":"forced","requestID":"req-1","password":"***"`); n != 3 {
+		t.Errorf("got %d forced entries, want 3 with context fields and redaction, output = %q", n, got)
+	}
+	if !strings.Contains(got, `"msg":"cached","cached":"yes"`) {
+		t.Errorf("缓存的 logger 也应该强制记录, output = %q", got)
+	}
+	if !strings.Contains(got, `"msg":"global","requestID":"req-1"`) {
+		t.Errorf("DebugContext 也应该强制记录, output = %q", got)
+	}
+	if strings.Contains(got, "unforced") {
+		t.Errorf("未标记的 DebugContext 不应该记录, output = %q", got)
+	}
+	if GetLogger().Core().Enabled(zap.DebugLevel) {
+		t.Error("强制记录不应该影响全局 logger")
+	}
+}
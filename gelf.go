@@ -0,0 +1,181 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// gelfVersion 是输出的 GELF 规范版本.
+const gelfVersion = "1.1"
+
+// gelfEncoder 是输出 GELF 1.1 json 消息的 zapcore.Encoder.
+// 日志字段作为附加字段输出，字段名自动添加 "_" 前缀，已经带有前缀的字段名保持不变.
+type gelfEncoder struct {
+	zapcore.Encoder
+	// nested 表示是否已经打开了命名空间，命名空间内的字段名不添加前缀
+	nested bool
+}
+
+// newGELFEncoder 创建 GELF 格式的 zapcore.Encoder，host 为空时使用 os.Hostname().
+// 除字段名外，时间和级别的编码方式固定为 GELF 要求的格式，其他编码方式沿用 cfg.
+func newGELFEncoder(cfg zapcore.EncoderConfig, host string) zapcore.Encoder {
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	gelfConfig := cfg
+	gelfConfig.MessageKey = "short_message"
+	gelfConfig.TimeKey = "timestamp"
+	gelfConfig.EncodeTime = gelfTimeEncoder
+	gelfConfig.LevelKey = "level"
+	gelfConfig.EncodeLevel = gelfLevelEncoder
+	gelfConfig.NameKey = gelfKey(cfg.NameKey)
+	gelfConfig.CallerKey = gelfKey(cfg.CallerKey)
+	gelfConfig.FunctionKey = gelfKey(cfg.FunctionKey)
+	if cfg.StacktraceKey != "" && cfg.StacktraceKey != zapcore.OmitKey {
+		gelfConfig.StacktraceKey = "full_message"
+	}
+
+	enc := zapcore.NewJSONEncoder(gelfConfig)
+	enc.AddString("version", gelfVersion)
+	enc.AddString("host", host)
+	return &gelfEncoder{Encoder: enc}
+}
+
+// gelfKey 返回附加字段的字段名，为字段名添加 "_" 前缀. 空字段名和 zapcore.OmitKey 保持不变.
+func gelfKey(key string) string {
+	if key == "" || key == zapcore.OmitKey || strings.HasPrefix(key, "_") {
+		return key
+	}
+	return "_" + key
+}
+
+// gelfTimeEncoder 将时间编码为带毫秒的 Unix 秒数.
+func gelfTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendFloat64(float64(t.UnixMilli()) / 1000)
+}
+
+// gelfLevelEncoder 将日志级别编码为 syslog 严重性数值.
+func gelfLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt(syslogSeverity(l))
+}
+
+// syslogSeverity 返回日志级别对应的 syslog 严重性数值.
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	case zapcore.FatalLevel:
+		return 0
+	default:
+		return 6
+	}
+}
+
+// key 返回字段在当前位置的字段名.
+func (e *gelfEncoder) key(key string) string {
+	if e.nested {
+		return key
+	}
+	return gelfKey(key)
+}
+
+// Clone 实现 zapcore.Encoder 接口.
+func (e *gelfEncoder) Clone() zapcore.Encoder {
+	return &gelfEncoder{Encoder: e.Encoder.Clone(), nested: e.nested}
+}
+
+// EncodeEntry 实现 zapcore.Encoder 接口.
+func (e *gelfEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	if !e.nested && len(fields) > 0 {
+		prefixed := make([]zapcore.Field, len(fields))
+		nested := false
+		for i, f := range fields {
+			if !nested {
+				f.Key = gelfKey(f.Key)
+			}
+			nested = nested || f.Type == zapcore.NamespaceType
+			prefixed[i] = f
+		}
+		fields = prefixed
+	}
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// OpenNamespace 实现 zapcore.ObjectEncoder 接口.
+func (e *gelfEncoder) OpenNamespace(key string) {
+	e.Encoder.OpenNamespace(e.key(key))
+	e.nested = true
+}
+
+// 以下方法实现 zapcore.ObjectEncoder 接口，为字段名添加 "_" 前缀.
+
+func (e *gelfEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	return e.Encoder.AddArray(e.key(key), arr)
+}
+
+func (e *gelfEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	return e.Encoder.AddObject(e.key(key), obj)
+}
+
+func (e *gelfEncoder) AddBinary(key string, val []byte) { e.Encoder.AddBinary(e.key(key), val) }
+func (e *gelfEncoder) AddByteString(key string, val []byte) {
+	e.Encoder.AddByteString(e.key(key), val)
+}
+func (e *gelfEncoder) AddBool(key string, val bool) { e.Encoder.AddBool(e.key(key), val) }
+func (e *gelfEncoder) AddComplex128(key string, val complex128) {
+	e.Encoder.AddComplex128(e.key(key), val)
+}
+func (e *gelfEncoder) AddComplex64(key string, val complex64) {
+	e.Encoder.AddComplex64(e.key(key), val)
+}
+func (e *gelfEncoder) AddDuration(key string, val time.Duration) {
+	e.Encoder.AddDuration(e.key(key), val)
+}
+func (e *gelfEncoder) AddFloat64(key string, val float64) { e.Encoder.AddFloat64(e.key(key), val) }
+func (e *gelfEncoder) AddFloat32(key string, val float32) { e.Encoder.AddFloat32(e.key(key), val) }
+func (e *gelfEncoder) AddInt(key string, val int)         { e.Encoder.AddInt(e.key(key), val) }
+func (e *gelfEncoder) AddInt64(key string, val int64)     { e.Encoder.AddInt64(e.key(key), val) }
+func (e *gelfEncoder) AddInt32(key string, val int32)     { e.Encoder.AddInt32(e.key(key), val) }
+func (e *gelfEncoder) AddInt16(key string, val int16)     { e.Encoder.AddInt16(e.key(key), val) }
+func (e *gelfEncoder) AddInt8(key string, val int8)       { e.Encoder.AddInt8(e.key(key), val) }
+func (e *gelfEncoder) AddString(key, val string)          { e.Encoder.AddString(e.key(key), val) }
+func (e *gelfEncoder) AddTime(key string, val time.Time)  { e.Encoder.AddTime(e.key(key), val) }
+func (e *gelfEncoder) AddUint(key string, val uint)       { e.Encoder.AddUint(e.key(key), val) }
+func (e *gelfEncoder) AddUint64(key string, val uint64)   { e.Encoder.AddUint64(e.key(key), val) }
+func (e *gelfEncoder) AddUint32(key string, val uint32)   { e.Encoder.AddUint32(e.key(key), val) }
+func (e *gelfEncoder) AddUint16(key string, val uint16)   { e.Encoder.AddUint16(e.key(key), val) }
+func (e *gelfEncoder) AddUint8(key string, val uint8)     { e.Encoder.AddUint8(e.key(key), val) }
+func (e *gelfEncoder) AddUintptr(key string, val uintptr) { e.Encoder.AddUintptr(e.key(key), val) }
+
+func (e *gelfEncoder) AddReflected(key string, val interface{}) error {
+	return e.Encoder.AddReflected(e.key(key), val)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestGELFFormat 测试 gelf 格式包含必需字段，附加字段带有 "_" 前缀且不会重复添加前缀.
+func TestGELFFormat(t *testing.T) {
+	var buf bytes.Buffer
+	opts := NewOptions()
+	opts.Apply(WithFormat("gelf"), WithGELFHost("web-1"))
+	core := zapcore.NewCore(newEncoder(opts.Format, newEncoderConfig(opts), opts), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	logger := zap.New(core).Named("api").With(zap.String("service", "orders"))
+
+	logger.Warn("disk almost full", zap.Int("percent", 91), zap.String("_custom", "kept"),
+		zap.Namespace("detail"), zap.String("path", "/data"))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("output is not json: %q", buf.String())
+	}
+	want := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "disk almost full",
+		"level":         float64(4),
+		"_logger":       "api",
+		"_service":      "orders",
+		"_percent":      float64(91),
+		"_custom":       "kept",
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s = %v, want %v", k, line[k], v)
+		}
+	}
+	if ts, ok := line["timestamp"].(float64); !ok || ts < 1e9 {
+		t.Errorf("timestamp = %v, want epoch seconds", line["timestamp"])
+	}
+	if detail, ok := line["_detail"].(map[string]interface{}); !ok || detail["path"] != "/data" {
+		t.Errorf("_detail = %v, want namespace with unprefixed keys", line["_detail"])
+	}
+	if _, ok := line["__custom"]; ok {
+		t.Error("字段名不应该重复添加前缀")
+	}
+}
+
+// TestGELFDefaultHost 测试默认使用 os.Hostname() 作为 host.
+func TestGELFDefaultHost(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	opts := NewOptions()
+	out := encodeEntry(t, opts, "gelf", zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"})
+
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &line); err != nil {
+		t.Fatalf("output is not json: %q", out)
+	}
+	if line["host"] != hostname || line["level"] != float64(3) {
+		t.Errorf("host = %v, level = %v, want %s and 3", line["host"], line["level"], hostname)
+	}
+}
@@ -27,7 +27,7 @@ import (
 // 这个 Writer 会将所有写入的内容作为 Info 级别的日志记录.
 func GinWriter() io.Writer {
 	return &zapWriter{
-		logger: std,
+		logger: std.Load(),
 		level:  zapcore.InfoLevel,
 	}
 }
@@ -36,7 +36,7 @@ func GinWriter() io.Writer {
 // 这个 Writer 会将所有写入的内容作为 Error 级别的日志记录.
 func GinErrorWriter() io.Writer {
 	return &zapWriter{
-		logger: std,
+		logger: std.Load(),
 		level:  zapcore.ErrorLevel,
 	}
 }
@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build gin
+
+package log
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GinMiddleware 返回一个记录请求错误和 panic 的 gin 中间件.
+// 处理函数通过 c.Error 添加的错误在请求结束后逐条以 error 级别记录，panic 会被恢复并以 error 级别记录，
+// 然后返回 500. 日志使用 FromContext(c.Request.Context()) 获取的 logger，因此包含处理函数通过
+// ContextWithFields 累积的全部字段，前提是处理函数将新的 context 设置回 c.Request.
+// 这个文件需要使用 gin 构建标签编译，例如 go build -tags gin，对应的测试使用 go test -tags gin ./... 运行.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				FromContext(c.Request.Context()).Error("panic recovered",
+					zap.Any("panic", r),
+					zap.String("method", c.Request.Method),
+					zap.String("path", c.Request.URL.Path),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		logger := FromContext(c.Request.Context())
+		for _, e := range c.Errors {
+			logger.Error("gin request error",
+				zap.Error(e.Err),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Int("status", c.Writer.Status()),
+			)
+		}
+	}
+}
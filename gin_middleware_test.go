@@ -0,0 +1,57 @@
+//go:build gin
+
+package log_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+)
+
+// TestGinMiddlewareContextFields 测试错误和 panic 日志包含处理函数通过 ContextWithFields 添加的字段.
+func TestGinMiddlewareContextFields(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(log.GinMiddleware())
+	withFields := func(c *gin.Context) {
+		c.Request = c.Request.WithContext(log.ContextWithFields(c.Request.Context(), zap.String("userID", "u-42")))
+	}
+	r.GET("/error", func(c *gin.Context) {
+		withFields(c)
+		_ = c.Error(errors.New("db unavailable"))
+		c.Status(http.StatusServiceUnavailable)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		withFields(c)
+		panic("boom")
+	})
+
+	for _, path := range []string{"/error", "/panic"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code < http.StatusInternalServerError {
+			t.Errorf("%s: status = %d, want 5xx", path, rec.Code)
+		}
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		if e.ContextMap()["userID"] != "u-42" {
+			t.Errorf("%s: fields = %v, want userID", e.Message, e.ContextMap())
+		}
+	}
+	if entries[0].ContextMap()["error"] != "db unavailable" || entries[1].ContextMap()["panic"] != "boom" {
+		t.Errorf("entries = %v", entries)
+	}
+}
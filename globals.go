@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	stdlog "log"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// zapGlobalsRestore 恢复替换之前的 zap 全局 logger，为 nil 表示 zap 全局 logger 没有被替换.
+	zapGlobalsRestore func()
+	// stdLogRestore 恢复重定向之前的标准库 log 输出，为 nil 表示标准库 log 没有被重定向.
+	stdLogRestore func()
+)
+
+// ReplaceGlobals 将全局日志记录器注册为 zap 的全局 logger，使调用 zap.L() 和 zap.S() 的第三方库也使用当前的配置.
+// 之后每次调用 Init 都会重新注册新的全局日志记录器. 返回的函数恢复替换之前的 zap 全局 logger，主要用于测试.
+func ReplaceGlobals() (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	replaceZapGlobals()
+	return zapGlobalsRestore
+}
+
+// replaceZapGlobals 将 std 注册为 zap 的全局 logger，调用者需要持有 mu.
+// 只有第一次替换时保存原来的 zap 全局 logger，之后的替换只更新指向.
+func replaceZapGlobals() {
+	undo := zap.ReplaceGlobals(std.Load())
+	if zapGlobalsRestore != nil {
+		return
+	}
+	var once sync.Once
+	zapGlobalsRestore = func() {
+		once.Do(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			undo()
+			zapGlobalsRestore = nil
+		})
+	}
+}
+
+// RedirectStdLog 将标准库 log 包的输出重定向到全局日志记录器，以 info 级别记录.
+// 之后每次调用 Init 都会重定向到新的全局日志记录器. 返回的函数恢复标准库 log 原来的输出.
+func RedirectStdLog() (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	redirectStdLog()
+	return stdLogRestore
+}
+
+// redirectStdLog 将标准库 log 包的输出重定向到 std，调用者需要持有 mu.
+// 只有第一次重定向时保存标准库 log 原来的输出，之后的重定向只更新指向.
+func redirectStdLog() {
+	// zap 恢复时总是将输出设置为 os.Stderr，这里额外保存原来的输出
+	prev := stdlog.Writer()
+	undo := zap.RedirectStdLog(std.Load())
+	if stdLogRestore != nil {
+		return
+	}
+	var once sync.Once
+	stdLogRestore = func() {
+		once.Do(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			undo()
+			stdlog.SetOutput(prev)
+			stdLogRestore = nil
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package log_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestReplaceGlobals 测试 zap 全局 logger 在重新 Init 后仍然指向全局日志记录器，并且可以恢复.
+func TestReplaceGlobals(t *testing.T) {
+	defer log.Init(log.WithLevel("info"))
+	prev := zap.L()
+
+	restore := log.ReplaceGlobals()
+	if zap.L() != log.GetLogger() {
+		t.Error("zap.L() 应该是全局日志记录器")
+	}
+	log.Init(log.WithLevel("warn"))
+	if zap.L() != log.GetLogger() {
+		t.Error("重新 Init 后 zap.L() 应该指向新的全局日志记录器")
+	}
+
+	restore()
+	if zap.L() != prev {
+		t.Error("restore 应该恢复原来的 zap 全局 logger")
+	}
+	log.Init()
+	if zap.L() != prev {
+		t.Error("restore 之后 Init 不应该再替换 zap 全局 logger")
+	}
+}
+
+// TestWithReplaceZapGlobals 测试通过选项在 Init 时替换 zap 全局 logger.
+func TestWithReplaceZapGlobals(t *testing.T) {
+	defer log.Init(log.WithLevel("info"))
+	prev := zap.L()
+
+	log.Init(log.WithReplaceZapGlobals(true))
+	if zap.L() != log.GetLogger() {
+		t.Error("zap.L() 应该是全局日志记录器")
+	}
+	log.ReplaceGlobals()()
+	if zap.L() != prev {
+		t.Error("restore 应该恢复原来的 zap 全局 logger")
+	}
+}
+
+// TestRedirectStdLog 测试标准库 log 的输出在重新 Init 后仍然写入全局日志记录器，并且可以恢复.
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	stdlog.SetOutput(&buf)
+	defer stdlog.SetOutput(os.Stderr)
+
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+	restore := log.RedirectStdLog()
+	stdlog.Print("legacy message")
+
+	entries := logs.FilterMessage("legacy message").All()
+	if len(entries) != 1 || entries[0].Level != zapcore.InfoLevel {
+		t.Fatalf("entries = %v, want one info entry", entries)
+	}
+
+	log.Init(log.WithOutputPaths(nil))
+	defer log.Init(log.WithLevel("info"))
+	stdlog.Print("after init")
+	if logs.FilterMessage("after init").Len() != 0 {
+		t.Error("重新 Init 后应该重定向到新的全局日志记录器")
+	}
+
+	restore()
+	stdlog.Print("restored message")
+	if !strings.Contains(buf.String(), "restored message") {
+		t.Errorf("restore 后应该写入原来的输出: %q", buf.String())
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// goroutineCore 为每条日志添加写入日志的 goroutine ID 的 zapcore.Core 包装器.
+// Write 在调用日志方法的 goroutine 中执行，因此在 Write 中获取的就是调用者的 goroutine ID.
+type goroutineCore struct {
+	zapcore.Core
+	errOut zapcore.WriteSyncer
+}
+
+// newGoroutineCore 创建一个 goroutineCore.
+func newGoroutineCore(core zapcore.Core, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &goroutineCore{Core: core, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *goroutineCore) With(fields []zapcore.Field) zapcore.Core {
+	return &goroutineCore{Core: c.Core.With(fields), errOut: c.errOut}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *goroutineCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *goroutineCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// 避免修改调用者的切片
+	fields = append(fields[:len(fields):len(fields)], zap.Uint64("goid", goroutineID()))
+	writeThrough(c.Core, c.errOut, ent, fields)
+	return nil
+}
+
+// goroutineIDPrefix 是 runtime.Stack 输出的第一行的前缀.
+var goroutineIDPrefix = []byte("goroutine ")
+
+// goroutineID 从 runtime.Stack 输出的第一行 "goroutine N [running]:" 中解析当前 goroutine 的 ID，
+// 解析失败时返回 0.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, goroutineIDPrefix)
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
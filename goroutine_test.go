@@ -0,0 +1,34 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestGoroutineID 测试每条日志带有写入日志的 goroutine 的 ID.
+func TestGoroutineID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newGoroutineCore(core, zapcore.AddSync(nil)))
+
+	logger.Info("main")
+	done := make(chan uint64)
+	go func() {
+		logger.Info("worker")
+		done <- goroutineID()
+	}()
+	workerID := <-done
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if got, want := entries[0].ContextMap()["goid"], goroutineID(); got != want || want == 0 {
+		t.Errorf("main goid = %v, want %d", got, want)
+	}
+	if got := entries[1].ContextMap()["goid"]; got != workerID || workerID == goroutineID() {
+		t.Errorf("worker goid = %v, want %d", got, workerID)
+	}
+}
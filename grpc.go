@@ -0,0 +1,234 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zapgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// grpcRequestIDKey 是传递 requestID 的 gRPC metadata 键.
+	grpcRequestIDKey = "x-request-id"
+	// grpcTraceIDKey 是传递 traceID 的 gRPC metadata 键，未使用 OpenTelemetry 传播时使用.
+	grpcTraceIDKey = "x-trace-id"
+)
+
+// GRPCOption 是配置 gRPC 拦截器和 GRPCLogger 的函数.
+type GRPCOption func(*grpcOptions)
+
+// grpcOptions 是 gRPC 拦截器和 GRPCLogger 的配置项.
+type grpcOptions struct {
+	levels   map[codes.Code]zapcore.Level
+	minLevel zapcore.Level
+}
+
+// GRPCCodeLevel 覆盖 gRPC 状态码对应的日志级别，例如将 codes.NotFound 记录为 info.
+// 默认情况下 codes.OK 记录为 info，其他状态码记录为 error. 无效的级别会被忽略.
+func GRPCCodeLevel(code codes.Code, level string) GRPCOption {
+	return func(o *grpcOptions) {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(level)); err == nil {
+			o.levels[code] = l
+		}
+	}
+}
+
+// GRPCMinLevel 设置 GRPCLogger 记录 gRPC 内部日志的最低级别，例如设置为 warn 时忽略 gRPC 的 info 日志，
+// 只保留警告和错误. 默认只使用全局日志记录器的级别. 无效的级别会被忽略.
+func GRPCMinLevel(level string) GRPCOption {
+	return func(o *grpcOptions) {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(level)); err == nil {
+			o.minLevel = l
+		}
+	}
+}
+
+// newGRPCOptions 创建 gRPC 拦截器和 GRPCLogger 的配置项.
+func newGRPCOptions(opts []GRPCOption) *grpcOptions {
+	o := &grpcOptions{levels: make(map[codes.Code]zapcore.Level), minLevel: zapcore.DebugLevel}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// level 返回状态码对应的日志级别.
+func (o *grpcOptions) level(code codes.Code) zapcore.Level {
+	if l, ok := o.levels[code]; ok {
+		return l
+	}
+	if code == codes.OK {
+		return zapcore.InfoLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// UnaryServerInterceptor 返回一个记录一元 RPC 调用的 gRPC 服务端拦截器.
+// 它从传入的 metadata (x-request-id, x-trace-id) 中提取 requestID 和 traceID，
+// 并通过 ContextWithLogger 在 context 中缓存 FromContext 生成的 logger，供处理函数使用.
+// 调用结束后记录方法、状态码、耗时和对端地址.
+func UnaryServerInterceptor(opts ...GRPCOption) grpc.UnaryServerInterceptor {
+	o := newGRPCOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = grpcContext(ctx)
+		resp, err := handler(ctx, req)
+		o.log(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回一个记录流式 RPC 调用的 gRPC 服务端拦截器.
+// 行为与 UnaryServerInterceptor 一致，流的 Context() 会返回注入了 logger 的 context.
+func StreamServerInterceptor(opts ...GRPCOption) grpc.StreamServerInterceptor {
+	o := newGRPCOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := grpcContext(ss.Context())
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		o.log(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// log 记录一次 RPC 调用.
+func (o *grpcOptions) log(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	if ce := FromContext(ctx).Check(o.level(code), "grpc request"); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// grpcContext 从传入的 metadata 中提取 requestID 和 traceID，并缓存对应的 logger.
+func grpcContext(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(grpcRequestIDKey); len(v) > 0 && v[0] != "" {
+			ctx = ContextWithRequestID(ctx, v[0])
+		}
+		if v := md.Get(grpcTraceIDKey); len(v) > 0 && v[0] != "" {
+			ctx = ContextWithTraceID(ctx, v[0])
+		}
+	}
+	return ContextWithLogger(ctx, FromContext(ctx))
+}
+
+// serverStream 包装 grpc.ServerStream，替换其 context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回注入了 logger 的 context.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// GRPCLogger 返回一个将 gRPC 内部日志（grpclog）写入全局日志记录器的 grpclog.LoggerV2，logger 名称为 grpc.
+// gRPC 的 Info、Warning、Error、Fatal 分别记录为 info、warn、error、fatal 级别，
+// V(0) 到 V(3) 也按这个对应关系判断是否启用. 每条日志都使用调用时的全局 logger，因此 Init 重新配置后会立即生效.
+// 可以通过 GRPCMinLevel 或 SetLoggerLevel("grpc", ...) 过滤 gRPC 的 info 日志.
+func GRPCLogger(opts ...GRPCOption) grpclog.LoggerV2 {
+	o := newGRPCOptions(opts)
+	return zapgrpc.NewLogger(zap.New(&globalCore{minLevel: o.minLevel}).Named("grpc"))
+}
+
+// UseGRPCLogger 使用 GRPCLogger 替换 gRPC 内部的日志记录器.
+// grpclog.SetLoggerV2 不是线程安全的，应该在调用任何 gRPC 函数之前调用，例如在 main 函数开始时.
+func UseGRPCLogger(opts ...GRPCOption) {
+	grpclog.SetLoggerV2(GRPCLogger(opts...))
+}
+
+// globalCore 是每次调用时都转发到当前全局日志记录器的 zapcore.Core，并额外过滤低于 minLevel 的日志.
+type globalCore struct {
+	minLevel zapcore.Level
+	fields   []zapcore.Field
+	// cached 是添加了 fields 的全局日志记录器的 Core，全局日志记录器被替换后重新创建
+	cached atomic.Pointer[globalCoreCache]
+}
+
+// globalCoreCache 记录创建 core 时的全局日志记录器.
+type globalCoreCache struct {
+	logger *zap.Logger
+	core   zapcore.Core
+}
+
+// core 返回当前全局日志记录器的 Core.
+func (c *globalCore) core() zapcore.Core {
+	logger := std.Load()
+	if len(c.fields) == 0 {
+		return logger.Core()
+	}
+	if cached := c.cached.Load(); cached != nil && cached.logger == logger {
+		return cached.core
+	}
+	core := logger.Core().With(c.fields)
+	c.cached.Store(&globalCoreCache{logger: logger, core: core})
+	return core
+}
+
+// Enabled 实现 zapcore.LevelEnabler 接口.
+func (c *globalCore) Enabled(l zapcore.Level) bool {
+	return l >= c.minLevel && std.Load().Core().Enabled(l)
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *globalCore) With(fields []zapcore.Field) zapcore.Core {
+	return &globalCore{minLevel: c.minLevel, fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *globalCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.minLevel {
+		return ce
+	}
+	return c.core().Check(ent, ce)
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *globalCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(ent, fields)
+}
+
+// Sync 实现 zapcore.Core 接口.
+func (c *globalCore) Sync() error {
+	return std.Load().Sync()
+}
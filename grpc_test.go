@@ -0,0 +1,150 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryServerInterceptor 测试一元拦截器记录调用并注入带 requestID 的 logger.
+func TestUnaryServerInterceptor(t *testing.T) {
+	logger, logs := NewObserver()
+	defer SwapForTest(logger)()
+
+	interceptor := UnaryServerInterceptor(GRPCCodeLevel(codes.NotFound, "info"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/GetUser"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		FromContext(ctx).Info("in handler")
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.NotFound {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+	_, _ = interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+
+	if entries := logs.FilterMessage("in handler").All(); len(entries) != 1 || entries[0].ContextMap()["requestID"] != "req-1" {
+		t.Errorf("处理函数中的 logger 应该携带 requestID: %v", entries)
+	}
+
+	entries := logs.FilterMessage("grpc request").All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Level != zapcore.InfoLevel {
+		t.Errorf("NotFound level = %v, want info", entries[0].Level)
+	}
+	if entries[1].Level != zapcore.ErrorLevel {
+		t.Errorf("Internal level = %v, want error", entries[1].Level)
+	}
+	fields := entries[0].ContextMap()
+	if fields["method"] != info.FullMethod || fields["code"] != "NotFound" || fields["requestID"] != "req-1" {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+// fakeServerStream 是用于测试的 grpc.ServerStream.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+// TestStreamServerInterceptor 测试流式拦截器记录调用.
+func TestStreamServerInterceptor(t *testing.T) {
+	logger, logs := NewObserver()
+	defer SwapForTest(logger)()
+
+	interceptor := StreamServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-2"))
+	info := &grpc.StreamServerInfo{FullMethod: "/user.v1.UserService/Watch"}
+
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		if RequestIDFromContext(ss.Context()) != "req-2" {
+			t.Error("流的 context 应该携带 requestID")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor error: %v", err)
+	}
+
+	entries := logs.FilterMessage("grpc request").All()
+	if len(entries) != 1 || entries[0].Level != zapcore.InfoLevel {
+		t.Fatalf("entries = %v, want one info entry", entries)
+	}
+}
+
+// TestGRPCLogger 测试 gRPC 内部日志写入全局 logger，并按最低级别过滤.
+func TestGRPCLogger(t *testing.T) {
+	logger, logs := NewObserver()
+	defer SwapForTest(logger)()
+
+	glog := GRPCLogger(GRPCMinLevel("warn"))
+	glog.Info("channel created")
+	glog.Warningf("transport closed: %s", "eof")
+	glog.Errorln("connection", "failed")
+
+	if logs.FilterMessage("channel created").Len() != 0 {
+		t.Error("低于最低级别的 gRPC 日志应该被忽略")
+	}
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Message != "transport closed: eof" || e.Level != zapcore.WarnLevel || e.LoggerName != "grpc" {
+		t.Errorf("entry = %s %q from %q, want warn from grpc", e.Level, e.Message, e.LoggerName)
+	}
+	if e := entries[1]; e.Message != "connection failed" || e.Level != zapcore.ErrorLevel {
+		t.Errorf("entry = %s %q, want error", e.Level, e.Message)
+	}
+	if glog.V(0) || !glog.V(1) {
+		t.Errorf("V(0) = %v, V(1) = %v, want info disabled and warn enabled", glog.V(0), glog.V(1))
+	}
+}
+
+// countingCore 是统计 With 调用次数的 zapcore.Core 包装器.
+type countingCore struct {
+	zapcore.Core
+	with *int
+}
+
+// With 实现 zapcore.Core 接口.
+func (c countingCore) With(fields []zapcore.Field) zapcore.Core {
+	*c.with++
+	return countingCore{Core: c.Core.With(fields), with: c.with}
+}
+
+// TestGlobalCoreCache 测试带字段的 globalCore 复用添加了字段的 Core，全局日志记录器被替换后重新创建.
+func TestGlobalCoreCache(t *testing.T) {
+	var with int
+	logger, logs := NewObserver()
+	defer SwapForTest(zap.New(countingCore{Core: logger.Core(), with: &with}))()
+
+	l := zap.New(&globalCore{minLevel: zapcore.DebugLevel}).With(zap.String("k", "v"))
+	for range 3 {
+		l.Info("cached")
+	}
+	if with != 1 {
+		t.Errorf("With called %d times, want 1", with)
+	}
+
+	defer SwapForTest(zap.New(countingCore{Core: logger.Core(), with: &with}))()
+	l.Info("rebuilt")
+	if with != 2 {
+		t.Errorf("With called %d times after swap, want 2", with)
+	}
+	if n := logs.FilterField(zap.String("k", "v")).Len(); n != 4 {
+		t.Errorf("got %d entries with k=v, want 4", n)
+	}
+}
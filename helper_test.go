@@ -0,0 +1,35 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeStd 将全局 logger 替换为内存观察者，并在测试结束时恢复.
+func observeStd(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	prev := std.Swap(zap.New(core))
+	t.Cleanup(func() { std.Store(prev) })
+	return logs
+}
+
+// initStd 使用 opts 重新初始化全局 logger，并在测试结束时恢复.
+func initStd(t *testing.T, opts ...Option) {
+	t.Helper()
+	mu.Lock()
+	prev, prevOpts, prevLevel, prevStop := std.Load(), stdOpts.Load(), stdLevel.Load(), stdStop
+	mu.Unlock()
+	Init(opts...)
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		std.Store(prev)
+		stdOpts.Store(prevOpts)
+		stdLevel.Store(prevLevel)
+		stdStop = prevStop
+	})
+}
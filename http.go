@@ -0,0 +1,198 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestIDHeader 是传递 requestID 的 HTTP 请求头.
+const RequestIDHeader = "X-Request-ID"
+
+// HTTPOption 是配置 HTTPMiddleware 的函数.
+type HTTPOption func(*httpOptions)
+
+// httpOptions 是 HTTPMiddleware 的配置项.
+type httpOptions struct {
+	skipPaths map[string]bool
+	// bodyMax 是记录请求体和响应体的最大字节数，为 0 时不记录
+	bodyMax   int
+	bodyTypes []string
+}
+
+// HTTPSkipPaths 设置不记录日志的请求路径，例如健康检查 /healthz.
+// 这些请求仍会注入 requestID，只是不输出请求日志.
+func HTTPSkipPaths(paths ...string) HTTPOption {
+	return func(o *httpOptions) {
+		for _, p := range paths {
+			o.skipPaths[p] = true
+		}
+	}
+}
+
+// HTTPMiddleware 返回一个记录每个请求的 net/http 中间件.
+// 它从请求头 X-Request-ID 中读取 requestID，没有时生成一个新的，并通过 ContextWithRequestID
+// 注入请求的 context，同时写回响应头. 请求处理完成后，使用 FromContext 记录方法、路径、状态码、
+// 耗时、响应字节数和客户端 IP，因此日志中会包含 traceID 和 requestID.
+// 5xx 响应记录为 error 级别，4xx 记录为 warn 级别，其他记录为 info 级别.
+func HTTPMiddleware(next http.Handler, opts ...HTTPOption) http.Handler {
+	o := &httpOptions{skipPaths: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ctx := r.Context()
+		requestID := requestIDForRequest(r)
+		ctx = ContextWithRequestID(ctx, requestID)
+		w.Header().Set(RequestIDHeader, requestID)
+
+		var reqBody []byte
+		var reqTruncated bool
+		logBody := o.bodyMax > 0 && !o.skipPaths[r.URL.Path]
+		if logBody && bodyContentAllowed(r.Header.Get("Content-Type"), o.bodyTypes) {
+			reqBody, reqTruncated = captureRequestBody(r, o.bodyMax)
+		}
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		if logBody {
+			rw.bodyMax = o.bodyMax
+		}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		if o.skipPaths[r.URL.Path] {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rw.status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int64("bytes", rw.bytes),
+			zap.String("clientIP", clientIP(r)),
+		}
+		if logBody {
+			redactKeys := stdOpts.Load().RedactKeys
+			if f, ok := bodyField("requestBody", r.Header.Get("Content-Type"), reqBody, reqTruncated, redactKeys); ok {
+				fields = append(fields, f)
+			}
+			respType := rw.Header().Get("Content-Type")
+			if bodyContentAllowed(respType, o.bodyTypes) {
+				if f, ok := bodyField("responseBody", respType, rw.body, rw.bytes > int64(o.bodyMax), redactKeys); ok {
+					fields = append(fields, f)
+				}
+			}
+		}
+		if ce := FromContext(ctx).Check(statusLevel(rw.status), "http request"); ce != nil {
+			ce.Write(fields...)
+		}
+	})
+}
+
+// requestIDForRequest 返回请求的 requestID，依次从请求的 context 和 X-Request-ID 请求头中读取，
+// 都没有时生成一个新的.
+func requestIDForRequest(r *http.Request) string {
+	if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+		return requestID
+	}
+	if requestID := r.Header.Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+	return newRequestID()
+}
+
+// statusLevel 返回 HTTP 状态码对应的日志级别.
+// 5xx 为 error 级别，4xx 为 warn 级别，其他为 info 级别.
+func statusLevel(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// responseWriter 包装 http.ResponseWriter，记录状态码和写入的字节数.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+	// bodyMax 大于 0 时在 body 中保存响应体的前 bodyMax 个字节
+	bodyMax int
+	body    []byte
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口.
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write 实现 http.ResponseWriter 接口.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	if remaining := w.bodyMax - len(w.body); remaining > 0 {
+		w.body = append(w.body, p[:min(n, remaining)]...)
+	}
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush 实现 http.Flusher 接口.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap 返回被包装的 http.ResponseWriter，供 http.ResponseController 使用.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// clientIP 返回客户端 IP，优先使用 X-Forwarded-For 和 X-Real-IP 请求头.
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		ip, _, _ := strings.Cut(xff, ",")
+		return strings.TrimSpace(ip)
+	}
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,140 @@
+package log_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHTTPMiddleware 测试 HTTP 中间件记录请求信息并注入 requestID.
+func TestHTTPMiddleware(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	var handlerRequestID string
+	handler := log.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			return
+		}
+		handlerRequestID = log.RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}), log.HTTPSkipPaths("/healthz"))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(log.RequestIDHeader, "req-123")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if handlerRequestID != "req-123" {
+		t.Errorf("handler requestID = %q, want req-123", handlerRequestID)
+	}
+	if got := rec.Header().Get(log.RequestIDHeader); got != "req-123" {
+		t.Errorf("response %s = %q, want req-123", log.RequestIDHeader, got)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel {
+		t.Errorf("level = %v, want warn", entries[0].Level)
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]interface{}{
+		"method":    http.MethodGet,
+		"path":      "/users/1",
+		"status":    int64(http.StatusNotFound),
+		"bytes":     int64(len("not found")),
+		"clientIP":  "10.0.0.1",
+		"requestID": "req-123",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("field %s = %v, want %v", k, fields[k], v)
+		}
+	}
+	if _, ok := fields["latency"]; !ok {
+		t.Error("缺少 latency 字段")
+	}
+}
+
+// TestHTTPMiddlewareGeneratesRequestID 测试请求没有 requestID 时自动生成.
+func TestHTTPMiddlewareGeneratesRequestID(t *testing.T) {
+	logger, _ := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	handler := log.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get(log.RequestIDHeader) == "" {
+		t.Error("应该生成 requestID")
+	}
+}
+
+// TestHTTPBodyLogging 测试记录截断的请求体和响应体，处理函数仍能读取完整的请求体，json 内容被脱敏，二进制内容不被记录.
+func TestHTTPBodyLogging(t *testing.T) {
+	log.Init(log.WithOutputPaths(nil), log.WithRedactKeys("password"))
+	defer log.Init(log.WithLevel("info"))
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	var handlerBody string
+	handler := log.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		handlerBody = string(b)
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"token":"t","password":"secret"}`))
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte{0xff, 0x00, 0x01})
+		default:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("hello world, this is a long response"))
+		}
+	}), log.HTTPBodyLogging(16))
+
+	send := func(path, contentType, body string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if handlerBody != body {
+			t.Errorf("handler read %q, want %q", handlerBody, body)
+		}
+		all := logs.TakeAll()
+		if len(all) != 1 {
+			t.Fatalf("got %d entries, want 1", len(all))
+		}
+		return all[0].ContextMap()
+	}
+
+	fields := send("/text", "text/plain", "a request body longer than the cap")
+	if fields["requestBody"] != "a request body l...(truncated)" || fields["responseBody"] != "hello world, thi...(truncated)" {
+		t.Errorf("fields = %v, want truncated bodies", fields)
+	}
+
+	fields = send("/json", "application/json", `{"password":"p"}`)
+	if fields["requestBody"] != `{"password":"***"}` || fields["responseBody"] != nil {
+		t.Errorf("fields = %v, want redacted request body and no truncated json response", fields)
+	}
+
+	fields = send("/binary", "application/octet-stream", "\xff\x00")
+	if _, ok := fields["requestBody"]; ok {
+		t.Errorf("fields = %v, want no binary request body", fields)
+	}
+	if _, ok := fields["responseBody"]; ok {
+		t.Errorf("fields = %v, want no binary response body", fields)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// defaultBodyContentTypes 是没有指定内容类型时记录请求体和响应体的内容类型.
+var defaultBodyContentTypes = []string{
+	"application/json",
+	"application/x-www-form-urlencoded",
+	"application/xml",
+	"text/*",
+}
+
+// HTTPBodyLogging 设置 HTTPMiddleware 记录请求体和响应体，每个最多记录 maxBytes 字节，超出部分被截断.
+// 只记录 contentTypes 中的内容类型，支持 "text/*" 形式的通配符，为空时记录 json、表单、xml 和文本，
+// 二进制内容和不是有效 UTF-8 的内容不会被记录. 请求体读取后重新缓冲，处理函数仍然可以完整读取.
+// json 内容中与 WithRedactKeys 配置的字段名相同的字段会被脱敏，被截断而无法解析的 json 在配置了脱敏字段时不记录.
+func HTTPBodyLogging(maxBytes int, contentTypes ...string) HTTPOption {
+	return func(o *httpOptions) {
+		o.bodyMax = maxBytes
+		o.bodyTypes = defaultBodyContentTypes
+		if len(contentTypes) > 0 {
+			o.bodyTypes = contentTypes
+		}
+	}
+}
+
+// captureRequestBody 读取请求体的前 max 个字节，并将请求体替换为包含已读取内容的 Reader.
+func captureRequestBody(r *http.Request, max int) (body []byte, truncated bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false
+	}
+	head, err := io.ReadAll(io.LimitReader(r.Body, int64(max)+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil {
+		return nil, false
+	}
+	if len(head) > max {
+		return head[:max], true
+	}
+	return head, false
+}
+
+// bodyContentAllowed 判断内容类型是否在 allowed 中.
+func bodyContentAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		if prefix, ok := strings.CutSuffix(strings.ToLower(a), "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == strings.ToLower(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyField 返回记录请求体或响应体的字段，内容不是有效的 UTF-8 或者无法安全脱敏时返回 false.
+func bodyField(key, contentType string, body []byte, truncated bool, redactKeys []string) (zap.Field, bool) {
+	if len(body) == 0 || !utf8.Valid(body) {
+		return zap.Skip(), false
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if len(redactKeys) > 0 && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		if truncated {
+			return zap.Skip(), false
+		}
+		redacted, err := redactJSON(body, redactKeys)
+		if err != nil {
+			return zap.Skip(), false
+		}
+		body = redacted
+	}
+	s := string(body)
+	if truncated {
+		s += "...(truncated)"
+	}
+	return zap.String(key, s), true
+}
+
+// redactJSON 将 json 中与 keys 同名的字段（不区分大小写）的值替换为 ***.
+func redactJSON(body []byte, keys []string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return json.Marshal(redactValue(v, set))
+}
+
+// redactValue 递归地脱敏 json 对象和数组中的字段.
+func redactValue(v interface{}, keys map[string]struct{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if _, ok := keys[strings.ToLower(k)]; ok {
+				v[k] = redactedValue
+			} else {
+				v[k] = redactValue(val, keys)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val, keys)
+		}
+	}
+	return v
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultHTTPSinkBatchSize  = 100
+	defaultHTTPSinkInterval   = time.Second
+	defaultHTTPSinkRetries    = 3
+	defaultHTTPSinkBackoff    = 100 * time.Millisecond
+	defaultHTTPSinkTimeout    = 10 * time.Second
+	httpSinkMaxPendingBatches = 10
+)
+
+// HTTPSinkOption 是配置 HTTP 输出的函数.
+type HTTPSinkOption func(*httpSinkConfig)
+
+// httpSinkConfig 是 HTTP 输出的配置项.
+type httpSinkConfig struct {
+	headers    http.Header
+	gzip       bool
+	batchSize  int
+	interval   time.Duration
+	maxRetries int
+	backoff    time.Duration
+	client     *http.Client
+}
+
+// HTTPSinkHeader 为每个请求添加一个 header，例如用于认证的 token.
+func HTTPSinkHeader(key, value string) HTTPSinkOption {
+	return func(c *httpSinkConfig) {
+		c.headers.Add(key, value)
+	}
+}
+
+// HTTPSinkGzip 设置是否使用 gzip 压缩请求体.
+func HTTPSinkGzip(enabled bool) HTTPSinkOption {
+	return func(c *httpSinkConfig) {
+		c.gzip = enabled
+	}
+}
+
+// HTTPSinkBatch 设置批次的大小和发送间隔，日志条数达到 size 或距上次发送超过 interval 时发送一个批次.
+// 默认每 100 条或每秒发送一次，不大于 0 的值使用默认值.
+func HTTPSinkBatch(size int, interval time.Duration) HTTPSinkOption {
+	return func(c *httpSinkConfig) {
+		if size > 0 {
+			c.batchSize = size
+		}
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// HTTPSinkRetry 设置发送失败时的最大重试次数和首次重试的等待时间，之后每次重试的等待时间翻倍.
+// 默认重试 3 次，首次等待 100ms.
+func HTTPSinkRetry(maxRetries int, backoff time.Duration) HTTPSinkOption {
+	return func(c *httpSinkConfig) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// HTTPSinkClient 设置发送请求使用的 http.Client，默认使用超时为 10 秒的客户端.
+func HTTPSinkClient(client *http.Client) HTTPSinkOption {
+	return func(c *httpSinkConfig) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// httpSink 是将日志按批次 POST 到 HTTP 端点的 zapcore.WriteSyncer.
+// 请求体是以换行分隔的 json 日志. 发送在后台进行，不会阻塞写日志的调用方;
+// 重试后仍然失败的批次会被丢弃，并将原因写入错误输出.
+type httpSink struct {
+	url    string
+	cfg    httpSinkConfig
+	errOut zapcore.WriteSyncer
+
+	mu      sync.Mutex
+	pending [][]byte
+	dropped int
+
+	sendMu sync.Mutex // 保证批次按顺序发送
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newHTTPSink 创建一个 httpSink 并启动后台发送.
+func newHTTPSink(url string, errOut zapcore.WriteSyncer, opts ...HTTPSinkOption) *httpSink {
+	cfg := httpSinkConfig{
+		headers:    make(http.Header),
+		batchSize:  defaultHTTPSinkBatchSize,
+		interval:   defaultHTTPSinkInterval,
+		maxRetries: defaultHTTPSinkRetries,
+		backoff:    defaultHTTPSinkBackoff,
+		client:     &http.Client{Timeout: defaultHTTPSinkTimeout},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	s := &httpSink{
+		url:     url,
+		cfg:     cfg,
+		errOut:  errOut,
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Write 实现 io.Writer 接口.
+// 待发送的日志超过上限时（例如端点长时间不可用），丢弃新的日志而不是无限增长.
+func (s *httpSink) Write(p []byte) (int, error) {
+	line := append([]byte(nil), p...)
+
+	s.mu.Lock()
+	if len(s.pending) >= s.cfg.batchSize*httpSinkMaxPendingBatches {
+		s.dropped++
+		s.mu.Unlock()
+		return len(p), nil
+	}
+	s.pending = append(s.pending, line)
+	full := len(s.pending) >= s.cfg.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口，同步发送所有待发送的日志.
+func (s *httpSink) Sync() error {
+	return s.flush()
+}
+
+// Close 停止后台发送并发送剩余的日志.
+func (s *httpSink) Close() error {
+	s.once.Do(func() { close(s.stopCh) })
+	<-s.done
+	return s.flush()
+}
+
+// loop 在批次写满或定时器触发时发送日志.
+func (s *httpSink) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+		_ = s.flush()
+	}
+}
+
+// flush 按批次发送所有待发送的日志.
+func (s *httpSink) flush() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	lines, dropped := s.pending, s.dropped
+	s.pending, s.dropped = nil, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.reportError(fmt.Errorf("dropped %d log lines: too many pending", dropped))
+	}
+
+	var firstErr error
+	for len(lines) > 0 {
+		n := min(len(lines), s.cfg.batchSize)
+		if err := s.send(lines[:n]); err != nil {
+			s.reportError(fmt.Errorf("dropped batch of %d log lines: %w", n, err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		lines = lines[n:]
+	}
+	return firstErr
+}
+
+// send 发送一个批次，失败时按指数退避重试.
+func (s *httpSink) send(lines [][]byte) error {
+	body, err := s.encodeBody(lines)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.cfg.backoff
+	for attempt := 0; ; attempt++ {
+		err = s.post(body)
+		if err == nil || attempt >= s.cfg.maxRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// encodeBody 将一个批次编码为请求体，启用 gzip 时进行压缩.
+func (s *httpSink) encodeBody(lines [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if s.cfg.gzip {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	for _, line := range lines {
+		if _, err := w.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// post 发送一次请求，状态码为 2xx 时视为成功.
+func (s *httpSink) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = s.cfg.headers.Clone()
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.cfg.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+// reportError 将发送失败的原因写入错误输出.
+func (s *httpSink) reportError(err error) {
+	fmt.Fprintf(s.errOut, "%v write logs to %s: %v\n", time.Now(), s.url, err)
+	_ = s.errOut.Sync()
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHTTPSink 测试日志按批次发送，并携带配置的 header 和 gzip 压缩.
+func TestHTTPSink(t *testing.T) {
+	var mu sync.Mutex
+	var batches []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("gzip.NewReader error: %v", err)
+			return
+		}
+		body, _ := io.ReadAll(gz)
+		mu.Lock()
+		batches = append(batches, string(body))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	sink := newHTTPSink(srv.URL, zapcore.AddSync(io.Discard),
+		HTTPSinkHeader("Authorization", "Bearer token"),
+		HTTPSinkGzip(true),
+		HTTPSinkBatch(2, time.Hour),
+	)
+	defer sink.Close()
+
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		_, _ = sink.Write([]byte(line))
+	}
+	if err := sink.Sync(); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(batches, "|") != "a\nb\n|c\n" {
+		t.Errorf("batches = %q", batches)
+	}
+}
+
+// TestHTTPSinkRetry 测试持续失败时按次数重试，然后丢弃批次并写入错误输出.
+func TestHTTPSinkRetry(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var errOut bytes.Buffer
+	sink := newHTTPSink(srv.URL, zapcore.AddSync(&errOut), HTTPSinkRetry(2, time.Millisecond), HTTPSinkBatch(10, time.Hour))
+	defer sink.Close()
+
+	_, _ = sink.Write([]byte("lost\n"))
+	if err := sink.Sync(); err == nil {
+		t.Error("Sync() 应该返回发送失败的错误")
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if !strings.Contains(errOut.String(), "dropped batch of 1 log lines") {
+		t.Errorf("error output = %q", errOut.String())
+	}
+}
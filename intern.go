@@ -0,0 +1,77 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"unique"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// internCore 驻留日志消息和字段键的 zapcore.Core 包装器.
+// 相同的字符串通过 unique 包共享同一份存储，被缓冲或保留的日志不会各自持有一份副本.
+type internCore struct {
+	zapcore.Core
+	errOut zapcore.WriteSyncer
+}
+
+// newInternCore 创建一个 internCore.
+func newInternCore(core zapcore.Core, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &internCore{Core: core, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *internCore) With(fields []zapcore.Field) zapcore.Core {
+	return &internCore{Core: c.Core.With(internKeys(fields)), errOut: c.errOut}
+}
+
+// Check 实现 zapcore.Core 接口.
+// 本次调用传入的字段只在 Write 中可见，因此推迟到 Write 中驻留.
+func (c *internCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *internCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = intern(ent.Message)
+	writeThrough(c.Core, c.errOut, ent, internKeys(fields))
+	return nil
+}
+
+// internKeys 返回字段键被驻留后的字段列表，不修改调用者的切片.
+func internKeys(fields []zapcore.Field) []zapcore.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	interned := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		f.Key = intern(f.Key)
+		interned[i] = f
+	}
+	return interned
+}
+
+// intern 返回与 s 相等且共享存储的字符串.
+func intern(s string) string {
+	if s == "" {
+		return s
+	}
+	return unique.Make(s).Value()
+}
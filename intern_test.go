@@ -0,0 +1,69 @@
+package log
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestStringInterning 测试驻留后的消息和字段与未驻留时渲染一致，并且共享存储.
+func TestStringInterning(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newInternCore(core, zapcore.AddSync(nil))).With(zap.String("service", "api"))
+
+	for i := 0; i < 2; i++ {
+		msg := string([]byte("request handled"))
+		logger.Info(msg, zap.Int("status", 200))
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		fields := e.ContextMap()
+		if e.Message != "request handled" || fields["service"] != "api" || fields["status"] != int64(200) {
+			t.Errorf("entry = %q %v", e.Message, fields)
+		}
+	}
+	if unsafe.StringData(entries[0].Message) != unsafe.StringData(entries[1].Message) {
+		t.Error("相同的消息应该共享存储")
+	}
+}
+
+// BenchmarkStringInterning 比较启用和禁用字符串驻留时，保留的日志占用的内存.
+// 消息每次都是新分配的字符串，模拟从外部输入解码出的重复消息.
+func BenchmarkStringInterning(b *testing.B) {
+	for _, tc := range []struct {
+		name   string
+		intern bool
+	}{{"disabled", false}, {"enabled", true}} {
+		b.Run(tc.name, func(b *testing.B) {
+			var core zapcore.Core
+			core, logs := observer.New(zapcore.DebugLevel)
+			if tc.intern {
+				core = newInternCore(core, zapcore.AddSync(nil))
+			}
+			logger := zap.New(core)
+			raw := []byte("cache miss for a frequently requested key")
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info(string(raw))
+			}
+			b.StopTimer()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.HeapAlloc-min(after.HeapAlloc, before.HeapAlloc))/float64(b.N), "retained-B/op")
+			runtime.KeepAlive(logs)
+		})
+	}
+}
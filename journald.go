@@ -0,0 +1,208 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journaldSocketPath 是 systemd-journald 原生协议的 socket 地址.
+var journaldSocketPath = "/run/systemd/journal/socket"
+
+// journaldSender 是向 journald 发送一条已经序列化的日志的接口.
+type journaldSender interface {
+	Send(data []byte) error
+	Close() error
+}
+
+// newJournaldOutputCore 创建写入 journald 的 zapcore.Core，以及关闭连接的函数.
+// 不在 systemd 下运行时（socket 不存在或平台不支持）输出一次提示到 errOut 并忽略 journald 输出，
+// 日志仍然写入其他输出.
+func newJournaldOutputCore(enab zapcore.LevelEnabler, errOut zapcore.WriteSyncer) (zapcore.Core, func() error) {
+	out, err := dialJournald(journaldSocketPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "%v dial journald: %v, journald output disabled\n", time.Now(), err)
+		return zapcore.NewNopCore(), nil
+	}
+	return &journaldCore{LevelEnabler: enab, identifier: filepath.Base(os.Args[0]), out: out}, out.Close
+}
+
+// journaldCore 是将每条日志以 journald 原生字段写入 journal 的 zapcore.Core.
+type journaldCore struct {
+	zapcore.LevelEnabler
+	identifier string
+	fields     []zapcore.Field
+	out        journaldSender
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *journaldCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *journaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+// 日志级别映射为 PRIORITY，消息写入 MESSAGE，调用位置写入 CODE_FILE、CODE_LINE 和 CODE_FUNC，
+// 其他字段的键转换为 journald 字段名，值为字符串或 json.
+func (c *journaldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var buf []byte
+	buf = appendJournaldField(buf, "PRIORITY", strconv.Itoa(journaldPriority(ent.Level)))
+	buf = appendJournaldField(buf, "MESSAGE", ent.Message)
+	buf = appendJournaldField(buf, "SYSLOG_IDENTIFIER", c.identifier)
+	if ent.LoggerName != "" {
+		buf = appendJournaldField(buf, "LOGGER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		buf = appendJournaldField(buf, "CODE_FILE", ent.Caller.File)
+		buf = appendJournaldField(buf, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		if ent.Caller.Function != "" {
+			buf = appendJournaldField(buf, "CODE_FUNC", ent.Caller.Function)
+		}
+	}
+	if ent.Stack != "" {
+		buf = appendJournaldField(buf, "STACKTRACE", ent.Stack)
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if name := journaldFieldName(k); name != "" {
+			buf = appendJournaldField(buf, name, journaldValue(enc.Fields[k]))
+		}
+	}
+	return c.out.Send(buf)
+}
+
+// Sync 实现 zapcore.Core 接口. 每条日志都会立即发送到 journald，没有需要刷新的缓冲.
+func (c *journaldCore) Sync() error {
+	return nil
+}
+
+// journaldPriority 将日志级别映射为 syslog 优先级：debug 为 7，info 为 6，warn 为 4，
+// error 为 3，dpanic 为 2，panic 为 1，fatal 为 0.
+func journaldPriority(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	case zapcore.FatalLevel:
+		return 0
+	default:
+		return 6
+	}
+}
+
+// journaldFieldName 将字段键转换为 journald 字段名：转为大写，字母、数字和下划线以外的字符替换为下划线，
+// 去掉开头的下划线（以下划线开头的字段由 journald 保留），以数字开头时添加 F 前缀，最长 64 个字符.
+// 无法转换时返回空字符串.
+func journaldFieldName(key string) string {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			b = append(b, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		case c == '_' && len(b) == 0:
+		default:
+			b = append(b, '_')
+		}
+	}
+	name := strings.TrimLeft(string(b), "_")
+	if name == "" {
+		return ""
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "F" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// journaldValue 将 MapObjectEncoder 编码后的字段值转换为字符串，对象和数组编码为 json.
+func journaldValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// appendJournaldField 按 journald 原生协议将一个字段追加到 buf.
+// 值不包含换行时写为 KEY=value，否则写为 KEY、换行、8 字节小端序的长度和原始值.
+func appendJournaldField(buf []byte, key, value string) []byte {
+	buf = append(buf, key...)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+		return append(buf, '\n')
+	}
+	buf = append(buf, '\n')
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(value)))
+	buf = append(buf, value...)
+	return append(buf, '\n')
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build linux
+
+package log
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// journaldConn 通过 unixgram socket 向 journald 发送日志.
+type journaldConn struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// dialJournald 连接 path 上的 journald socket，socket 不存在时返回错误.
+func dialJournald(path string) (journaldSender, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journaldConn{conn: conn, addr: &net.UnixAddr{Name: path, Net: "unixgram"}}, nil
+}
+
+// Send 实现 journaldSender 接口.
+// 日志超过 socket 的数据报大小限制时，写入一个已删除的临时文件并传递文件描述符，这也是 journald 协议规定的方式.
+func (j *journaldConn) Send(data []byte) error {
+	_, _, err := j.conn.WriteMsgUnix(data, nil, j.addr)
+	if err == nil || !(errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)) {
+		return err
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "journal.")
+	if err != nil {
+		if f, err = os.CreateTemp("", "journal."); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	_, _, err = j.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), j.addr)
+	return err
+}
+
+// Close 实现 journaldSender 接口.
+func (j *journaldConn) Close() error {
+	return j.conn.Close()
+}
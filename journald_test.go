@@ -0,0 +1,197 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build linux
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// listenJournald 在临时目录中监听一个模拟的 journald socket，并在测试结束时恢复 socket 地址.
+func listenJournald(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	prev := journaldSocketPath
+	journaldSocketPath = path
+	t.Cleanup(func() { journaldSocketPath = prev })
+	return conn
+}
+
+// readJournald 读取一条日志，如果日志通过文件描述符传递则读取文件内容，并按原生协议解析字段.
+func readJournald(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			t.Fatal(err)
+		}
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := os.NewFile(uintptr(fds[0]), "journal")
+		defer f.Close()
+		// 文件描述符与发送方共享偏移量，journald 从头读取
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if data, err = io.ReadAll(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		i := bytes.IndexAny(data, "=\n")
+		if i < 0 {
+			t.Fatalf("malformed journal data %q", data)
+		}
+		key := string(data[:i])
+		if data[i] == '=' {
+			end := bytes.IndexByte(data, '\n')
+			fields[key] = string(data[i+1 : end])
+			data = data[end+1:]
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[i+1:])
+		fields[key] = string(data[i+9 : i+9+int(size)])
+		data = data[i+9+int(size)+1:]
+	}
+	return fields
+}
+
+// TestJournaldOutput 测试日志级别映射为 PRIORITY，字段转换为 journald 字段.
+func TestJournaldOutput(t *testing.T) {
+	conn := listenJournald(t)
+	core, stop := newJournaldOutputCore(zapcore.DebugLevel, zapcore.AddSync(io.Discard))
+	if stop == nil {
+		t.Fatal("连接成功时应该返回关闭函数")
+	}
+	defer stop()
+	logger := zap.New(core, zap.AddCaller()).Named("api").With(zap.String("traceID", "t-1"))
+
+	logger.Warn("line1\nline2",
+		zap.Int("status", 500),
+		zap.String("_hidden", "x"),
+		zap.String("http.method", "GET"),
+		zap.Error(errors.New("boom")),
+		zap.Any("user", map[string]interface{}{"id": 1}),
+	)
+
+	fields := readJournald(t, conn)
+	want := map[string]string{
+		"PRIORITY":    "4",
+		"MESSAGE":     "line1\nline2",
+		"LOGGER":      "api",
+		"TRACEID":     "t-1",
+		"STATUS":      "500",
+		"HIDDEN":      "x",
+		"HTTP_METHOD": "GET",
+		"ERROR":       "boom",
+		"USER":        `{"id":1}`,
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %q, want %q", k, fields[k], v)
+		}
+	}
+	if !strings.HasSuffix(fields["CODE_FILE"], "journald_test.go") || fields["CODE_LINE"] == "" || fields["SYSLOG_IDENTIFIER"] == "" {
+		t.Errorf("missing caller or identifier fields: %v", fields)
+	}
+
+	logger.Debug("debug")
+	if fields := readJournald(t, conn); fields["PRIORITY"] != "7" {
+		t.Errorf("debug PRIORITY = %q, want 7", fields["PRIORITY"])
+	}
+}
+
+// TestJournaldLargeMessage 测试超过数据报大小限制的日志通过文件描述符发送.
+func TestJournaldLargeMessage(t *testing.T) {
+	conn := listenJournald(t)
+	core, stop := newJournaldOutputCore(zapcore.DebugLevel, zapcore.AddSync(io.Discard))
+	defer stop()
+
+	msg := strings.Repeat("x", 1<<20)
+	if err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: msg}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fields := readJournald(t, conn); fields["MESSAGE"] != msg {
+		t.Errorf("MESSAGE has %d bytes, want %d", len(fields["MESSAGE"]), len(msg))
+	}
+}
+
+// TestJournaldFallback 测试不在 systemd 下运行时忽略 journald 输出并提示.
+func TestJournaldFallback(t *testing.T) {
+	prev := journaldSocketPath
+	journaldSocketPath = filepath.Join(t.TempDir(), "missing.socket")
+	defer func() { journaldSocketPath = prev }()
+
+	var errOut bytes.Buffer
+	core, stop := newJournaldOutputCore(zapcore.InfoLevel, zapcore.AddSync(&errOut))
+	if stop != nil {
+		t.Error("回退时不应该返回关闭函数")
+	}
+	zap.New(core).Info("ignored")
+
+	if !strings.Contains(errOut.String(), "dial journald") || strings.Contains(errOut.String(), "ignored") {
+		t.Errorf("got %q, want only the dial error", errOut.String())
+	}
+}
+
+// TestJournaldFieldName 测试字段键到 journald 字段名的转换.
+func TestJournaldFieldName(t *testing.T) {
+	tests := map[string]string{
+		"traceID":               "TRACEID",
+		"http.status_code":      "HTTP_STATUS_CODE",
+		"__private":             "PRIVATE",
+		"2fa":                   "F2FA",
+		"___":                   "",
+		strings.Repeat("a", 70): strings.Repeat("A", 64),
+	}
+	for key, want := range tests {
+		if got := journaldFieldName(key); got != want {
+			t.Errorf("journaldFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
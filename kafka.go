@@ -0,0 +1,150 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap/zapcore"
+)
+
+// kafkaBatchTimeout 是 Kafka 生产者发送未满批次的最长等待时间.
+const kafkaBatchTimeout = 100 * time.Millisecond
+
+// messageWriter 是发送 Kafka 消息的接口，由 kafka.Writer 实现，测试中可以替换.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// newKafkaWriter 创建一个异步批量发送的 Kafka 生产者.
+// 发送失败的消息会写入 errOut. 生产者在发送时才会连接 broker，连接断开后会自动重连.
+func newKafkaWriter(brokers []string, topic string, errOut zapcore.WriteSyncer) (messageWriter, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("no kafka brokers")
+	}
+	if topic == "" {
+		return nil, errors.New("empty kafka topic")
+	}
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: kafkaBatchTimeout,
+		Async:        true,
+		Completion: func(messages []kafka.Message, err error) {
+			if err != nil {
+				fmt.Fprintf(errOut, "%v write %d log messages to kafka: %v\n", time.Now(), len(messages), err)
+				_ = errOut.Sync()
+			}
+		},
+	}, nil
+}
+
+// kafkaCore 是将每条日志作为一条消息发送到 Kafka 的 zapcore.Core.
+// 配置了 keyField 时，使用该字段的值作为消息的 key，相同 key 的消息会写入同一个分区.
+type kafkaCore struct {
+	zapcore.LevelEnabler
+	enc      zapcore.Encoder
+	out      messageWriter
+	keyField string
+	key      []byte
+}
+
+// newKafkaCore 创建一个 Kafka 输出的 zapcore.Core，以及关闭生产者并发送剩余日志的函数.
+// 无法创建生产者时，日志回退到 errOut.
+func newKafkaCore(enc zapcore.Encoder, opts *Options, enab zapcore.LevelEnabler, errOut zapcore.WriteSyncer) (zapcore.Core, func() error) {
+	w, err := newKafkaWriter(opts.KafkaBrokers, opts.KafkaTopic, errOut)
+	if err != nil {
+		fmt.Fprintf(errOut, "%v create kafka producer: %v, falling back to error output\n", time.Now(), err)
+		return zapcore.NewCore(enc, errOut, enab), nil
+	}
+	return &kafkaCore{LevelEnabler: enab, enc: enc, out: w, keyField: opts.KafkaKeyField}, w.Close
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *kafkaCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &kafkaCore{
+		LevelEnabler: c.LevelEnabler,
+		enc:          c.enc.Clone(),
+		out:          c.out,
+		keyField:     c.keyField,
+		key:          c.key,
+	}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	if key, ok := c.findKey(fields); ok {
+		clone.key = key
+	}
+	return clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *kafkaCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *kafkaCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	// 生产者异步发送，消息内容需要独立于可复用的 buffer
+	value := append([]byte(nil), buf.Bytes()...)
+	buf.Free()
+
+	key := c.key
+	if k, ok := c.findKey(fields); ok {
+		key = k
+	}
+	return c.out.WriteMessages(context.Background(), kafka.Message{Key: key, Value: value})
+}
+
+// Sync 实现 zapcore.Core 接口.
+// 生产者按批次异步发送，剩余的日志在关闭生产者时发送.
+func (c *kafkaCore) Sync() error {
+	return nil
+}
+
+// findKey 在 fields 中查找作为消息 key 的字段.
+func (c *kafkaCore) findKey(fields []zapcore.Field) ([]byte, bool) {
+	if c.keyField == "" {
+		return nil, false
+	}
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Key != c.keyField {
+			continue
+		}
+		if f.Type == zapcore.StringType {
+			return []byte(f.String), true
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return []byte(fmt.Sprint(enc.Fields[f.Key])), true
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeMessageWriter 是记录消息的 messageWriter.
+type fakeMessageWriter struct {
+	messages []kafka.Message
+}
+
+func (w *fakeMessageWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeMessageWriter) Close() error { return nil }
+
+// TestKafkaCore 测试每条日志作为一条 json 消息发送，并使用 traceID 作为消息的 key.
+func TestKafkaCore(t *testing.T) {
+	w := &fakeMessageWriter{}
+	enc := zapcore.NewJSONEncoder(newEncoderConfig(NewOptions()))
+	logger := zap.New(&kafkaCore{LevelEnabler: zapcore.InfoLevel, enc: enc, out: w, keyField: "traceID"})
+
+	logger.With(zap.String("traceID", "t-1")).Info("first")
+	logger.Info("second", zap.String("traceID", "t-2"))
+	logger.Info("third")
+	logger.Debug("filtered")
+
+	if len(w.messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(w.messages))
+	}
+	wantKeys := []string{"t-1", "t-2", ""}
+	for i, msg := range w.messages {
+		if string(msg.Key) != wantKeys[i] {
+			t.Errorf("message %d key = %q, want %q", i, msg.Key, wantKeys[i])
+		}
+		var line map[string]interface{}
+		if err := json.Unmarshal(msg.Value, &line); err != nil {
+			t.Errorf("message %d is not json: %q", i, msg.Value)
+		}
+	}
+}
+
+// TestKafkaFallback 测试无法创建生产者时日志回退到错误输出.
+func TestKafkaFallback(t *testing.T) {
+	var errOut bytes.Buffer
+	opts := NewOptions()
+	opts.Apply(WithKafkaOutput(nil, "logs"))
+	core, stop := newKafkaCore(zapcore.NewJSONEncoder(newEncoderConfig(opts)), opts, zapcore.InfoLevel, zapcore.AddSync(&errOut))
+	if stop != nil {
+		t.Error("回退时不应该返回关闭函数")
+	}
+	zap.New(core).Info("fallback message")
+
+	if !strings.Contains(errOut.String(), "no kafka brokers") || !strings.Contains(errOut.String(), "fallback message") {
+		t.Errorf("error output = %q", errOut.String())
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dynamicLevel 是可以在运行时调整的日志级别，支持按 logger 名称覆盖全局级别.
+type dynamicLevel struct {
+	level zap.AtomicLevel
+
+	mu    sync.RWMutex
+	named map[string]zapcore.Level
+
+	// boostMu 保护临时调整级别的状态，boostTimer 不为 nil 时表示正在临时调整
+	boostMu    sync.Mutex
+	boostGen   uint64
+	boostOrig  zapcore.Level
+	boostTimer *time.Timer
+}
+
+// newDynamicLevel 创建一个初始级别为 level 的 dynamicLevel.
+func newDynamicLevel(level zapcore.Level) *dynamicLevel {
+	return &dynamicLevel{level: zap.NewAtomicLevelAt(level)}
+}
+
+// Enabled 实现 zapcore.LevelEnabler 接口.
+// 只要全局级别或任意一个 logger 的级别允许，就返回 true，最终由 levelFor 按 logger 名称判断.
+func (d *dynamicLevel) Enabled(l zapcore.Level) bool {
+	if d.level.Enabled(l) {
+		return true
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, lvl := range d.named {
+		if l >= lvl {
+			return true
+		}
+	}
+	return false
+}
+
+// namedOnly 判断 l 级别是否只被按名称设置的级别启用，而未命名的根 logger 不记录该级别.
+// Enabled 在任意一个 logger 的级别允许时就返回 true，判断根 logger 时需要排除这种情况.
+func (d *dynamicLevel) namedOnly(l zapcore.Level) bool {
+	d.mu.RLock()
+	hasNamed := len(d.named) > 0
+	d.mu.RUnlock()
+	return hasNamed && l < d.levelFor("")
+}
+
+// levelFor 返回名为 name 的 logger 的有效级别.
+// 按名称的最长前缀匹配，例如为 db 设置的级别同时作用于 db.query.
+func (d *dynamicLevel) levelFor(name string) zapcore.Level {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	best, found := "", false
+	for n := range d.named {
+		if (name == n || strings.HasPrefix(name, n+".")) && (!found || len(n) > len(best)) {
+			best, found = n, true
+		}
+	}
+	if found {
+		return d.named[best]
+	}
+	return d.level.Level()
+}
+
+// setNamed 替换所有按 logger 名称设置的级别.
+func (d *dynamicLevel) setNamed(named map[string]zapcore.Level) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.named = named
+}
+
+// parseLevelRules 将 WithLevelRules 设置的规则解析为按 logger 名称的级别.
+// 名称末尾的 ".*" 会被去掉，无效的级别会被忽略.
+func parseLevelRules(rules map[string]string) map[string]zapcore.Level {
+	named := make(map[string]zapcore.Level, len(rules))
+	for name, level := range rules {
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			continue
+		}
+		named[strings.TrimSuffix(name, ".*")] = lvl
+	}
+	return named
+}
+
+// setLoggerLevel 设置名为 name 的 logger 的级别.
+func (d *dynamicLevel) setLoggerLevel(name string, level zapcore.Level) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	named := make(map[string]zapcore.Level, len(d.named)+1)
+	for n, l := range d.named {
+		named[n] = l
+	}
+	named[name] = level
+	d.named = named
+}
+
+// boost 将级别临时设置为 level，d 之后恢复为第一次临时调整之前的级别，返回提前恢复的函数.
+// 临时调整期间再次调整时，新的调整生效并重新计时，之前的调整返回的函数不再起作用.
+func (d *dynamicLevel) boost(level zapcore.Level, dur time.Duration) func() {
+	d.boostMu.Lock()
+	defer d.boostMu.Unlock()
+	if d.boostTimer == nil {
+		d.boostOrig = d.level.Level()
+	} else {
+		d.boostTimer.Stop()
+	}
+	d.boostGen++
+	gen := d.boostGen
+	d.level.SetLevel(level)
+	d.boostTimer = time.AfterFunc(dur, func() { d.endBoost(gen) })
+	return func() { d.endBoost(gen) }
+}
+
+// endBoost 在 gen 仍是最后一次临时调整时恢复之前的级别.
+func (d *dynamicLevel) endBoost(gen uint64) {
+	d.boostMu.Lock()
+	defer d.boostMu.Unlock()
+	if gen != d.boostGen || d.boostTimer == nil {
+		return
+	}
+	d.boostTimer.Stop()
+	d.boostTimer = nil
+	d.level.SetLevel(d.boostOrig)
+}
+
+// levelCore 按 logger 名称应用 dynamicLevel 的 zapcore.Core 包装器.
+type levelCore struct {
+	zapcore.Core
+	level *dynamicLevel
+}
+
+// newLevelCore 创建一个 levelCore.
+func newLevelCore(core zapcore.Core, level *dynamicLevel) zapcore.Core {
+	return &levelCore{Core: core, level: level}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.level.levelFor(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// SetLevel 在运行时调整全局 logger 的日志级别，无需重新 Init.
+func SetLevel(level string) error {
+	return Default().SetLevel(level)
+}
+
+// GetLevel 返回全局 logger 当前的日志级别.
+func GetLevel() string {
+	return Default().GetLevel()
+}
+
+// BoostLevel 将全局 logger 的日志级别临时调整为 level，d 之后自动恢复，返回提前恢复的函数.
+// 适合在排查问题时临时开启 debug 日志，避免忘记恢复.
+//
+//	cancel, err := log.BoostLevel("debug", 5*time.Minute)
+//
+// 临时调整期间再次调用时，最后一次调用生效并重新计时，恢复的仍是第一次调用之前的级别.
+// 临时调整期间通过 SetLevel 设置的级别会在恢复时被覆盖.
+func BoostLevel(level string, d time.Duration) (cancel func(), err error) {
+	return Default().BoostLevel(level, d)
+}
+
+// SetLoggerLevel 在运行时调整通过 Named 创建的 logger 的日志级别，覆盖全局级别.
+// 级别按名称前缀生效，例如为 db 设置的级别同时作用于 db.query.
+func SetLoggerLevel(name, level string) error {
+	return Default().SetLoggerLevel(name, level)
+}
+
+// currentLevel 返回全局 logger 的 dynamicLevel，不需要加锁.
+func currentLevel() *dynamicLevel {
+	return stdLevel.Load()
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSetLevel 测试运行时调整全局级别和按名称调整 logger 级别.
+func TestSetLevel(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths([]string{"stderr"}))
+
+	if std.Load().Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug 级别不应该启用")
+	}
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel error: %v", err)
+	}
+	if GetLevel() != "debug" || std.Load().Check(zapcore.DebugLevel, "x") == nil {
+		t.Errorf("SetLevel 后 debug 级别应该启用, level = %s", GetLevel())
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("无效的级别应该返回错误")
+	}
+
+	_ = SetLevel("warn")
+	if err := SetLoggerLevel("db", "debug"); err != nil {
+		t.Fatalf("SetLoggerLevel error: %v", err)
+	}
+	if Named("db").Named("query").Check(zapcore.DebugLevel, "x") == nil {
+		t.Error("db.query 应该继承 db 的 debug 级别")
+	}
+	if Named("database").Check(zapcore.InfoLevel, "x") != nil {
+		t.Error("database 不应该匹配 db 的级别")
+	}
+	if std.Load().Check(zapcore.InfoLevel, "x") != nil {
+		t.Error("全局 logger 应该保持 warn 级别")
+	}
+}
+
+// TestCheckAndIfLevel 测试只有启用的级别才会构建字段，并遵循运行时调整的级别.
+func TestCheckAndIfLevel(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths(nil))
+
+	called := false
+	IfLevel("debug", func(*zap.Logger) { called = true })
+	if called || Check("debug", "dump") != nil {
+		t.Error("debug 级别未启用时不应该构建字段")
+	}
+	if Check("verbose", "dump") != nil {
+		t.Error("无效的级别应该返回 nil")
+	}
+
+	_ = SetLevel("debug")
+	IfLevel("debug", func(*zap.Logger) { called = true })
+	if !called {
+		t.Error("SetLevel 后应该调用 fn")
+	}
+	ce := Check("debug", "dump")
+	if ce == nil {
+		t.Fatal("SetLevel 后应该返回 CheckedEntry")
+	}
+	if !strings.HasSuffix(ce.Caller.File, "level_test.go") {
+		t.Errorf("caller = %s, want level_test.go", ce.Caller.File)
+	}
+	ce.Write(zap.String("payload", "x"))
+}
+
+// TestEnabled 测试 Enabled 和 DebugEnabled 遵循运行时调整的级别.
+func TestEnabled(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths(nil))
+
+	if DebugEnabled() || Enabled("debug") || !Enabled("info") || !Enabled("error") {
+		t.Error("info 级别下只应该启用 info 及以上级别")
+	}
+	if Enabled("verbose") {
+		t.Error("无效的级别应该返回 false")
+	}
+
+	if err := SetLoggerLevel("db", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if DebugEnabled() || Enabled("debug") {
+		t.Error("只为 db 设置 debug 级别时，全局日志记录器不应该启用 debug")
+	}
+
+	_ = SetLevel("debug")
+	if !DebugEnabled() || !Enabled("debug") {
+		t.Error("SetLevel 后 debug 级别应该启用")
+	}
+}
+
+// TestEnabledWithoutLock 测试 DebugEnabled 和 Enabled 不需要获取全局锁.
+func TestEnabledWithoutLock(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths(nil))
+
+	mu.Lock()
+	defer mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = DebugEnabled()
+		_ = Enabled("info")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("DebugEnabled 和 Enabled 不应该等待全局锁")
+	}
+}
+
+// TestLevelRules 测试按 logger 名称的级别规则，最长的前缀优先，没有匹配时使用全局级别.
+func TestLevelRules(t *testing.T) {
+	initStd(t, WithLevel("warn"), WithOutputPaths(nil), WithLevelRules(map[string]string{
+		"http.*":   "info",
+		"db":       "error",
+		"db.query": "debug",
+		"cache":    "verbose",
+	}))
+
+	tests := []struct {
+		name  string
+		level zapcore.Level
+		want  bool
+	}{
+		{"http", zapcore.InfoLevel, true},
+		{"http.server", zapcore.InfoLevel, true},
+		{"http.server", zapcore.DebugLevel, false},
+		{"db", zapcore.WarnLevel, false},
+		{"db.query", zapcore.DebugLevel, true},
+		{"db.query.slow", zapcore.DebugLevel, true},
+		{"cache", zapcore.InfoLevel, false},
+		{"cache", zapcore.WarnLevel, true},
+		{"", zapcore.InfoLevel, false},
+	}
+	for _, tt := range tests {
+		if got := Named(tt.name).Check(tt.level, "x") != nil; got != tt.want {
+			t.Errorf("Named(%q).Check(%v) = %v, want %v", tt.name, tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestNamedOnly 测试区分只被按名称设置的级别启用的级别.
+func TestNamedOnly(t *testing.T) {
+	d := newDynamicLevel(zapcore.InfoLevel)
+	if d.namedOnly(zapcore.DebugLevel) {
+		t.Error("没有按名称设置的级别时 namedOnly 应该返回 false")
+	}
+	d.setNamed(map[string]zapcore.Level{"db": zapcore.DebugLevel})
+	if !d.Enabled(zapcore.DebugLevel) || !d.namedOnly(zapcore.DebugLevel) {
+		t.Error("只有 db 启用 debug 时，Enabled 应该返回 true，namedOnly 也应该返回 true")
+	}
+	if d.namedOnly(zapcore.InfoLevel) {
+		t.Error("根 logger 启用的级别 namedOnly 应该返回 false")
+	}
+}
+
+// TestBoostLevel 测试临时调整级别到期或提前取消后恢复第一次调整之前的级别，最后一次调整生效.
+func TestBoostLevel(t *testing.T) {
+	initStd(t, WithLevel("warn"), WithOutputPaths([]string{"stderr"}))
+
+	if _, err := BoostLevel("verbose", time.Minute); err == nil {
+		t.Error("BoostLevel() 应该拒绝无效的级别")
+	}
+
+	cancel, err := BoostLevel("debug", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("BoostLevel error: %v", err)
+	}
+	defer cancel()
+	if got := GetLevel(); got != "debug" {
+		t.Fatalf("GetLevel() = %q, want debug", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for GetLevel() != "warn" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := GetLevel(); got != "warn" {
+		t.Fatalf("GetLevel() = %q after the boost expired, want warn", got)
+	}
+
+	first, _ := BoostLevel("info", time.Minute)
+	second, _ := BoostLevel("debug", time.Minute)
+	first()
+	if got := GetLevel(); got != "debug" {
+		t.Errorf("GetLevel() = %q after cancelling a superseded boost, want debug", got)
+	}
+	second()
+	if got := GetLevel(); got != "warn" {
+		t.Errorf("GetLevel() = %q after cancel, want the level before the first boost", got)
+	}
+}
@@ -18,29 +18,99 @@ package log
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
-	std *zap.Logger
-	mu  sync.Mutex
+	// std 和 stdOpts 是全局日志记录器和它的配置，全局日志函数无锁读取，Init 在持有 mu 时替换
+	std     atomic.Pointer[zap.Logger]
+	stdOpts atomic.Pointer[Options]
+	// stdSkipped 缓存跳过两层调用者的全局日志记录器，供全局日志函数使用
+	stdSkipped atomic.Pointer[skippedLogger]
+	// stdDisabled 表示全局日志记录器是否被 Disable 或 LOG_DISABLE_AUTOINIT 禁用
+	stdDisabled atomic.Bool
+
+	// stdLevel 是全局日志记录器的 dynamicLevel，只在持有 mu 时替换，读取不需要加锁
+	stdLevel atomic.Pointer[dynamicLevel]
+	// stdStop 只在持有 mu 时读写
+	stdStop func() error
+	mu      sync.Mutex
 )
 
+// reinitGracePeriod 是重新初始化后停止旧日志记录器缓冲写入的等待时间，
+// 让仍在写入旧日志记录器的日志有机会进入缓冲区并被刷新.
+const reinitGracePeriod = time.Second
+
+// DisableAutoInitEnv 是禁用默认日志记录器的环境变量.
+// 设置为 1、true 等真值时，导入这个包不会创建写入 stdout 的默认日志记录器，
+// 全局日志记录器在调用 Init 之前不输出任何日志.
+const DisableAutoInitEnv = "LOG_DISABLE_AUTOINIT"
+
 // init 初始化默认的日志记录器.
 func init() {
 	// 初始化时使用默认配置
-	std = New(NewOptions())
+	opts := NewOptions()
+	stdOpts.Store(opts)
+	if disabled, _ := strconv.ParseBool(os.Getenv(DisableAutoInitEnv)); disabled {
+		std.Store(zap.NewNop())
+		stdLevel.Store(newDynamicLevel(zapcore.InfoLevel))
+		stdDisabled.Store(true)
+		return
+	}
+	logger, level, stop := newLogger(opts)
+	std.Store(logger)
+	stdSkipped.Store(newSkippedLogger(logger))
+	stdLevel.Store(level)
+	stdStop = stop
 }
 
 // New 根据给定的选项创建一个新的日志记录器.
+// New 使用 opts 的副本，不会修改 opts，同一个 Options 可以用于创建多个日志记录器.
 func New(opts *Options) *zap.Logger {
+	logger, _, _ := newLogger(opts.Clone())
+	return logger
+}
+
+// NewCore 根据给定的选项创建 New 使用的 zapcore.Core 和 zap 选项，用于与其他 Core 组合后创建自己的 *zap.Logger，例如:
+//
+//	core, zapOpts, err := log.NewCore(opts)
+//	logger := zap.New(zapcore.NewTee(myCore, core), zapOpts...)
+//
+// 与 New 一样使用 opts 的副本，不会修改 opts. opts 无效时返回 Validate 的错误.
+// 缓冲写入和远程输出等后台资源与 New 创建的日志记录器一样不会被释放，需要释放时使用 NewLogger.
+func NewCore(opts *Options) (zapcore.Core, []zap.Option, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+	core, zapOpts, _, _ := newCore(opts.Clone())
+	return core, zapOpts, nil
+}
+
+// newLogger 创建日志记录器，同时返回它使用的可动态调整的日志级别，
+// 以及停止缓冲写入并刷新剩余日志的函数.
+// 开发模式下会调整 opts 中的配置，调用者需要传入不与其他调用者共享的 Options.
+func newLogger(opts *Options) (*zap.Logger, *dynamicLevel, func() error) {
+	core, zapOpts, dl, stop := newCore(opts)
+	return zap.New(core, zapOpts...), dl, stop
+}
+
+// newCore 创建日志记录器使用的 Core 和 zap 选项，同时返回可动态调整的日志级别和停止函数，要求与 newLogger 相同.
+func newCore(opts *Options) (zapcore.Core, []zap.Option, *dynamicLevel, func() error) {
 	// 开发模式自动调整配置
 	if opts.Development {
 		if opts.Level == "info" {
@@ -59,51 +129,265 @@ func New(opts *Options) *zap.Logger {
 		// 如果解析失败，默认为 Info 级别
 		level = zapcore.InfoLevel
 	}
+	dl := newDynamicLevel(level)
+	if len(opts.LevelRules) > 0 {
+		dl.setNamed(parseLevelRules(opts.LevelRules))
+	}
 
 	// 配置 zap Encoder
-	encoderConfig := zapcore.EncoderConfig{
-		MessageKey:     "msg",
-		LevelKey:       "level",
-		TimeKey:        "ts",
-		CallerKey:      "caller",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,    // 大写的日志级别 (INFO, ERROR)
-		EncodeTime:     zapcore.ISO8601TimeEncoder,     // ISO8601 格式的时间
-		EncodeDuration: zapcore.SecondsDurationEncoder, // 持续时间以秒为单位
-		EncodeCaller:   zapcore.ShortCallerEncoder,     // 短格式的调用者路径 (package/file.go:line)
-	}
-
-	var encoder zapcore.Encoder
-	if opts.Format == "json" {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-	}
-
-	// 创建 WriteSyncer
-	ws := getWriteSyncer(opts)
+	encoderConfig := newEncoderConfig(opts)
+	encoder := newEncoder(opts.Format, encoderConfig, opts)
+
 	// 创建错误输出 WriteSyncer
 	errorWS := getErrorWriteSyncer(opts)
+	if opts.ErrorOutputFormat != "" {
+		// logger 内部错误使用指定的格式编码，调用者信息对内部错误没有意义
+		errorConfig := encoderConfig
+		errorConfig.CallerKey = zapcore.OmitKey
+		errorWS = newErrorOutputSyncer(newEncoder(opts.ErrorOutputFormat, errorConfig, opts), errorWS)
+	}
+
+	// 创建文件输出，启用缓冲写入时只缓冲文件输出，控制台输出保持无缓冲;
+	// WithWriter 添加的输出与文件输出一样不使用彩色的日志级别
+	fileWS, closeFiles := getFileWriteSyncer(opts, errorWS)
+	var stops []func() error
+	if opts.BufferSize > 0 && opts.Filename != "" {
+		buffered := &zapcore.BufferedWriteSyncer{
+			WS:            fileWS,
+			Size:          opts.BufferSize,
+			FlushInterval: opts.FlushInterval,
+		}
+		fileWS = buffered
+		stops = append(stops, buffered.Stop)
+	}
+	stops = append(stops, closeFiles)
+	if len(opts.Writers) > 0 {
+		fileWS = zapcore.NewMultiWriteSyncer(fileWS, newWritersSyncer(opts.Writers))
+	}
 
 	// 创建 Core
-	core := zapcore.NewCore(encoder, ws, level)
+	consoleWS := getConsoleWriteSyncer(opts)
+	newMainCore := func(enab zapcore.LevelEnabler) zapcore.Core {
+		if (opts.Color || autoColor(opts)) && opts.Format != "json" && opts.Format != "gelf" && opts.Format != "msgpack" {
+			// 彩色的日志级别只用于控制台输出，文件输出使用普通的 Encoder，避免 ANSI 转义码写入文件
+			colorConfig := encoderConfig
+			colorConfig.EncodeLevel = colorLevelEncoder(opts)
+			return zapcore.NewTee(
+				zapcore.NewCore(encoder.Clone(), fileWS, enab),
+				zapcore.NewCore(newEncoder(opts.Format, colorConfig, opts), consoleWS, enab),
+			)
+		}
+		return zapcore.NewCore(encoder.Clone(), zapcore.NewMultiWriteSyncer(fileWS, consoleWS), enab)
+	}
+	core := newMainCore(dl)
+
+	// 为按级别路由的输出、支持 context 的输出等额外目标创建 Core，与主 Core 组合在一起
+	cores := []zapcore.Core{core}
+	for _, lo := range opts.LevelOutputs {
+		cores = append(cores, newLevelOutputCore(encoder, lo, dl, opts))
+	}
+	opts.ring = nil
+	if opts.RingBufferSize > 0 {
+		opts.ring = newRingBuffer(opts.RingBufferSize)
+		cores = append(cores, zapcore.NewCore(encoder.Clone(), opts.ring, dl))
+	}
+	for _, sink := range opts.Sinks {
+		cores = append(cores, newSinkCore(encoderConfig, sink, dl, opts))
+	}
+	for _, sink := range opts.ContextSinks {
+		cores = append(cores, newContextCore(encoder.Clone(), sink, dl))
+	}
+	if opts.SpanEvents {
+		cores = append(cores, newSpanEventCore(dl))
+	}
+	cores = append(cores, newLevelSinkCores(encoderConfig, opts.LevelSinks, dl, opts)...)
+	// 远程输出总是使用 json 格式和不带颜色的日志级别
+	jsonConfig := encoderConfig
+	jsonConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	if opts.KafkaTopic != "" || len(opts.KafkaBrokers) > 0 {
+		kafkaCore, stop := newKafkaCore(zapcore.NewJSONEncoder(jsonConfig), opts, dl, errorWS)
+		cores = append(cores, kafkaCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if opts.HTTPOutputURL != "" {
+		sink := newHTTPSink(opts.HTTPOutputURL, errorWS, opts.HTTPOutputOptions...)
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(jsonConfig), sink, dl))
+		stops = append(stops, sink.Close)
+	}
+	if opts.NetworkAddr != "" {
+		nw := newNetworkWriter(opts.NetworkOutput, opts.NetworkAddr, newConsoleSyncer(os.Stderr), errorWS)
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(jsonConfig), nw, dl))
+		stops = append(stops, nw.Close)
+	}
+	if opts.Syslog {
+		syslogCore, stop := newSyslogOutputCore(zapcore.NewJSONEncoder(jsonConfig), opts, dl, errorWS)
+		cores = append(cores, syslogCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if opts.Journald {
+		journaldCore, stop := newJournaldOutputCore(dl, errorWS)
+		cores = append(cores, journaldCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if opts.CloudWatchGroup != "" {
+		cloudWatchConfig := jsonConfig
+		cloudWatchConfig.TimeKey = "timestamp"
+		cloudWatchConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		cloudWatchCore, stop := newCloudWatchCore(zapcore.NewJSONEncoder(cloudWatchConfig), opts, dl, errorWS)
+		cores = append(cores, cloudWatchCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if opts.SentryDSN != "" {
+		sentryCore, stop := newSentryOutputCore(opts, dl, errorWS)
+		cores = append(cores, sentryCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if opts.EventLogSource != "" {
+		eventLogCore, stop := newEventLogOutputCore(encoder.Clone(), opts.EventLogSource, dl, opts)
+		cores = append(cores, eventLogCore)
+		if stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+	if len(cores) > 1 {
+		core = zapcore.NewTee(cores...)
+	}
+	// 统计写入的日志数量，被采样和过滤丢弃的日志不会到达这里
+	core = zapcore.RegisterHooks(core, countMessage)
+	// 级别可以在运行时按 logger 名称调整
+	core = newLevelCore(core, dl)
+
+	// 启用采样时，按级别和消息对日志进行采样
+	unsampled := core
+	sampled := core
+	if opts.SamplingInitial > 0 {
+		sampled = zapcore.NewSamplerWithOptions(core, time.Second, opts.SamplingInitial, opts.SamplingThereafter, zapcore.SamplerHook(countSampled))
+	}
+	if len(opts.SamplingSchedule) > 0 {
+		sampled = newScheduledSamplerCore(core, sampled, opts.SamplingSchedule, clock(opts))
+	}
+	// 设置了 SamplingBelowLevel 时，不低于该级别的日志不经过采样
+	if opts.SamplingBelowLevel != "" && sampled != core {
+		var below zapcore.Level
+		if err := below.UnmarshalText([]byte(opts.SamplingBelowLevel)); err == nil {
+			sampled = newBelowLevelSamplerCore(sampled, core, below)
+		}
+	}
+	core = sampled
+
+	// 启用按字段值采样时，每个字段值独立计数，避免一个值的大量日志挤占其他值
+	if opts.FieldSamplingKey != "" {
+		core = newFieldSamplerCore(core, opts.FieldSamplingKey, opts.FieldSamplingInitial, opts.FieldSamplingThereafter, clock(opts), errorWS)
+	}
+
+	// 带有 NoSample 或 Sample 字段的日志不经过上面的采样
+	core = newSampleOverrideCore(core, unsampled, dl, errorWS)
+
+	// 启用折叠时，连续重复的日志只写入第一条和一条汇总
+	if opts.DedupWindow > 0 {
+		rc := newRepeatCore(core, opts.DedupWindow, errorWS)
+		core = rc
+		// 先于其他停止函数输出汇总日志，保证汇总日志能写入缓冲的输出
+		stops = append([]func() error{func() error {
+			rc.state.flushLocked()
+			return nil
+		}}, stops...)
+	}
+
+	// 被 ContextWithForceDebug 标记的请求改用不经过级别、采样和折叠的 Core，只写入主输出
+	core = newForceCore(core, zapcore.RegisterHooks(newMainCore(zapcore.DebugLevel), countMessage))
+
+	// 开发模式下按声明校验字段类型
+	if opts.Development && len(opts.FieldSchema) > 0 {
+		core = newSchemaCore(core, opts.FieldSchema, errorWS)
+	}
+
+	// 配置了 Google Cloud 项目时，将 traceID 和 spanID 改写为 Cloud Logging 的关联字段
+	if opts.StackdriverProject != "" {
+		core = newTransformCore(core, stackdriverMapper(opts.StackdriverProject), errorWS)
+	}
+
+	// 配置了字段转换函数时，在编码前改写或丢弃字段，转换函数看到的是脱敏后的值
+	if len(opts.FieldTransforms) > 0 {
+		core = newTransformCore(core, transformsMapper(opts.FieldTransforms), errorWS)
+	}
+
+	// 配置了脱敏字段时，在编码前替换敏感字段的值
+	if len(opts.RedactKeys) > 0 {
+		core = newTransformCore(core, redactMapper(opts.RedactKeys), errorWS)
+	}
+
+	// 配置了过滤函数时，在写入前丢弃不需要的日志
+	if len(opts.EntryFilters) > 0 {
+		core = newFilterCore(core, opts.EntryFilters, errorWS)
+	}
+
+	// 配置了去重策略时，在写入前去除重复的字段
+	if opts.DedupFields != "" {
+		core = newDedupCore(core, opts.DedupFields, errorWS)
+	}
+
+	// 启用 goroutine ID 时，在调用者的 goroutine 中添加 goid 字段
+	if opts.GoroutineID {
+		core = newGoroutineCore(core, errorWS)
+	}
+
+	// 启用字符串驻留时，在进入其他 Core 之前驻留消息和字段键
+	if opts.StringInterning {
+		core = newInternCore(core, errorWS)
+	}
 
 	// 构建 zap 选项
 	zapOpts := []zap.Option{
 		zap.ErrorOutput(errorWS),
+		zap.WithClock(clock(opts)),
 	}
 
 	// 根据选项添加额外的 zap 选项
 	if !opts.DisableCaller {
 		zapOpts = append(zapOpts, zap.AddCaller())
 	}
+	if opts.CallerSkip != 0 {
+		zapOpts = append(zapOpts, zap.AddCallerSkip(opts.CallerSkip))
+	}
 	if !opts.DisableStacktrace {
 		// 开发模式下，Warn 级别及以上记录堆栈；生产模式下，Error 级别及以上记录堆栈
 		stackLevel := zapcore.ErrorLevel
 		if opts.Development {
 			stackLevel = zapcore.WarnLevel
 		}
+		// 设置了 StacktraceLevel 时使用指定的级别，无效的级别被忽略
+		if opts.StacktraceLevel != "" {
+			var l zapcore.Level
+			if err := l.UnmarshalText([]byte(opts.StacktraceLevel)); err == nil {
+				stackLevel = l
+			}
+		}
 		zapOpts = append(zapOpts, zap.AddStacktrace(stackLevel))
+		opts.stacktrace = stackLevel
+	}
+	opts.errorOutput = errorWS
+
+	if len(opts.Hooks) > 0 {
+		zapOpts = append(zapOpts, zap.Hooks(opts.Hooks...))
+	}
+	if processFields(opts) {
+		zapOpts = append(zapOpts, zap.Fields(zap.String("host", hostname(opts)), zap.Int("pid", os.Getpid())))
+	}
+	if opts.MetricsRegisterer != nil {
+		if err := registerMetrics(opts.MetricsRegisterer); err != nil {
+			fmt.Fprintf(errorWS, "%v register log metrics: %v\n", time.Now(), err)
+		}
 	}
 
 	// 开发模式下添加开发选项
@@ -111,27 +395,246 @@ func New(opts *Options) *zap.Logger {
 		zapOpts = append(zapOpts, zap.Development())
 	}
 
-	// 创建 Logger
-	logger := zap.New(core, zapOpts...)
+	return core, zapOpts, dl, func() error {
+		var errs []error
+		for _, stop := range stops {
+			errs = append(errs, stop())
+		}
+		return errors.Join(errs...)
+	}
+}
 
-	return logger
+// processFields 判断是否为每条日志添加 host 和 pid 字段，未设置时只在非开发模式下添加.
+func processFields(opts *Options) bool {
+	if opts.ProcessFields != nil {
+		return *opts.ProcessFields
+	}
+	return !opts.Development
+}
+
+// clock 返回日志使用的时钟，未设置 Clock 时使用系统时钟.
+func clock(opts *Options) zapcore.Clock {
+	if opts.Clock != nil {
+		return opts.Clock
+	}
+	return zapcore.DefaultClock
+}
+
+// hostname 返回 host 字段的值，未设置 Hostname 时使用 os.Hostname.
+func hostname(opts *Options) string {
+	if opts.Hostname != "" {
+		return opts.Hostname
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+// newEncoderConfig 根据配置创建 zapcore.EncoderConfig.
+func newEncoderConfig(opts *Options) zapcore.EncoderConfig {
+	keys := DefaultEncoderKeys()
+	if opts.EncoderKeys != nil {
+		keys = *opts.EncoderKeys
+	}
+	return zapcore.EncoderConfig{
+		MessageKey:       keys.MessageKey,
+		LevelKey:         keys.LevelKey,
+		TimeKey:          keys.TimeKey,
+		NameKey:          keys.NameKey,
+		CallerKey:        keys.CallerKey,
+		StacktraceKey:    keys.StacktraceKey,
+		LineEnding:       zapcore.DefaultLineEnding,
+		ConsoleSeparator: opts.ConsoleSeparator,          // 为空时 console 格式使用制表符分隔
+		EncodeLevel:      newLevelEncoder(opts),          // 默认为大写的日志级别 (INFO, ERROR)
+		EncodeTime:       newTimeEncoder(opts),           // 默认为 ISO8601 格式的时间
+		EncodeDuration:   zapcore.SecondsDurationEncoder, // 持续时间以秒为单位
+		EncodeCaller:     zapcore.ShortCallerEncoder,     // 短格式的调用者路径 (package/file.go:line)
+	}
+}
+
+// levelEncoders 是 LevelEncoder 可选值对应的 zapcore.LevelEncoder.
+var levelEncoders = map[string]zapcore.LevelEncoder{
+	"capital":      zapcore.CapitalLevelEncoder,
+	"capitalColor": zapcore.CapitalColorLevelEncoder,
+	"lowercase":    zapcore.LowercaseLevelEncoder,
+	"color":        zapcore.LowercaseColorLevelEncoder,
+	"stackdriver":  stackdriverLevelEncoder,
+}
+
+// newLevelEncoder 根据 LevelEncoder 创建 zapcore.LevelEncoder，未设置时使用大写的日志级别.
+func newLevelEncoder(opts *Options) zapcore.LevelEncoder {
+	// 带颜色的编码方式在设置了 LevelColors 时使用自定义的颜色
+	if len(opts.LevelColors) > 0 && (opts.LevelEncoder == "capitalColor" || opts.LevelEncoder == "color") {
+		return newColorLevelEncoder(opts.LevelColors, opts.LevelEncoder == "color")
+	}
+	if enc, ok := levelEncoders[opts.LevelEncoder]; ok {
+		return enc
+	}
+	return zapcore.CapitalLevelEncoder
+}
+
+// autoColor 判断是否自动为控制台输出启用彩色的日志级别.
+// 只有在开发模式下、未指定 LevelEncoder 并且控制台输出是终端时才启用，重定向到管道或文件的输出保持无色.
+func autoColor(opts *Options) bool {
+	return autoColorPaths(opts.OutputPaths, opts)
+}
+
+// autoColorPaths 判断 paths 中的控制台输出是否自动使用彩色的日志级别，规则与 autoColor 相同.
+func autoColorPaths(paths []string, opts *Options) bool {
+	if !opts.Development || opts.LevelEncoder != "" {
+		return false
+	}
+	for _, path := range paths {
+		switch strings.ToLower(path) {
+		case "stdout":
+			if opts.stdout == nil && isTerminal(os.Stdout) {
+				return true
+			}
+		case "stderr":
+			if opts.stderr == nil && isTerminal(os.Stderr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newTimeEncoder 根据 TimeFormat 和 TimeZone 创建 zapcore.TimeEncoder.
+func newTimeEncoder(opts *Options) zapcore.TimeEncoder {
+	var layout string
+	switch opts.TimeFormat {
+	case "epoch":
+		return zapcore.EpochTimeEncoder
+	case "epochMillis":
+		return zapcore.EpochMillisTimeEncoder
+	case "epochNanos":
+		return zapcore.EpochNanosTimeEncoder
+	case "", "iso8601":
+		if opts.TimeZone == nil {
+			return zapcore.ISO8601TimeEncoder
+		}
+		layout = "2006-01-02T15:04:05.000Z0700"
+	case "iso8601millis":
+		layout = "2006-01-02T15:04:05.000Z07:00"
+	case "rfc3339":
+		layout = time.RFC3339
+	case "rfc3339nano":
+		layout = time.RFC3339Nano
+	default:
+		layout = opts.TimeFormat
+	}
+
+	loc := opts.TimeZone
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		enc.AppendString(t.Format(layout))
+	}
+}
+
+// newEncoder 根据日志格式创建 zapcore.Encoder.
+// 未知的格式使用 console 格式.
+func newEncoder(format string, cfg zapcore.EncoderConfig, opts *Options) zapcore.Encoder {
+	switch format {
+	case "json":
+		return zapcore.NewJSONEncoder(cfg)
+	case "gelf":
+		host := opts.GELFHost
+		if host == "" {
+			host = opts.Hostname
+		}
+		return newGELFEncoder(cfg, host)
+	case "msgpack":
+		return newMsgpackEncoder(cfg)
+	default:
+		return zapcore.NewConsoleEncoder(cfg)
+	}
 }
 
-// getWriteSyncer 根据配置创建 zapcore.WriteSyncer.
-func getWriteSyncer(opts *Options) zapcore.WriteSyncer {
+// getFileWriteSyncer 根据配置创建写入日志文件的 zapcore.WriteSyncer，同时返回关闭这些文件的函数.
+// Filename 使用 lumberjack 按配置轮转，OutputPaths 中 stdout 和 stderr 以外的路径以追加方式写入，不进行轮转.
+// 无法打开的文件会被跳过，原因写入错误输出.
+func getFileWriteSyncer(opts *Options, errOut zapcore.WriteSyncer) (zapcore.WriteSyncer, func() error) {
 	var writers []zapcore.WriteSyncer
+	var files []*os.File
 
-	// 如果配置了文件名，则添加文件写入器 (使用 lumberjack 进行日志轮转)
+	// 如果配置了文件名，则添加文件写入器 (使用 lumberjack 进行日志轮转，可选按时间轮转)
 	if opts.Filename != "" {
-		lumberJackLogger := &lumberjack.Logger{
-			Filename:   opts.Filename,
-			MaxSize:    opts.MaxSize,
-			MaxBackups: opts.MaxBackups,
-			MaxAge:     opts.MaxAge,
-			Compress:   opts.Compress,
+		// 预先创建目录和文件，使用配置的权限，失败时仍交给 lumberjack 在写入时重试
+		if err := createLogFile(opts.Filename, opts); err != nil {
+			fmt.Fprintf(errOut, "%v create log file: %v\n", time.Now(), err)
+			_ = errOut.Sync()
+		}
+		writers = append(writers, encryptFileSyncer(serializeFileSyncer(zapcore.AddSync(newFileWriter(opts.Filename, opts)), opts), opts))
+	}
+
+	// 使用 map 来避免重复打开同一个文件，与 Filename 相同的路径已经由 lumberjack 写入
+	seen := map[string]bool{filepath.Clean(opts.Filename): true}
+	for _, path := range opts.OutputPaths {
+		switch strings.ToLower(path) {
+		case "", "stdout", "stderr":
+			continue
+		}
+		if seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+		f, err := openLogFile(path, opts)
+		if err != nil {
+			fmt.Fprintf(errOut, "%v open log file: %v\n", time.Now(), err)
+			_ = errOut.Sync()
+			continue
+		}
+		files = append(files, f)
+		writers = append(writers, encryptFileSyncer(serializeFileSyncer(zapcore.Lock(f), opts), opts))
+	}
+
+	closeFiles := func() error {
+		var errs []error
+		for _, f := range files {
+			errs = append(errs, f.Close())
 		}
-		writers = append(writers, zapcore.AddSync(lumberJackLogger))
+		return errors.Join(errs...)
+	}
+	return zapcore.NewMultiWriteSyncer(writers...), closeFiles
+}
+
+// openLogFile 以追加方式打开日志文件，文件或所在目录不存在时使用配置的权限创建.
+func openLogFile(path string, opts *Options) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirMode(opts)); err != nil {
+		return nil, err
 	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, fileMode(opts))
+}
+
+// createLogFile 使用配置的权限创建日志文件和所在的目录，文件已经存在时不做修改.
+func createLogFile(path string, opts *Options) error {
+	f, err := openLogFile(path, opts)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// fileMode 返回新建日志文件的权限.
+func fileMode(opts *Options) os.FileMode {
+	if opts.FileMode == 0 {
+		return 0o600
+	}
+	return opts.FileMode
+}
+
+// dirMode 返回新建日志目录的权限.
+func dirMode(opts *Options) os.FileMode {
+	if opts.DirMode == 0 {
+		return 0o755
+	}
+	return opts.DirMode
+}
+
+// getConsoleWriteSyncer 根据配置创建写入控制台的 zapcore.WriteSyncer.
+func getConsoleWriteSyncer(opts *Options) zapcore.WriteSyncer {
+	var writers []zapcore.WriteSyncer
 
 	// 处理控制台输出
 	// 使用 map 来避免重复添加 stdout 或 stderr
@@ -141,10 +644,10 @@ func getWriteSyncer(opts *Options) zapcore.WriteSyncer {
 		if _, exists := consoleWriters[lowerPath]; !exists {
 			switch lowerPath {
 			case "stdout":
-				writers = append(writers, zapcore.AddSync(os.Stdout))
+				writers = append(writers, stdoutSyncer(opts))
 				consoleWriters[lowerPath] = true
 			case "stderr":
-				writers = append(writers, zapcore.AddSync(os.Stderr))
+				writers = append(writers, stderrSyncer(opts))
 				consoleWriters[lowerPath] = true
 			}
 		}
@@ -153,6 +656,103 @@ func getWriteSyncer(opts *Options) zapcore.WriteSyncer {
 	return zapcore.NewMultiWriteSyncer(writers...)
 }
 
+// newLevelOutputCore 为按级别路由的输出目标创建 zapcore.Core.
+// 只有同时满足全局级别和目标最低级别的日志才会写入该目标.
+func newLevelOutputCore(encoder zapcore.Encoder, lo LevelOutput, level zapcore.LevelEnabler, opts *Options) zapcore.Core {
+	var minLevel zapcore.Level
+	if err := minLevel.UnmarshalText([]byte(lo.MinLevel)); err != nil {
+		minLevel = zapcore.InfoLevel
+	}
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= minLevel && level.Enabled(l)
+	})
+	return zapcore.NewCore(encoder.Clone(), getPathsWriteSyncer(lo.Paths, opts), enabler)
+}
+
+// newSinkCore 为 WithSink 添加的输出目标创建使用独立格式和级别的 Core.
+// 控制台输出在启用颜色时使用彩色的日志级别，文件输出总是使用普通的日志级别.
+func newSinkCore(encoderConfig zapcore.EncoderConfig, sink Sink, level zapcore.LevelEnabler, opts *Options) zapcore.Core {
+	var minLevel zapcore.Level
+	if err := minLevel.UnmarshalText([]byte(sink.Level)); err != nil {
+		minLevel = zapcore.InfoLevel
+	}
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= minLevel && level.Enabled(l)
+	})
+	format := sink.Format
+	if format == "" {
+		format = opts.Format
+	}
+
+	var consolePaths, filePaths []string
+	for _, path := range sink.Paths {
+		if isConsolePath(path) {
+			consolePaths = append(consolePaths, path)
+		} else {
+			filePaths = append(filePaths, path)
+		}
+	}
+	consoleConfig := encoderConfig
+	if (opts.Color || autoColorPaths(consolePaths, opts)) && format != "json" && format != "gelf" && format != "msgpack" {
+		consoleConfig.EncodeLevel = colorLevelEncoder(opts)
+	}
+	return zapcore.NewTee(
+		zapcore.NewCore(newEncoder(format, encoderConfig, opts), getPathsWriteSyncer(filePaths, opts), enabler),
+		zapcore.NewCore(newEncoder(format, consoleConfig, opts), getPathsWriteSyncer(consolePaths, opts), enabler),
+	)
+}
+
+// getPathsWriteSyncer 根据路径列表创建 zapcore.WriteSyncer.
+// stdout 和 stderr 写入控制台，其他路径视为文件并沿用 Options 中的轮转配置.
+func getPathsWriteSyncer(paths []string, opts *Options) zapcore.WriteSyncer {
+	var writers []zapcore.WriteSyncer
+
+	// 使用 map 来避免重复添加同一个路径
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		lowerPath := strings.ToLower(path)
+		if path == "" || seen[lowerPath] {
+			continue
+		}
+		seen[lowerPath] = true
+		switch lowerPath {
+		case "stdout":
+			writers = append(writers, stdoutSyncer(opts))
+		case "stderr":
+			writers = append(writers, stderrSyncer(opts))
+		default:
+			writers = append(writers, encryptFileSyncer(serializeFileSyncer(zapcore.AddSync(newFileWriter(path, opts)), opts), opts))
+		}
+	}
+
+	return zapcore.NewMultiWriteSyncer(writers...)
+}
+
+// stdoutSyncer 返回 stdout 输出使用的 zapcore.WriteSyncer，CaptureOutput 捕获输出时写入内存.
+func stdoutSyncer(opts *Options) zapcore.WriteSyncer {
+	if opts.stdout != nil {
+		return zapcore.Lock(zapcore.AddSync(opts.stdout))
+	}
+	return newConsoleSyncer(os.Stdout)
+}
+
+// stderrSyncer 返回 stderr 输出使用的 zapcore.WriteSyncer，CaptureOutput 捕获输出时写入内存.
+func stderrSyncer(opts *Options) zapcore.WriteSyncer {
+	if opts.stderr != nil {
+		return zapcore.Lock(zapcore.AddSync(opts.stderr))
+	}
+	return newConsoleSyncer(os.Stderr)
+}
+
+// newWritersSyncer 将 io.Writer 列表包装为加锁写入的 zapcore.WriteSyncer.
+func newWritersSyncer(ws []io.Writer) zapcore.WriteSyncer {
+	writers := make([]zapcore.WriteSyncer, 0, len(ws))
+	for _, w := range ws {
+		writers = append(writers, zapcore.Lock(zapcore.AddSync(w)))
+	}
+	return zapcore.NewMultiWriteSyncer(writers...)
+}
+
 // getErrorWriteSyncer 根据配置创建错误日志的 zapcore.WriteSyncer.
 func getErrorWriteSyncer(opts *Options) zapcore.WriteSyncer {
 	var writers []zapcore.WriteSyncer
@@ -165,78 +765,303 @@ func getErrorWriteSyncer(opts *Options) zapcore.WriteSyncer {
 		if _, exists := consoleWriters[lowerPath]; !exists {
 			switch lowerPath {
 			case "stdout":
-				writers = append(writers, zapcore.AddSync(os.Stdout))
+				writers = append(writers, stdoutSyncer(opts))
 				consoleWriters[lowerPath] = true
 			case "stderr":
-				writers = append(writers, zapcore.AddSync(os.Stderr))
+				writers = append(writers, stderrSyncer(opts))
 				consoleWriters[lowerPath] = true
 			}
 		}
 	}
 
-	// 如果没有配置错误输出路径，默认使用 stderr
+	if len(opts.ErrorWriters) > 0 {
+		writers = append(writers, newWritersSyncer(opts.ErrorWriters))
+	}
+
+	// 如果没有配置错误输出，默认使用 stderr
 	if len(writers) == 0 {
-		writers = append(writers, zapcore.AddSync(os.Stderr))
+		writers = append(writers, stderrSyncer(opts))
 	}
 
 	return zapcore.NewMultiWriteSyncer(writers...)
 }
 
 // Init 使用给定的选项初始化或重新初始化全局日志记录器.
+// 新的日志记录器完全构建好之后才会替换全局日志记录器，替换后再刷新旧日志记录器的缓冲区，
+// 这样重新初始化期间仍在写入旧日志记录器的日志不会丢失.
 // 这个函数是线程安全的.
 func Init(opts ...Option) {
-	mu.Lock()
-	defer mu.Unlock()
 	o := NewOptions()
 	o.Apply(opts...)
-	std = New(o)
+	initOptions(o)
+}
+
+// CurrentOptions 返回全局日志记录器实际使用的配置的副本，可用于在调试接口中展示日志配置.
+// 返回的是开发模式自动调整后的值，Level 为通过 SetLevel 等调整后的当前级别.
+func CurrentOptions() Options {
+	return Default().Options()
+}
+
+// InitE 与 Init 相同，但会先使用 Options.Validate 检查配置，配置无效时返回错误，
+// 并保持全局日志记录器不变. 需要检查输出路径时使用 WithStrictPaths.
+func InitE(opts ...Option) error {
+	o := NewOptions()
+	o.Apply(opts...)
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	initOptions(o)
+	return nil
+}
+
+// Disable 将全局日志记录器替换为不输出任何日志的 logger，直到再次调用 Init.
+// 之前的全局日志记录器会被刷新并停止. 全局日志函数在禁用期间可以正常调用，只是不输出日志，
+// FromContext 也返回不输出日志的 logger，即使 context 中通过 ContextWithLogger 缓存了其他 logger.
+// 导入这个包时就需要禁用默认日志记录器时，设置环境变量 LOG_DISABLE_AUTOINIT=1.
+func Disable() {
+	setStd(Nop(), newDynamicLevel(zapcore.InfoLevel), nil, NewOptions())
+	stdDisabled.Store(true)
+}
+
+// Nop 返回一个不输出任何日志的 logger，可用于在测试中替换全局日志记录器或注入到被测代码中.
+//
+//	restore := log.SwapForTest(log.Nop())
+//	defer restore()
+func Nop() *zap.Logger {
+	return zap.NewNop()
+}
+
+// initOptions 使用 o 初始化或重新初始化全局日志记录器.
+func initOptions(o *Options) {
+	logger, level, stop := newLogger(o)
+	stdDisabled.Store(false)
+	setStd(logger, level, stop, o)
+}
+
+// setStd 替换全局日志记录器，然后刷新旧日志记录器，并在等待一段时间后停止它的缓冲写入.
+func setStd(logger *zap.Logger, level *dynamicLevel, stop func() error, o *Options) {
+	mu.Lock()
+	old, oldStop := std.Swap(logger), stdStop
+	stdSkipped.Store(newSkippedLogger(logger))
+	stdOpts.Store(o)
+	stdLevel.Store(level)
+	stdStop = stop
+	// 已经替换过 zap 全局 logger 或重定向过标准库 log 时，重新指向新的全局日志记录器
+	if o.ReplaceZapGlobals || zapGlobalsRestore != nil {
+		replaceZapGlobals()
+	}
+	if o.RedirectStdLog || stdLogRestore != nil {
+		redirectStdLog()
+	}
+	mu.Unlock()
+
+	if old != nil {
+		_ = old.Sync()
+	}
+	if oldStop != nil {
+		time.AfterFunc(reinitGracePeriod, func() { _ = oldStop() })
+	}
 }
 
 // Debug 记录一条 debug 级别的日志.
 func Debug(msg string, fields ...zap.Field) {
-	std.Debug(msg, fields...)
+	if ce := checkStd(zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // Info 记录一条 info 级别的日志.
 func Info(msg string, fields ...zap.Field) {
-	std.Info(msg, fields...)
+	if ce := checkStd(zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // Warn 记录一条 warn 级别的日志.
 func Warn(msg string, fields ...zap.Field) {
-	std.Warn(msg, fields...)
+	if ce := checkStd(zapcore.WarnLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // Error 记录一条 error 级别的日志.
 func Error(msg string, fields ...zap.Field) {
-	std.Error(msg, fields...)
+	if ce := checkStd(zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // DPanic 记录一条 dpanic 级别的日志. 在开发模式下会 panic.
 func DPanic(msg string, fields ...zap.Field) {
-	std.DPanic(msg, fields...)
+	if ce := checkStd(zapcore.DPanicLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // Panic 记录一条 panic 级别的日志，然后调用 panic().
 func Panic(msg string, fields ...zap.Field) {
-	std.Panic(msg, fields...)
+	if ce := checkStd(zapcore.PanicLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
 }
 
 // Fatal 记录一条 fatal 级别的日志，然后调用 os.Exit(1).
 func Fatal(msg string, fields ...zap.Field) {
-	std.Fatal(msg, fields...)
+	if ce := checkStd(zapcore.FatalLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// DebugContext 使用 FromContext(ctx) 返回的 logger 记录一条 debug 级别的日志.
+func DebugContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := checkContext(ctx, zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// InfoContext 使用 FromContext(ctx) 返回的 logger 记录一条 info 级别的日志，
+// 等同于 log.FromContext(ctx).Info(msg, fields...)，调用者信息指向调用 InfoContext 的位置.
+func InfoContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := checkContext(ctx, zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// WarnContext 使用 FromContext(ctx) 返回的 logger 记录一条 warn 级别的日志.
+func WarnContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := checkContext(ctx, zapcore.WarnLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// ErrorContext 使用 FromContext(ctx) 返回的 logger 记录一条 error 级别的日志.
+func ErrorContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := checkContext(ctx, zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// checkContext 检查 FromContext(ctx) 返回的 logger 是否记录 lvl 级别的日志，调用者信息指向调用 xxxContext 函数的位置.
+// 全局日志记录器没有启用 lvl 级别时不会从 context 中提取字段，
+// 但 context 被 ContextWithForceDebug 标记时仍交给 FromContext(ctx) 返回的 logger 判断.
+func checkContext(ctx context.Context, lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	if !forceDebug(ctx) && !std.Load().Core().Enabled(lvl) {
+		return nil
+	}
+	return fromContext(ctx, true).Check(lvl, msg)
+}
+
+// checkStd 检查全局日志记录器是否记录 lvl 级别的日志，调用者信息指向调用全局日志函数的位置.
+// dpanic 及以上级别总是返回 CheckedEntry，以保证 panic 和退出行为.
+func checkStd(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	logger := stdCallerSkipped()
+	if lvl < zapcore.DPanicLevel && !logger.Core().Enabled(lvl) {
+		return nil
+	}
+	return logger.Check(lvl, msg)
+}
+
+// skippedLogger 是日志记录器 base 以及跳过两层调用者的 logger.
+type skippedLogger struct {
+	base   *zap.Logger
+	logger *zap.Logger
+}
+
+// newSkippedLogger 为 base 创建跳过两层调用者的 logger.
+func newSkippedLogger(base *zap.Logger) *skippedLogger {
+	return &skippedLogger{base: base, logger: base.WithOptions(zap.AddCallerSkip(2))}
+}
+
+// stdCallerSkipped 返回跳过两层调用者的全局日志记录器，调用者信息指向调用全局日志函数的位置.
+// 全局日志记录器被直接替换（例如测试中）而缓存过期时重新创建.
+func stdCallerSkipped() *zap.Logger {
+	logger := std.Load()
+	if cached := stdSkipped.Load(); cached != nil && cached.base == logger {
+		return cached.logger
+	}
+	cached := newSkippedLogger(logger)
+	stdSkipped.Store(cached)
+	return cached.logger
+}
+
+// Check 在 level 级别的日志会被记录时返回 CheckedEntry，否则返回 nil.
+// 只有在返回值不为 nil 时才需要构建字段，适合字段构建代价较高的场景:
+//
+//	if ce := log.Check("debug", "request dump"); ce != nil {
+//		ce.Write(zap.Any("request", expensiveDump()))
+//	}
+//
+// 无效的级别返回 nil.
+func Check(level, msg string) *zapcore.CheckedEntry {
+	logger := std.Load()
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil || !logger.Core().Enabled(lvl) {
+		return nil
+	}
+	return logger.WithOptions(zap.AddCallerSkip(1)).Check(lvl, msg)
+}
+
+// IfLevel 仅当全局日志记录器启用了 level 级别时才调用 fn，fn 中可以构建代价较高的字段并记录日志.
+// 是否启用遵循运行时通过 SetLevel 等方式调整后的级别. 无效的级别不会调用 fn.
+func IfLevel(level string, fn func(l *zap.Logger)) {
+	logger := std.Load()
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil || !logger.Core().Enabled(lvl) {
+		return
+	}
+	fn(logger)
+}
+
+// Enabled 判断全局日志记录器当前是否记录 level 级别的日志，反映通过 SetLevel 等方式在运行时调整后的级别.
+// 无效的级别返回 false.
+func Enabled(level string) bool {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return false
+	}
+	return rootEnabled(lvl)
+}
+
+// DebugEnabled 判断全局日志记录器当前是否记录 debug 级别的日志，可以在 debug 关闭时避免构建代价较高的内容.
+// 只为 Named 创建的 logger 设置了 debug 级别时返回 false.
+func DebugEnabled() bool {
+	return rootEnabled(zapcore.DebugLevel)
+}
+
+// rootEnabled 判断全局日志记录器本身是否记录 lvl 级别的日志，不受按 logger 名称设置的级别影响.
+func rootEnabled(lvl zapcore.Level) bool {
+	return std.Load().Core().Enabled(lvl) && !currentLevel().namedOnly(lvl)
 }
 
 // Sync 将所有缓冲的日志条目刷新到磁盘.
 // 应用程序在退出前应调用此方法.
 func Sync() error {
-	return std.Sync()
+	return std.Load().Sync()
+}
+
+// Close 刷新全局日志记录器的日志，并停止缓冲写入的后台刷新.
+// 启用了 WithBufferedWrites 时，应在程序退出前调用 Close，避免缓冲区中的日志丢失.
+func Close() error {
+	return Default().Close()
 }
 
 // GetLogger 返回当前的全局日志记录器.
 // 这在需要传递 logger 实例而不是使用全局函数时很有用.
 func GetLogger() *zap.Logger {
-	return std
+	return std.Load()
+}
+
+// AddCallerSkip 返回一个在记录调用者时额外跳过 n 层调用的全局日志记录器副本，
+// 用于在封装了日志记录器的辅助函数中记录真实的调用位置. 与 WithCallerSkip 选项不同，它不影响全局日志记录器.
+func AddCallerSkip(n int) *zap.Logger {
+	return std.Load().WithOptions(zap.AddCallerSkip(n))
+}
+
+// Named 返回一个带有指定名称的子 logger，用于按组件（如 db, http, cache）区分日志.
+// 名称会以 logger 字段输出，多次调用以点号连接，例如 Named("http").Named("router") 的名称为 http.router.
+// FromContext 返回的 logger 同样可以通过 Named 命名.
+func Named(name string) *zap.Logger {
+	return std.Load().Named(name)
 }
 
 type contextKey string
@@ -256,14 +1081,134 @@ func ContextWithRequestID(ctx context.Context, requestID string) context.Context
 	return context.WithValue(ctx, requestIDKey, requestID)
 }
 
+// EnsureRequestID 返回 context 中的 requestID，没有时生成一个新的 requestID 并返回包含它的新 context.
+// 默认生成 UUID v4 格式的 requestID，可以通过 WithRequestIDGenerator 使用 ULID、雪花算法等其他生成方式.
+// 生成的 requestID 可以通过 RequestIDFromContext 获取，并会出现在 FromContext 返回的 logger 的日志中.
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return ctx, requestID
+	}
+	requestID := newRequestID()
+	return ContextWithRequestID(ctx, requestID), requestID
+}
+
+// newRequestID 使用 WithRequestIDGenerator 配置的生成函数生成一个 requestID，
+// 没有配置或生成函数返回空字符串时生成一个 UUID v4.
+func newRequestID() string {
+	if gen := stdOpts.Load().RequestIDGenerator; gen != nil {
+		if requestID := gen(); requestID != "" {
+			return requestID
+		}
+	}
+	return newUUID()
+}
+
+// newUUID 生成一个随机的 UUID v4.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // 版本 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 变体
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
 // FromContext 从 context 中提取 traceID 和 requestID，返回一个包含这些字段的 Logger 实例。
 // 如果上下文中没有这些值，它会返回全局的 logger。
+// 如果 context 中通过 ContextWithLogger 缓存了 logger，则返回缓存的 logger，并附加当前 span 的信息。
 // traceID 优先从 OpenTelemetry span 中提取，如果没有则从自定义 context key 中提取。
+// 通过 ContextWithForceDebug 标记的 context 返回的 logger 忽略级别和采样，记录包括 debug 在内的所有日志。
+// 通过 Disable 或 LOG_DISABLE_AUTOINIT 禁用日志时，总是返回不输出日志的全局 logger。
 func FromContext(ctx context.Context) *zap.Logger {
+	return fromContext(ctx, false)
+}
+
+// fromContext 实现 FromContext，callerSkipped 为 true 时返回跳过两层调用者的 logger，
+// 供 DebugContext 等全局函数使用，避免每次调用都复制 logger.
+func fromContext(ctx context.Context, callerSkipped bool) *zap.Logger {
+	logger := std.Load()
+	if callerSkipped {
+		logger = stdCallerSkipped()
+	}
 	if ctx == nil {
-		return std
+		return logger
 	}
 
+	// 禁用日志时不使用缓存的 logger，也不需要提取字段
+	if stdDisabled.Load() {
+		return logger
+	}
+
+	// 优先使用通过 ContextWithLogger 缓存的 logger
+	if cached, ok := loggerFromContext(ctx, callerSkipped); ok {
+		if forceDebug(ctx) {
+			return cached.With(forceDebugField())
+		}
+		return cached
+	}
+
+	return withContextFields(ctx, logger, stdOpts.Load())
+}
+
+// withContextFields 返回附加了 context 中 traceID、spanID、requestID、提取器字段和累积字段的 logger，
+// 没有需要附加的字段时直接返回 logger.
+func withContextFields(ctx context.Context, logger *zap.Logger, opts *Options) *zap.Logger {
+	traced := traceContextFields(ctx, opts)
+	var fields []zap.Field
+
+	// 启用时记录 context 截止时间的剩余时间，已经超时时为负数
+	if opts.ContextDeadlineField {
+		if deadline, ok := ctx.Deadline(); ok {
+			fields = append(fields, zap.Duration("deadlineRemaining", deadline.Sub(clock(opts).Now())))
+		}
+	}
+
+	// 追加已注册提取器返回的字段
+	fields = append(fields, extractContextFields(ctx)...)
+
+	// 追加通过 ContextWithFields 累积的字段
+	if ctxFields, ok := ctx.Value(fieldsKey).([]zap.Field); ok {
+		fields = append(fields, ctxFields...)
+	}
+
+	// 标记了强制记录 debug 日志时，切换到不经过级别和采样的 Core
+	if forceDebug(ctx) {
+		fields = append(fields, forceDebugField())
+	}
+
+	// 配置了支持 context 的输出或 span 事件时，将 context 传递给这些 Core
+	if len(opts.ContextSinks) > 0 || opts.SpanEvents {
+		fields = append(fields, contextField(ctx))
+	}
+
+	// 有追踪字段时记录添加之前的 Core，缓存的 logger 所在的 span 变化时据此重新生成追踪字段
+	if len(traced) > 0 {
+		return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newTraceFieldsCore(core, traced, fields)
+		}))
+	}
+
+	// 如果没有字段，直接返回 logger，避免不必要的 With 调用
+	if len(fields) == 0 {
+		return logger
+	}
+
+	return logger.With(fields...)
+}
+
+// traceContextFields 返回 context 中的 traceID、spanID 和 requestID 字段.
+// 设置了 TraceFieldNamespace 时这些字段放入以它命名的对象中.
+func traceContextFields(ctx context.Context, opts *Options) []zap.Field {
 	var fields []zap.Field
 
 	// 提取 traceID（优先从 OpenTelemetry span 中获取）
@@ -272,17 +1217,33 @@ func FromContext(ctx context.Context) *zap.Logger {
 		fields = append(fields, zap.String("traceID", traceID))
 	}
 
+	// 提取 OpenTelemetry spanID
+	if spanID := SpanIDFromContext(ctx); spanID != "" {
+		fields = append(fields, zap.String("spanID", spanID))
+	}
+
 	// 提取 requestID
-	if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
+	if requestID := requestIDFromContext(ctx, opts); requestID != "" {
 		fields = append(fields, zap.String("requestID", requestID))
 	}
 
-	// 如果没有字段，直接返回全局 logger，避免不必要的 With 调用
-	if len(fields) == 0 {
-		return std
+	// 配置了命名空间时，将追踪相关的字段放到一个嵌套对象中
+	if opts.TraceFieldNamespace != "" && len(fields) > 0 {
+		fields = []zap.Field{zap.Object(opts.TraceFieldNamespace, traceFields(fields))}
 	}
+	return fields
+}
 
-	return std.With(fields...)
+// traceFields 是编码为嵌套对象的追踪相关字段.
+// 使用 zap.Object 而不是 zap.Namespace，后者会把之后的所有字段都放入命名空间.
+type traceFields []zap.Field
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler 接口.
+func (fs traceFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range fs {
+		f.AddTo(enc)
+	}
+	return nil
 }
 
 // extractTraceID 从 context 中提取 traceID
@@ -307,13 +1268,36 @@ func TraceIDFromContext(ctx context.Context) string {
 	return extractTraceID(ctx)
 }
 
+// SpanIDFromContext 从 context 中的 OpenTelemetry span 提取 spanID
+// 如果 context 中没有有效的 span，返回空字符串
+func SpanIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		return sc.SpanID().String()
+	}
+	return ""
+}
+
 // RequestIDFromContext 从 context 中提取 requestID
+// 先查找 ContextWithRequestID 保存的 requestID，不存在时依次查找 WithRequestIDContextKeys 注册的 key
 func RequestIDFromContext(ctx context.Context) string {
 	if ctx == nil {
 		return ""
 	}
-	if requestID, ok := ctx.Value(requestIDKey).(string); ok {
+	return requestIDFromContext(ctx, stdOpts.Load())
+}
+
+// requestIDFromContext 先从自身的 key 中查找 requestID，不存在时依次查找 opts 中注册的其他 key.
+func requestIDFromContext(ctx context.Context, opts *Options) string {
+	if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
 		return requestID
 	}
+	for _, key := range opts.RequestIDContextKeys {
+		if requestID, ok := ctx.Value(key).(string); ok && requestID != "" {
+			return requestID
+		}
+	}
 	return ""
 }
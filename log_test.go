@@ -1,14 +1,28 @@
 package log_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-anyway/framework-log"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestDefaultLogging 测试默认日志记录器的输出.
@@ -38,6 +52,353 @@ func TestInitWithLevel(t *testing.T) {
 	defer log.Init(log.WithLevel("info"))
 }
 
+// TestInitConcurrentLogging 测试重新初始化期间并发写入的日志不会丢失.
+func TestInitConcurrentLogging(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "reinit.log")
+	opts := []log.Option{log.WithFilename(logFile), log.WithOutputPaths(nil), log.WithFormat("json")}
+	log.Init(opts...)
+	defer log.Init(log.WithLevel("info"))
+
+	const goroutines, perGoroutine = 4, 500
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				log.Info("concurrent message", zap.Int("seq", j))
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		log.Init(opts...)
+	}
+	wg.Wait()
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("读取日志文件失败: %v", err)
+	}
+	if got := strings.Count(string(content), "concurrent message"); got != goroutines*perGoroutine {
+		t.Errorf("got %d entries, want %d", got, goroutines*perGoroutine)
+	}
+}
+
+// TestInitConcurrentAccessors 测试重新初始化期间并发调用读取全局日志记录器的函数，配合 -race 检查数据竞争.
+func TestInitConcurrentAccessors(t *testing.T) {
+	opts := []log.Option{log.WithOutputPaths(nil)}
+	log.Init(opts...)
+	defer log.Init(log.WithLevel("info"))
+
+	ctx := log.ContextWithRequestID(context.Background(), "req-1")
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			log.InfoContext(ctx, "context message")
+			log.FromContext(ctx).Info("from context")
+			log.GetLogger().Info("from global")
+			log.Named("worker").Info("named")
+			_ = log.Enabled("debug")
+			_, _ = log.EnsureRequestID(context.Background())
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		log.Init(opts...)
+	}
+	close(done)
+	wg.Wait()
+}
+
+// TestBufferedWrites 测试缓冲写入在 Sync 和 Close 时刷新到文件.
+func TestBufferedWrites(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "buffered.log")
+	log.Init(log.WithFilename(logFile), log.WithOutputPaths(nil), log.WithBufferedWrites(64*1024, time.Hour))
+	defer log.Init(log.WithLevel("info"))
+
+	read := func() string {
+		content, _ := os.ReadFile(logFile)
+		return string(content)
+	}
+
+	log.Info("first buffered message")
+	if strings.Contains(read(), "first buffered message") {
+		t.Error("缓冲区未满时日志不应该写入文件")
+	}
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if !strings.Contains(read(), "first buffered message") {
+		t.Error("Sync 应该刷新缓冲区")
+	}
+
+	log.Info("second buffered message")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if !strings.Contains(read(), "second buffered message") {
+		t.Error("Close 应该刷新缓冲区")
+	}
+}
+
+// infoThroughHelper 模拟应用中封装全局日志函数的辅助函数.
+func infoThroughHelper(msg string) {
+	log.Info(msg)
+}
+
+// callerThroughHelper 模拟使用 AddCallerSkip 的辅助函数.
+func callerThroughHelper(msg string) {
+	log.AddCallerSkip(1).Info(msg)
+}
+
+// TestCallerSkip 测试调用者信息指向真实的调用位置，且禁用调用者信息时跳过层数无效.
+func TestCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	sink := log.WithLevelSink("info", log.SinkConfig{Writer: zapcore.AddSync(&buf), Format: "json"})
+	defer log.Init(log.WithLevel("info"))
+
+	callerLine := func(offset int) string {
+		_, file, line, _ := runtime.Caller(1)
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line+offset)
+	}
+	lastCaller := func() string {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		var entry map[string]interface{}
+		_ = json.Unmarshal([]byte(lines[len(lines)-1]), &entry)
+		caller, _ := entry["caller"].(string)
+		return caller
+	}
+
+	log.Init(log.WithOutputPaths(nil), sink)
+	log.Info("direct")
+	if want := callerLine(-1); !strings.HasSuffix(lastCaller(), want) {
+		t.Errorf("direct caller = %s, want %s", lastCaller(), want)
+	}
+	callerThroughHelper("runtime skip")
+	if want := callerLine(-1); !strings.HasSuffix(lastCaller(), want) {
+		t.Errorf("AddCallerSkip caller = %s, want %s", lastCaller(), want)
+	}
+
+	log.Init(log.WithOutputPaths(nil), sink, log.WithCallerSkip(1))
+	infoThroughHelper("option skip")
+	if want := callerLine(-1); !strings.HasSuffix(lastCaller(), want) {
+		t.Errorf("WithCallerSkip caller = %s, want %s", lastCaller(), want)
+	}
+
+	log.Init(log.WithOutputPaths(nil), sink, log.WithCallerSkip(1), log.WithDisableCaller(true))
+	infoThroughHelper("caller disabled")
+	if caller := lastCaller(); caller != "" {
+		t.Errorf("caller = %s, want no caller when disabled", caller)
+	}
+}
+
+// TestContextFunctions 测试 xxxContext 函数附加 context 中的字段，且调用者信息指向调用位置.
+func TestContextFunctions(t *testing.T) {
+	var buf bytes.Buffer
+	log.Init(log.WithOutputPaths(nil), log.WithLevel("info"),
+		log.WithLevelSink("debug", log.SinkConfig{Writer: zapcore.AddSync(&buf), Format: "json"}))
+	defer log.Init(log.WithLevel("info"))
+
+	ctx := log.ContextWithRequestID(context.Background(), "r-1")
+	log.DebugContext(ctx, "debug filtered")
+	log.InfoContext(ctx, "info message", zap.Int("n", 1))
+	_, file, line, _ := runtime.Caller(0)
+	log.WarnContext(ctx, "warn message")
+	log.ErrorContext(ctx, "error message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %s", len(lines), buf.String())
+	}
+	for _, l := range lines {
+		if !strings.Contains(l, `"requestID":"r-1"`) {
+			t.Errorf("line %s missing requestID", l)
+		}
+	}
+	if want := fmt.Sprintf("%s:%d", filepath.Base(file), line-1); !strings.Contains(lines[0], want) {
+		t.Errorf("line %s, want caller %s", lines[0], want)
+	}
+
+	buf.Reset()
+	ctx = log.ContextWithLogger(ctx, log.FromContext(ctx))
+	log.InfoContext(ctx, "cached logger")
+	_, file, line, _ = runtime.Caller(0)
+	if want := fmt.Sprintf("%s:%d", filepath.Base(file), line-1); !strings.Contains(buf.String(), want) {
+		t.Errorf("line %s, want caller %s", buf.String(), want)
+	}
+}
+
+// TestEnsureRequestID 测试已有的 requestID 保持不变，没有时生成 UUID v4 或使用配置的生成函数.
+func TestEnsureRequestID(t *testing.T) {
+	ctx, requestID := log.EnsureRequestID(log.ContextWithRequestID(context.Background(), "existing"))
+	if requestID != "existing" || log.RequestIDFromContext(ctx) != "existing" {
+		t.Errorf("EnsureRequestID() = %s, want existing", requestID)
+	}
+
+	ctx, requestID = log.EnsureRequestID(context.Background())
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(requestID) {
+		t.Errorf("EnsureRequestID() = %s, want UUID v4", requestID)
+	}
+	if log.RequestIDFromContext(ctx) != requestID {
+		t.Errorf("RequestIDFromContext() = %s, want %s", log.RequestIDFromContext(ctx), requestID)
+	}
+
+	var buf bytes.Buffer
+	log.Init(log.WithOutputPaths(nil), log.WithRequestIDGenerator(func() string { return "01HZX-ULID" }),
+		log.WithLevelSink("info", log.SinkConfig{Writer: zapcore.AddSync(&buf), Format: "json"}))
+	defer log.Init(log.WithLevel("info"))
+
+	ctx, requestID = log.EnsureRequestID(context.Background())
+	log.FromContext(ctx).Info("generated")
+	if requestID != "01HZX-ULID" || !strings.Contains(buf.String(), `"requestID":"01HZX-ULID"`) {
+		t.Errorf("requestID = %s, output %q, want generated ID", requestID, buf.String())
+	}
+}
+
+// TestHooks 测试回调函数对每条启用的日志按顺序调用，回调函数的错误写入错误输出且不影响日志写入.
+func TestHooks(t *testing.T) {
+	var calls []string
+	var out bytes.Buffer
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithLevel("info"),
+		log.WithLevelSink("debug", log.SinkConfig{Writer: zapcore.AddSync(&out)}),
+		log.WithHook(func(e zapcore.Entry) error {
+			calls = append(calls, "first:"+e.Level.String())
+			return errors.New("hook failed")
+		}),
+		log.WithHook(func(e zapcore.Entry) error {
+			calls = append(calls, "second:"+e.Level.String())
+			return nil
+		}),
+	)
+	defer l.Close()
+
+	var errOut bytes.Buffer
+	logger := l.WithOptions(zap.ErrorOutput(zapcore.AddSync(&errOut)))
+	logger.Debug("filtered")
+	logger.Info("counted")
+	logger.Error("counted too")
+
+	want := []string{"first:info", "second:info", "first:error", "second:error"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if strings.Count(errOut.String(), "hook failed") != 2 {
+		t.Errorf("error output = %q, want hook errors", errOut.String())
+	}
+	if !strings.Contains(out.String(), "counted too") {
+		t.Errorf("output = %q, hook errors should not stop logging", out.String())
+	}
+}
+
+// TestWriter 测试日志和内部错误写入 WithWriter 和 WithErrorWriter 添加的输出.
+func TestWriter(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithErrorOutputPaths(nil), log.WithColor(true),
+		log.WithWriter(&out), log.WithErrorWriter(&errOut),
+		log.WithHook(func(zapcore.Entry) error { return errors.New("hook failed") }),
+	)
+	defer l.Close()
+
+	l.Info("to writer")
+
+	if !strings.Contains(out.String(), "to writer") {
+		t.Errorf("output = %q, want the log entry", out.String())
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("output = %q, want no color codes", out.String())
+	}
+	if !strings.Contains(errOut.String(), "hook failed") {
+		t.Errorf("error output = %q, want the hook error", errOut.String())
+	}
+}
+
+// TestProcessFields 测试非开发模式下默认添加 host 和 pid 字段，开发模式或关闭后不添加.
+func TestProcessFields(t *testing.T) {
+	pid := fmt.Sprintf("%d", os.Getpid())
+	for _, format := range []string{"json", "console"} {
+		var out bytes.Buffer
+		l := log.NewLogger(log.WithOutputPaths(nil), log.WithFormat(format), log.WithWriter(&out),
+			log.WithHostname("web-1"))
+		l.Info("process")
+		l.Close()
+		if !strings.Contains(out.String(), "web-1") || !strings.Contains(out.String(), pid) {
+			t.Errorf("%s output = %q, want host and pid", format, out.String())
+		}
+	}
+
+	for name, opts := range map[string][]log.Option{
+		"development": {log.WithDevelopment(true)},
+		"disabled":    {log.WithProcessFields(false)},
+	} {
+		var out bytes.Buffer
+		l := log.NewLogger(append(opts, log.WithOutputPaths(nil), log.WithFormat("json"), log.WithWriter(&out))...)
+		l.Info("process")
+		l.Close()
+		if strings.Contains(out.String(), `"pid"`) || strings.Contains(out.String(), `"host"`) {
+			t.Errorf("%s output = %q, want no process fields", name, out.String())
+		}
+	}
+}
+
+// TestDisable 测试 Disable 后全局日志函数不输出日志也不会 panic，再次 Init 后恢复输出.
+func TestDisable(t *testing.T) {
+	log.Disable()
+	defer log.Init()
+
+	log.Info("disabled")
+	log.FromContext(context.Background()).Warn("disabled")
+	cached, logs := log.NewObserver()
+	log.FromContext(log.ContextWithLogger(context.Background(), cached)).Warn("disabled")
+	if logs.Len() != 0 {
+		t.Errorf("got %d entries from the cached logger, want FromContext to return the nop logger", logs.Len())
+	}
+	if log.Enabled("error") {
+		t.Error("Disable 后不应该启用任何级别")
+	}
+	if err := log.SetLevel("debug"); err != nil {
+		t.Errorf("SetLevel() error = %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	log.Init(log.WithOutputPaths(nil), log.WithWriter(&out))
+	log.Info("enabled")
+	if !strings.Contains(out.String(), "enabled") {
+		t.Errorf("output = %q, want logs after Init", out.String())
+	}
+}
+
+// TestDisableAutoInitEnv 测试设置 LOG_DISABLE_AUTOINIT 后导入包不会输出日志.
+func TestDisableAutoInitEnv(t *testing.T) {
+	if os.Getenv("LOG_TEST_AUTOINIT_CHILD") == "1" {
+		log.Info("should not be written")
+		log.Error("should not be written")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDisableAutoInitEnv$")
+	cmd.Env = append(os.Environ(), "LOG_TEST_AUTOINIT_CHILD=1", log.DisableAutoInitEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("child process failed: %v\n%s", err, out)
+	}
+	if strings.Contains(string(out), "should not be written") {
+		t.Errorf("output = %q, want no logs before Init", out)
+	}
+}
+
 // TestFileLogging 测试日志记录到文件.
 func TestFileLogging(t *testing.T) {
 	logFile := "test.log"
@@ -74,6 +435,80 @@ func TestFileLogging(t *testing.T) {
 	t.Logf("日志文件 '%s' 已成功创建，大小为 %d 字节。", logFile, info.Size())
 }
 
+// TestOutputPathsFile 测试 OutputPaths 中的文件路径以追加方式写入，不存在的目录会被创建.
+func TestOutputPathsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "app.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var errOut bytes.Buffer
+	l := log.NewLogger(log.WithErrorWriter(&errOut), log.WithErrorOutputPaths(nil),
+		log.WithOutputPaths([]string{path, path, filepath.Join(dir, "new", "other.log"), filepath.Join(blocker, "app.log")}))
+	l.Info("to file")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "existing\n") || strings.Count(string(data), "to file") != 1 {
+		t.Errorf("file = %q, want the entry appended once", data)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "new", "other.log")); err != nil || !strings.Contains(string(data), "to file") {
+		t.Errorf("other file = %q, %v, want the entry", data, err)
+	}
+	if !strings.Contains(errOut.String(), "open log file") {
+		t.Errorf("error output = %q, want the open error", errOut.String())
+	}
+}
+
+// TestFileMode 测试自动创建日志文件所在的目录，并使用配置的文件和目录权限.
+func TestFileMode(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "logs", "app.log")
+
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithFilename(filename),
+		log.WithFileMode(0o640), log.WithDirMode(0o750))
+	l.Info("to file")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]os.FileMode{filename: 0o640, filepath.Dir(filename): 0o750} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("mode of %s = %v, want %v", path, info.Mode().Perm(), want)
+		}
+	}
+
+	// 无法创建目录时，原因写入错误输出
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var errOut bytes.Buffer
+	l = log.NewLogger(log.WithOutputPaths(nil), log.WithErrorWriter(&errOut), log.WithErrorOutputPaths(nil),
+		log.WithFilename(filepath.Join(blocker, "app.log")))
+	defer l.Close()
+	if !strings.Contains(errOut.String(), "create log file") {
+		t.Errorf("error output = %q, want the mkdir error", errOut.String())
+	}
+}
+
 // TestFromContext 测试从 context 创建日志记录器.
 func TestFromContext(t *testing.T) {
 	// 创建一个父 context
@@ -230,6 +665,138 @@ func TestOptions_Apply(t *testing.T) {
 	}
 }
 
+// TestNewDoesNotModifyOptions 测试开发模式下 New 不修改传入的 Options，同一个 Options 可以重复使用.
+func TestNewDoesNotModifyOptions(t *testing.T) {
+	opts := log.NewOptions()
+	opts.Apply(log.WithDevelopment(true), log.WithDisableCaller(true), log.WithOutputPaths([]string{"stderr"}))
+
+	for i := 0; i < 2; i++ {
+		logger := log.New(opts)
+		if !logger.Core().Enabled(zapcore.DebugLevel) {
+			t.Errorf("New() #%d 开发模式下应该启用 debug 级别", i)
+		}
+		if opts.Level != "info" || !opts.DisableCaller {
+			t.Errorf("New() #%d 修改了 Options: level = %s, disableCaller = %v", i, opts.Level, opts.DisableCaller)
+		}
+	}
+}
+
+// TestOptionsClone 测试 Clone 返回的副本与原配置不共享切片、map 和指针字段.
+func TestOptionsClone(t *testing.T) {
+	opts := log.NewOptions()
+	opts.Apply(
+		log.WithOutputPaths([]string{"stdout", "app.log"}),
+		log.WithLevelOutput("error", []string{"stderr"}),
+		log.WithLevelRules(map[string]string{"db": "debug"}),
+		log.WithEncoderKeys(log.DefaultEncoderKeys()),
+		log.WithProcessFields(true),
+	)
+
+	clone := opts.Clone()
+	clone.OutputPaths[0] = "stderr"
+	clone.ErrorOutputPaths = append(clone.ErrorOutputPaths, "stdout")
+	clone.LevelOutputs[0].Paths[0] = "stdout"
+	clone.LevelRules["db"] = "error"
+	clone.EncoderKeys.MessageKey = "message"
+	*clone.ProcessFields = false
+
+	if opts.OutputPaths[0] != "stdout" || len(opts.ErrorOutputPaths) != 1 || opts.LevelOutputs[0].Paths[0] != "stderr" ||
+		opts.LevelRules["db"] != "debug" || opts.EncoderKeys.MessageKey != "msg" || !*opts.ProcessFields {
+		t.Errorf("修改副本影响了原配置: %+v", opts)
+	}
+
+	// 新增的切片和 map 字段也需要在 Clone 中复制
+	full := log.NewOptions()
+	v := reflect.ValueOf(full).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); {
+		case !v.Type().Field(i).IsExported():
+		case f.Kind() == reflect.Slice:
+			f.Set(reflect.MakeSlice(f.Type(), 1, 1))
+		case f.Kind() == reflect.Map:
+			f.Set(reflect.MakeMap(f.Type()))
+			f.SetMapIndex(reflect.Zero(f.Type().Key()), reflect.Zero(f.Type().Elem()))
+		}
+	}
+	c := reflect.ValueOf(full.Clone()).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); (f.Kind() == reflect.Slice || f.Kind() == reflect.Map) && f.CanInterface() && f.Pointer() == c.Field(i).Pointer() {
+			t.Errorf("Clone() 没有复制字段 %s", v.Type().Field(i).Name)
+		}
+	}
+}
+
+// TestOptionsValidate 测试 Options.Validate 拒绝无效的配置，StrictPaths 只在启用时检查输出路径.
+func TestOptionsValidate(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(o *log.Options)
+		wantErr bool
+	}{
+		{"default", func(o *log.Options) {}, false},
+		{"empty level", func(o *log.Options) { o.Level = "" }, true},
+		{"unknown level", func(o *log.Options) { o.Level = "verbose" }, true},
+		{"unknown format", func(o *log.Options) { o.Format = "xml" }, true},
+		{"negative max size", func(o *log.Options) { o.MaxSize = -1 }, true},
+		{"unresolvable path without strict", func(o *log.Options) {
+			o.OutputPaths = []string{filepath.Join(blocker, "app.log")}
+		}, false},
+		{"unresolvable path", func(o *log.Options) {
+			o.StrictPaths = true
+			o.OutputPaths = []string{filepath.Join(blocker, "app.log")}
+		}, true},
+		{"unresolvable filename", func(o *log.Options) {
+			o.StrictPaths = true
+			o.Filename = filepath.Join(blocker, "sub", "app.log")
+		}, true},
+		{"directory as file", func(o *log.Options) {
+			o.StrictPaths = true
+			o.OutputPaths = []string{dir}
+		}, true},
+		{"file error output path", func(o *log.Options) {
+			o.StrictPaths = true
+			o.ErrorOutputPaths = []string{filepath.Join(dir, "err.log")}
+		}, true},
+		{"creatable paths", func(o *log.Options) {
+			o.StrictPaths = true
+			o.OutputPaths = []string{"stdout", filepath.Join(dir, "new", "app.log")}
+			o.Filename = filepath.Join(dir, "app.log")
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := log.NewOptions()
+			tt.modify(o)
+			if err := o.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+		t.Errorf("Validate 不应该创建目录, stat error = %v", err)
+	}
+}
+
+// TestInitE 测试 InitE 在配置无效时返回错误并保持全局日志记录器不变.
+func TestInitE(t *testing.T) {
+	if err := log.InitE(log.WithOutputPaths(nil)); err != nil {
+		t.Fatalf("InitE() error = %v", err)
+	}
+	before := log.GetLogger()
+	if err := log.InitE(log.WithFormat("xml")); err == nil {
+		t.Error("InitE() 应该拒绝未知的日志格式")
+	}
+	if log.GetLogger() != before {
+		t.Error("配置无效时不应该替换全局日志记录器")
+	}
+}
+
 // TestWithInvalidLevel 测试无效日志级别时的行为.
 func TestWithInvalidLevel(t *testing.T) {
 	opts := log.NewOptions()
@@ -308,6 +875,30 @@ func TestTraceIDFromContext(t *testing.T) {
 	}
 }
 
+// TestSpanIDFromContext 测试从 OpenTelemetry span 获取 spanID，并由 FromContext 附加到日志中.
+func TestSpanIDFromContext(t *testing.T) {
+	if spanID := log.SpanIDFromContext(context.Background()); spanID != "" {
+		t.Errorf("SpanIDFromContext(empty context) = %s, want empty", spanID)
+	}
+
+	spanID := trace.SpanID{0xa, 0xb}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  spanID,
+	}))
+	if got := log.SpanIDFromContext(ctx); got != spanID.String() {
+		t.Errorf("SpanIDFromContext() = %s, want %s", got, spanID)
+	}
+
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+	log.FromContext(ctx).Info("hello")
+	fields := logs.All()[0].ContextMap()
+	if fields["spanID"] != spanID.String() || fields["traceID"] != (trace.TraceID{1}).String() {
+		t.Errorf("fields = %v, want traceID and spanID", fields)
+	}
+}
+
 // TestRequestIDFromContext 测试从 context 获取 requestID.
 func TestRequestIDFromContext(t *testing.T) {
 	ctx := context.Background()
@@ -346,6 +937,58 @@ func TestContextWithEmptyRequestID(t *testing.T) {
 	}
 }
 
+// chiRequestIDKey 和 ginRequestIDKey 模拟其他中间件保存 requestID 使用的 context key.
+type (
+	chiRequestIDKey struct{}
+	ginRequestIDKey struct{}
+)
+
+// TestRequestIDContextKeys 测试自身的 key 不存在时从注册的其他 context key 中查找 requestID.
+func TestRequestIDContextKeys(t *testing.T) {
+	var out bytes.Buffer
+	log.Init(log.WithOutputPaths(nil), log.WithFormat("json"), log.WithWriter(&out),
+		log.WithRequestIDContextKeys(chiRequestIDKey{}, ginRequestIDKey{}))
+	defer log.Init()
+
+	ctx := context.WithValue(context.Background(), ginRequestIDKey{}, "gin-id")
+	if got := log.RequestIDFromContext(ctx); got != "gin-id" {
+		t.Errorf("RequestIDFromContext() = %q, want %q", got, "gin-id")
+	}
+
+	ctx = context.WithValue(ctx, chiRequestIDKey{}, "chi-id")
+	if got := log.RequestIDFromContext(ctx); got != "chi-id" {
+		t.Errorf("RequestIDFromContext() = %q, want the first registered key", got)
+	}
+	log.FromContext(ctx).Info("fallback")
+	if !strings.Contains(out.String(), `"requestID":"chi-id"`) {
+		t.Errorf("output = %q, want requestID from the fallback key", out.String())
+	}
+
+	ctx = log.ContextWithRequestID(ctx, "own-id")
+	if got := log.RequestIDFromContext(ctx); got != "own-id" {
+		t.Errorf("RequestIDFromContext() = %q, want our own key first", got)
+	}
+}
+
+// TestTraceFieldNamespace 测试配置命名空间后追踪相关的字段在 json 和 console 格式中都输出为嵌套对象.
+func TestTraceFieldNamespace(t *testing.T) {
+	for format, want := range map[string]string{
+		"json":    `"trace":{"traceID":"trace-1","requestID":"req-1"},"user":"alice"`,
+		"console": `"trace": {"traceID": "trace-1", "requestID": "req-1"}, "user": "alice"`,
+	} {
+		var out bytes.Buffer
+		log.Init(log.WithOutputPaths(nil), log.WithFormat(format), log.WithWriter(&out),
+			log.WithProcessFields(false), log.WithTraceFieldNamespace("trace"))
+
+		ctx := log.ContextWithRequestID(log.ContextWithTraceID(context.Background(), "trace-1"), "req-1")
+		log.FromContext(ctx).Info("nested", zap.String("user", "alice"))
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("%s output = %q, want %s", format, out.String(), want)
+		}
+	}
+	log.Init()
+}
+
 // TestLogLevels 测试所有日志级别.
 func TestLogLevels(t *testing.T) {
 	log.Init(log.WithLevel("debug"))
@@ -359,14 +1002,10 @@ func TestLogLevels(t *testing.T) {
 }
 
 // TestSyncOnDefaultLogger 测试默认记录器的同步操作.
-// 注意：在某些测试环境中，stdout 可能无法正常同步，这是已知的限制。
-// 测试主要验证 Sync() 方法不会 panic。
+// 终端和管道等控制台设备不支持同步，这类无害的错误会被忽略，Sync() 不应返回错误.
 func TestSyncOnDefaultLogger(t *testing.T) {
-	err := log.Sync()
-	// stdout 在某些测试环境中可能无法正常同步，这是已知的限制
-	// 我们不将这种错误视为测试失败
-	if err != nil {
-		t.Logf("Sync() returned error (在测试环境中可能发生): %v", err)
+	if err := log.Sync(); err != nil {
+		t.Errorf("Sync() error: %v", err)
 	}
 }
 
@@ -391,3 +1030,293 @@ func TestFileRotationConfig(t *testing.T) {
 		t.Errorf("Sync() error: %v", err)
 	}
 }
+
+// TestLevelOutput 测试按级别路由的输出.
+func TestLevelOutput(t *testing.T) {
+	dir := t.TempDir()
+	appLog := filepath.Join(dir, "app.log")
+	errorLog := filepath.Join(dir, "error.log")
+
+	log.Init(
+		log.WithFilename(appLog),
+		log.WithOutputPaths([]string{}),
+		log.WithLevelOutput("warn", []string{errorLog}),
+	)
+	defer log.Init(log.WithLevel("info"))
+
+	log.Info("info message")
+	log.Warn("warn message")
+	log.Error("error message")
+
+	appData, err := os.ReadFile(appLog)
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", appLog, err)
+	}
+	errorData, err := os.ReadFile(errorLog)
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", errorLog, err)
+	}
+
+	for _, msg := range []string{"info message", "warn message", "error message"} {
+		if !strings.Contains(string(appData), msg) {
+			t.Errorf("app.log 缺少 %q", msg)
+		}
+	}
+	if strings.Contains(string(errorData), "info message") {
+		t.Errorf("error.log 不应该包含 info 日志")
+	}
+	for _, msg := range []string{"warn message", "error message"} {
+		if !strings.Contains(string(errorData), msg) {
+			t.Errorf("error.log 缺少 %q", msg)
+		}
+	}
+}
+
+// TestSampling 测试相同的日志在采样时只记录一部分.
+func TestSampling(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "sampling.log")
+	log.Init(
+		log.WithFilename(logFile),
+		log.WithOutputPaths([]string{}),
+		log.WithSampling(2, 100),
+	)
+	defer log.Init(log.WithLevel("info"))
+
+	for i := 0; i < 50; i++ {
+		log.Warn("hot path warning")
+	}
+	log.Warn("another warning")
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("读取日志文件失败: %v", err)
+	}
+	if n := strings.Count(string(data), "hot path warning"); n != 2 {
+		t.Errorf("got %d sampled lines, want 2", n)
+	}
+	if !strings.Contains(string(data), "another warning") {
+		t.Errorf("不同消息的日志不应该被采样丢弃")
+	}
+}
+
+// TestSamplingDisabled 测试禁用采样后所有日志都会记录.
+func TestSamplingDisabled(t *testing.T) {
+	opts := log.NewOptions()
+	opts.Apply(log.WithSampling(2, 100), log.WithSamplingDisabled())
+	if opts.SamplingInitial != 0 || opts.SamplingThereafter != 0 {
+		t.Errorf("WithSamplingDisabled() = (%d, %d), want (0, 0)", opts.SamplingInitial, opts.SamplingThereafter)
+	}
+}
+
+// TestNamedLoggerField 测试 json 输出中包含 logger 名称字段.
+func TestNamedLoggerField(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "named.log")
+	log.Init(
+		log.WithFormat("json"),
+		log.WithFilename(logFile),
+		log.WithOutputPaths([]string{}),
+	)
+	defer log.Init(log.WithLevel("info"))
+
+	log.Named("cache").Info("hit")
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("读取日志文件失败: %v", err)
+	}
+	if !strings.Contains(string(data), `"logger":"cache"`) {
+		t.Errorf("日志缺少 logger 字段: %s", data)
+	}
+}
+
+// fixedClock 是总是返回同一时间的 zapcore.Clock.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                         { return time.Time(c) }
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+// TestClock 测试 WithClock 注入的时钟决定日志的时间戳.
+func TestClock(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"),
+		log.WithUTC(true), log.WithClock(fixedClock(ts)))
+	defer l.Close()
+
+	l.Info("first")
+	l.Info("second")
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, `"ts":"2025-01-02T03:04:05.006Z"`) {
+			t.Errorf("got %q, want the fixed timestamp", line)
+		}
+	}
+}
+
+// TestContextDeadlineField 测试启用后 FromContext 添加距离截止时间的剩余时间，没有截止时间时不添加.
+func TestContextDeadlineField(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"),
+		log.WithClock(fixedClock(now)), log.WithContextDeadlineField(true))
+	defer l.Close()
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(1500*time.Millisecond))
+	defer cancel()
+	l.FromContext(ctx).Info("with deadline")
+	l.FromContext(context.Background()).Info("without deadline")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"deadlineRemaining":1.5`) || strings.Contains(lines[1], "deadlineRemaining") {
+		t.Errorf("got %q", buf.String())
+	}
+
+	// 默认不添加
+	buf.Reset()
+	l2 := log.NewLogger(log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"))
+	defer l2.Close()
+	l2.FromContext(ctx).Info("default")
+	if strings.Contains(buf.String(), "deadlineRemaining") {
+		t.Errorf("got %q, want no deadline field by default", buf.String())
+	}
+}
+
+// TestSink 测试 WithSink 添加的目标各自使用独立的格式和级别.
+func TestSink(t *testing.T) {
+	dir := t.TempDir()
+	consoleFile := filepath.Join(dir, "console.log")
+	jsonFile := filepath.Join(dir, "app.json")
+
+	l := log.NewLogger(log.WithLevel("debug"), log.WithOutputPaths(nil),
+		log.WithSink([]string{consoleFile}, "console", "info"),
+		log.WithSink([]string{jsonFile}, "json", "debug"))
+	l.Debug("debug message")
+	l.Info("info message", zap.String("k", "v"))
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	consoleOut, err := os.ReadFile(consoleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(consoleOut), "debug message") || !strings.Contains(string(consoleOut), "INFO\t") ||
+		strings.Contains(string(consoleOut), `"msg"`) {
+		t.Errorf("console sink = %q, want only the info entry in console format", consoleOut)
+	}
+
+	jsonOut, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(jsonOut)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"msg":"debug message"`) || !strings.Contains(lines[1], `"k":"v"`) {
+		t.Errorf("json sink = %q, want both entries in json format", jsonOut)
+	}
+
+	opts := log.NewOptions()
+	opts.Apply(log.WithSink([]string{"stdout"}, "xml", "info"))
+	if err := opts.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid sink format")
+	}
+}
+
+// TestStacktraceLevel 测试 WithStacktraceLevel 决定记录堆栈的最低级别，DisableStacktrace 优先.
+func TestStacktraceLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []log.Option
+		wantError bool
+		wantPanic bool
+	}{
+		{"default", nil, true, true},
+		{"panic", []log.Option{log.WithStacktraceLevel("panic")}, false, true},
+		{"disabled", []log.Option{log.WithStacktraceLevel("panic"), log.WithDisableStacktrace(true)}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]log.Option{log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"),
+				log.WithStacktraceKey("stacktrace")}, tt.opts...)
+			l := log.NewLogger(opts...)
+			defer l.Close()
+
+			l.Error("error message")
+			func() {
+				defer func() { _ = recover() }()
+				l.Panic("panic message")
+			}()
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got %d lines, want 2", len(lines))
+			}
+			if got := strings.Contains(lines[0], `"stacktrace"`); got != tt.wantError {
+				t.Errorf("error stacktrace = %v, want %v", got, tt.wantError)
+			}
+			if got := strings.Contains(lines[1], `"stacktrace"`); got != tt.wantPanic {
+				t.Errorf("panic stacktrace = %v, want %v", got, tt.wantPanic)
+			}
+		})
+	}
+
+	opts := log.NewOptions()
+	opts.Apply(log.WithStacktraceLevel("loud"))
+	if err := opts.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid stacktrace level")
+	}
+}
+
+// TestNewCore 测试 NewCore 返回的 Core 和 zap 选项可以与其他 Core 组合，无效的配置返回错误.
+func TestNewCore(t *testing.T) {
+	var buf bytes.Buffer
+	opts := log.NewOptions()
+	opts.Apply(log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"))
+	core, zapOpts, err := log.NewCore(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mine, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(zapcore.NewTee(mine, core), zapOpts...)
+	logger.Info("combined", zap.String("k", "v"))
+
+	if logs.FilterMessage("combined").Len() != 1 {
+		t.Error("own core should receive the entry")
+	}
+	if out := buf.String(); !strings.Contains(out, `"msg":"combined"`) || !strings.Contains(out, `"caller":"module/log_test.go`) {
+		t.Errorf("output = %q, want the entry with caller from the returned options", out)
+	}
+
+	opts.Apply(log.WithFormat("xml"))
+	if _, _, err := log.NewCore(opts); err == nil {
+		t.Error("NewCore() should reject invalid options")
+	}
+}
+
+// TestSamplingBelowLevel 测试只对低于指定级别的日志采样，更高级别的日志总是被记录.
+func TestSamplingBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := log.NewLogger(log.WithOutputPaths(nil), log.WithWriter(&buf), log.WithFormat("json"),
+		log.WithSamplingBelowLevel("warn", 2, 0))
+	defer l.Close()
+
+	for i := 0; i < 10; i++ {
+		l.Info("hot info")
+		l.Warn("hot warning")
+		l.With(zap.String("k", "v")).Error("hot error")
+	}
+
+	out := buf.String()
+	for msg, want := range map[string]int{"hot info": 2, "hot warning": 10, "hot error": 10} {
+		if n := strings.Count(out, `"msg":"`+msg+`"`); n != want {
+			t.Errorf("%s got %d lines, want %d", msg, n, want)
+		}
+	}
+
+	opts := log.NewOptions()
+	opts.Apply(log.WithSamplingBelowLevel("loud", 1, 1))
+	if err := opts.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid sampling level")
+	}
+}
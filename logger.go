@@ -0,0 +1,113 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger 是一个独立配置的日志记录器，拥有自己的输出、级别和后台资源，不依赖也不修改全局日志记录器.
+// 适用于需要多个不同配置的日志记录器的场景，或者不希望修改全局状态的库.
+// Logger 嵌入了 *zap.Logger，因此可以直接调用 Info、Warn、Error、With、Named、Sync 等方法.
+type Logger struct {
+	*zap.Logger
+	opts  *Options
+	level *dynamicLevel
+	stop  func() error
+}
+
+// NewLogger 使用 opts 创建一个独立的日志记录器. 不再使用时应调用 Close 释放后台资源.
+func NewLogger(opts ...Option) *Logger {
+	o := NewOptions()
+	o.Apply(opts...)
+	logger, level, stop := newLogger(o)
+	return &Logger{Logger: logger, opts: o, level: level, stop: stop}
+}
+
+// Default 返回全局日志记录器当前对应的 Logger，全局日志函数使用的就是该实例.
+// 之后再调用 Init 不会影响已经返回的 Logger.
+func Default() *Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return &Logger{Logger: std.Load(), opts: stdOpts.Load(), level: stdLevel.Load(), stop: stdStop}
+}
+
+// FromContext 返回附加了 context 中 traceID、spanID、requestID 和其他上下文字段的 logger，
+// 提取规则与全局的 FromContext 相同. 通过 ContextWithLogger 缓存的 logger 属于全局日志记录器，这里不会使用.
+func (l *Logger) FromContext(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return l.Logger
+	}
+	return withContextFields(ctx, l.Logger, l.opts)
+}
+
+// Options 返回 Logger 实际使用的配置的副本，包括开发模式自动调整后的值，Level 为当前的日志级别.
+func (l *Logger) Options() Options {
+	o := l.opts.Clone()
+	o.Level = l.GetLevel()
+	return *o
+}
+
+// SetLevel 在运行时调整日志级别.
+func (l *Logger) SetLevel(level string) error {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	l.level.level.SetLevel(lvl)
+	return nil
+}
+
+// GetLevel 返回当前的日志级别.
+func (l *Logger) GetLevel() string {
+	return l.level.level.Level().String()
+}
+
+// BoostLevel 将日志级别临时调整为 level，d 之后自动恢复，返回提前恢复的函数.
+// 规则与全局的 BoostLevel 相同.
+func (l *Logger) BoostLevel(level string, d time.Duration) (cancel func(), err error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return func() {}, err
+	}
+	return l.level.boost(lvl, d), nil
+}
+
+// SetLoggerLevel 在运行时调整通过 Named 创建的 logger 的日志级别，覆盖 Logger 的级别.
+func (l *Logger) SetLoggerLevel(name, level string) error {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	l.level.setLoggerLevel(name, lvl)
+	return nil
+}
+
+// Close 刷新日志，并停止缓冲写入、远程输出等后台资源.
+func (l *Logger) Close() error {
+	err := l.Sync()
+	if l.stop != nil {
+		if stopErr := l.stop(); err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLoggerInstances 测试多个 Logger 拥有独立的输出和级别，且不影响全局日志记录器.
+func TestLoggerInstances(t *testing.T) {
+	globalLevel := GetLevel()
+	var dbBuf, apiBuf bytes.Buffer
+	db := NewLogger(WithOutputPaths(nil), WithLevel("warn"), WithLevelSink("debug", SinkConfig{Writer: zapcore.AddSync(&dbBuf)}))
+	api := NewLogger(WithOutputPaths(nil), WithLevel("debug"), WithLevelSink("debug", SinkConfig{Writer: zapcore.AddSync(&apiBuf)}))
+	defer db.Close()
+	defer api.Close()
+
+	db.Info("db info")
+	db.Warn("db warn")
+	api.Debug("api debug")
+	if strings.Contains(dbBuf.String(), "db info") || !strings.Contains(dbBuf.String(), "db warn") {
+		t.Errorf("db output = %q, want only warn", dbBuf.String())
+	}
+	if strings.Contains(dbBuf.String(), "api") || !strings.Contains(apiBuf.String(), "api debug") {
+		t.Errorf("outputs are not independent: db %q, api %q", dbBuf.String(), apiBuf.String())
+	}
+
+	if err := db.SetLevel("error"); err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "error" || api.GetLevel() != "debug" || GetLevel() != globalLevel {
+		t.Errorf("levels = %s, %s, global %s", db.GetLevel(), api.GetLevel(), GetLevel())
+	}
+	if err := db.SetLevel("invalid"); err == nil {
+		t.Error("SetLevel(invalid) 应该返回错误")
+	}
+}
+
+// TestLoggerFromContext 测试 Logger 从 context 中提取字段，并忽略为全局日志记录器缓存的 logger.
+func TestLoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutputPaths(nil), WithLevelSink("debug", SinkConfig{Writer: zapcore.AddSync(&buf), Format: "json"}))
+	defer l.Close()
+
+	ctx := ContextWithRequestID(ContextWithTraceID(context.Background(), "t-1"), "r-1")
+	ctx = ContextWithLogger(ctx, GetLogger())
+	l.FromContext(ctx).Info("handled")
+	l.FromContext(nil).Info("no context")
+
+	out := buf.String()
+	if !strings.Contains(out, `"traceID":"t-1"`) || !strings.Contains(out, `"requestID":"r-1"`) || !strings.Contains(out, "no context") {
+		t.Errorf("got %q, want context fields in the instance output", out)
+	}
+}
+
+// TestDefault 测试 Default 返回全局日志记录器对应的 Logger.
+func TestDefault(t *testing.T) {
+	initStd(t, WithLevel("warn"))
+	d := Default()
+	if d.Logger != GetLogger() || d.GetLevel() != "warn" {
+		t.Errorf("Default() = %v level %s, want the global logger", d.Logger, d.GetLevel())
+	}
+	if err := d.SetLevel("error"); err != nil || GetLevel() != "error" {
+		t.Errorf("Default().SetLevel 应该调整全局级别, got %s", GetLevel())
+	}
+}
+
+// TestCurrentOptions 测试 CurrentOptions 返回开发模式调整后的配置和当前级别，修改副本不影响全局配置.
+func TestCurrentOptions(t *testing.T) {
+	initStd(t, WithDevelopment(true), WithDisableCaller(true), WithOutputPaths([]string{"stderr"}))
+
+	o := CurrentOptions()
+	if o.Level != "debug" || o.DisableCaller {
+		t.Errorf("CurrentOptions() level = %s, disableCaller = %v, want the development adjustments", o.Level, o.DisableCaller)
+	}
+	if err := SetLevel("error"); err != nil {
+		t.Fatalf("SetLevel error: %v", err)
+	}
+	o.Format = "gelf"
+	if got := CurrentOptions(); got.Level != "error" || got.Format == "gelf" {
+		t.Errorf("CurrentOptions() = level %s format %s, want the current level and an unchanged copy", got.Level, got.Format)
+	}
+}
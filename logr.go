@@ -0,0 +1,40 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build logr
+
+package log
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logr 返回写入全局日志记录器的 logr.Logger，用于 Kubernetes controller-runtime 等需要 logr 的库.
+// 每条日志都使用调用时的全局日志记录器，因此 Init 重新配置后会立即生效.
+//
+// logr 的 V 级别映射为 zap 级别 -V：V(0) 为 info，V(1) 为 debug，V(2) 及以上低于 debug，总是被丢弃.
+// logr 的 Error 映射为 error 级别，错误写入 error 字段.
+// 该函数需要使用 logr 构建标签编译，例如 go build -tags logr.
+func Logr() logr.Logger {
+	var opts []zap.Option
+	if !stdOpts.Load().DisableCaller {
+		opts = append(opts, zap.AddCaller())
+	}
+	return zapr.NewLogger(zap.New(&globalCore{minLevel: zapcore.DebugLevel}, opts...))
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build logr
+
+package log
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogr 测试 logr 的 V 级别映射为 zap 级别，并在全局日志记录器替换后写入新的日志记录器.
+func TestLogr(t *testing.T) {
+	logger, logs := NewObserver()
+	restore := SwapForTest(logger)
+	defer restore()
+
+	l := Logr()
+	l.Info("info message", "k", "v")
+	l.V(1).Info("debug message")
+	l.V(2).Info("v2 message")
+	l.Error(errors.New("boom"), "error message")
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if e := entries[0]; e.Level != zapcore.InfoLevel || e.ContextMap()["k"] != "v" || !e.Caller.Defined {
+		t.Errorf("entry = %s %v, want info with k=v and caller", e.Level, e.ContextMap())
+	}
+	if e := entries[1]; e.Level != zapcore.DebugLevel || e.Message != "debug message" {
+		t.Errorf("entry = %s %q, want V(1) as debug", e.Level, e.Message)
+	}
+	if e := entries[2]; e.Level != zapcore.ErrorLevel || e.ContextMap()["error"] != "boom" {
+		t.Errorf("entry = %s %v, want error with error field", e.Level, e.ContextMap())
+	}
+
+	// 替换全局日志记录器后，已经创建的 logr.Logger 写入新的日志记录器
+	next, nextLogs := NewObserver()
+	defer SwapForTest(next)()
+	l.Info("after swap")
+	if nextLogs.FilterMessage("after swap").Len() != 1 || logs.Len() != 3 {
+		t.Error("logr.Logger should follow the current global logger")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志被丢弃的原因.
+const (
+	dropReasonSampling  = "sampling"
+	dropReasonRateLimit = "rate_limit"
+	dropReasonRepeat    = "repeat"
+)
+
+var (
+	// logMessages 按级别统计写入的日志数量.
+	logMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_messages_total",
+		Help: "Total number of log messages written, by level.",
+	}, []string{"level"})
+	// logDropped 按原因统计被采样、限流或折叠重复日志丢弃的日志数量.
+	logDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_dropped_total",
+		Help: "Total number of log messages dropped, by reason.",
+	}, []string{"reason"})
+
+	// metricsCollector 是包含所有日志指标的 prometheus.Collector.
+	metricsCollector = &logCollector{}
+
+	metricsMu         sync.Mutex
+	metricsRegistered = make(map[prometheus.Registerer]bool)
+)
+
+// logCollector 是包含 log_messages_total 和 log_dropped_total 的 prometheus.Collector.
+type logCollector struct{}
+
+// Describe 实现 prometheus.Collector 接口.
+func (logCollector) Describe(ch chan<- *prometheus.Desc) {
+	logMessages.Describe(ch)
+	logDropped.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector 接口.
+func (logCollector) Collect(ch chan<- prometheus.Metric) {
+	logMessages.Collect(ch)
+	logDropped.Collect(ch)
+}
+
+// PrometheusCollector 返回日志指标的 prometheus.Collector，包括:
+//
+//   - log_messages_total{level}: 按级别统计写入的日志数量
+//   - log_dropped_total{reason}: 按原因统计被丢弃的日志数量，reason 为 sampling 或 rate_limit
+//
+// 指标在包初始化时创建，所有日志记录器共享，因此即使没有调用 Init 也可以使用.
+// 每次调用返回同一个 Collector，只能向同一个 Registerer 注册一次，也可以使用 WithMetrics 注册.
+func PrometheusCollector() prometheus.Collector {
+	return metricsCollector
+}
+
+// registerMetrics 将日志指标注册到 reg，同一个 reg 只注册一次，已经注册过时忽略错误.
+func registerMetrics(reg prometheus.Registerer) error {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if metricsRegistered[reg] {
+		return nil
+	}
+	if err := reg.Register(metricsCollector); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return err
+		}
+	}
+	metricsRegistered[reg] = true
+	return nil
+}
+
+// countMessage 是统计写入日志数量的 hook.
+func countMessage(ent zapcore.Entry) error {
+	logMessages.WithLabelValues(ent.Level.String()).Inc()
+	return nil
+}
+
+// countSampled 是统计被采样丢弃的日志数量的 zapcore.SamplerHook.
+func countSampled(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		logDropped.WithLabelValues(dropReasonSampling).Inc()
+	}
+}
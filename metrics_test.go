@@ -0,0 +1,85 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestMetrics 测试按级别统计写入的日志，被采样和限流丢弃的日志计入 log_dropped_total.
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	l := NewLogger(WithOutputPaths(nil), WithLevel("info"), WithMetrics(reg), WithSampling(1, 0))
+	defer l.Close()
+	// 重复注册不会 panic
+	NewLogger(WithOutputPaths(nil), WithMetrics(reg)).Close()
+
+	infos := testutil.ToFloat64(logMessages.WithLabelValues("info"))
+	debugs := testutil.ToFloat64(logMessages.WithLabelValues("debug"))
+	sampled := testutil.ToFloat64(logDropped.WithLabelValues(dropReasonSampling))
+
+	l.Debug("filtered")
+	for range 3 {
+		l.Info("repeated")
+	}
+
+	if got := testutil.ToFloat64(logMessages.WithLabelValues("info")) - infos; got != 1 {
+		t.Errorf("info messages = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(logMessages.WithLabelValues("debug")) - debugs; got != 0 {
+		t.Errorf("debug messages = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(logDropped.WithLabelValues(dropReasonSampling)) - sampled; got != 2 {
+		t.Errorf("sampled drops = %v, want 2", got)
+	}
+
+	if n, err := testutil.GatherAndCount(reg, "log_messages_total", "log_dropped_total"); err != nil || n == 0 {
+		t.Errorf("GatherAndCount() = %d, %v, want registered metrics", n, err)
+	}
+}
+
+// TestMetricsRateLimit 测试限流丢弃的日志计入 log_dropped_total.
+func TestMetricsRateLimit(t *testing.T) {
+	observeStd(t)
+	before := testutil.ToFloat64(logDropped.WithLabelValues(dropReasonRateLimit))
+
+	rl := NewRateLimitedLogger(time.Hour)
+	rl.Info("key", "first")
+	rl.Info("key", "suppressed")
+
+	if got := testutil.ToFloat64(logDropped.WithLabelValues(dropReasonRateLimit)) - before; got != 1 {
+		t.Errorf("rate limit drops = %v, want 1", got)
+	}
+}
+
+// TestPrometheusCollector 测试 Collector 可以在没有调用 Init 时注册，且每次返回同一个实例.
+func TestPrometheusCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(PrometheusCollector()); err != nil {
+		t.Fatal(err)
+	}
+	if PrometheusCollector() != PrometheusCollector() {
+		t.Error("PrometheusCollector 应该返回同一个实例")
+	}
+	if err := registerMetrics(reg); err != nil {
+		t.Errorf("registerMetrics() on a registry that already has the collector = %v, want nil", err)
+	}
+}
@@ -0,0 +1,587 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// msgpackPool 是 msgpackEncoder 输出使用的缓冲池.
+var msgpackPool = buffer.NewPool()
+
+// msgpackEncoder 将日志编码为 MessagePack map 的 zapcore.Encoder.
+// 每条日志以 4 字节大端序的长度前缀开头，后面是该长度的 MessagePack 数据，
+// 二进制数据中可能出现换行符，因此使用长度前缀而不是换行符分隔日志. LineEnding 配置不起作用.
+type msgpackEncoder struct {
+	// msgpackWriter 保存通过 With 添加的字段
+	*msgpackWriter
+}
+
+// newMsgpackEncoder 创建一个 msgpackEncoder.
+func newMsgpackEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &msgpackEncoder{&msgpackWriter{cfg: &cfg}}
+}
+
+// Clone 实现 zapcore.Encoder 接口.
+func (e *msgpackEncoder) Clone() zapcore.Encoder {
+	return &msgpackEncoder{e.clone()}
+}
+
+// EncodeEntry 实现 zapcore.Encoder 接口.
+func (e *msgpackEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	meta := &msgpackWriter{cfg: e.cfg}
+	if e.cfg.TimeKey != "" && !ent.Time.IsZero() {
+		meta.AddTime(e.cfg.TimeKey, ent.Time)
+	}
+	if e.cfg.LevelKey != "" {
+		meta.appendKey(e.cfg.LevelKey)
+		meta.appendEncoded(func(enc zapcore.PrimitiveArrayEncoder) {
+			if e.cfg.EncodeLevel != nil {
+				e.cfg.EncodeLevel(ent.Level, enc)
+			}
+		}, func() { meta.appendString(ent.Level.String()) })
+		meta.n++
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		meta.appendKey(e.cfg.NameKey)
+		meta.appendEncoded(func(enc zapcore.PrimitiveArrayEncoder) {
+			if e.cfg.EncodeName != nil {
+				e.cfg.EncodeName(ent.LoggerName, enc)
+			}
+		}, func() { meta.appendString(ent.LoggerName) })
+		meta.n++
+	}
+	if ent.Caller.Defined {
+		if e.cfg.CallerKey != "" {
+			meta.appendKey(e.cfg.CallerKey)
+			meta.appendEncoded(func(enc zapcore.PrimitiveArrayEncoder) {
+				if e.cfg.EncodeCaller != nil {
+					e.cfg.EncodeCaller(ent.Caller, enc)
+				}
+			}, func() { meta.appendString(ent.Caller.String()) })
+			meta.n++
+		}
+		if e.cfg.FunctionKey != "" {
+			meta.AddString(e.cfg.FunctionKey, ent.Caller.Function)
+		}
+	}
+	if e.cfg.MessageKey != "" {
+		meta.AddString(e.cfg.MessageKey, ent.Message)
+	}
+
+	final := e.clone()
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+	final.closeNamespaces()
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		final.AddString(e.cfg.StacktraceKey, ent.Stack)
+	}
+
+	var body msgpackWriter
+	body.appendMapHeader(meta.n + final.n)
+	body.buf = append(body.buf, meta.buf...)
+	body.buf = append(body.buf, final.buf...)
+
+	buf := msgpackPool.Get()
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(body.buf))))
+	buf.Write(body.buf)
+	return buf, nil
+}
+
+// msgpackWriter 将字段编码为 MessagePack，同时实现 zapcore.ObjectEncoder 和 zapcore.ArrayEncoder.
+// MessagePack 的 map 和数组需要先写入元素数量，因此先将元素编码到 buf 并计数，由外层写入头部.
+type msgpackWriter struct {
+	cfg *zapcore.EncoderConfig
+	buf []byte
+	// n 是 buf 中 map 的键值对数量或数组的元素数量
+	n int
+	// namespaces 是通过 OpenNamespace 打开、尚未关闭的命名空间
+	namespaces []msgpackNamespace
+}
+
+// msgpackNamespace 保存打开命名空间之前外层 map 的状态.
+type msgpackNamespace struct {
+	key string
+	buf []byte
+	n   int
+}
+
+// clone 返回 w 的副本.
+func (w *msgpackWriter) clone() *msgpackWriter {
+	c := &msgpackWriter{cfg: w.cfg, buf: append([]byte(nil), w.buf...), n: w.n}
+	for _, ns := range w.namespaces {
+		ns.buf = append([]byte(nil), ns.buf...)
+		c.namespaces = append(c.namespaces, ns)
+	}
+	return c
+}
+
+// closeNamespaces 关闭所有打开的命名空间，将其中的字段作为嵌套的 map 写入外层.
+func (w *msgpackWriter) closeNamespaces() {
+	for i := len(w.namespaces) - 1; i >= 0; i-- {
+		ns := w.namespaces[i]
+		inner, n := w.buf, w.n
+		w.buf, w.n = ns.buf, ns.n+1
+		w.appendKey(ns.key)
+		w.appendMapHeader(n)
+		w.buf = append(w.buf, inner...)
+	}
+	w.namespaces = nil
+}
+
+// appendKey 写入 map 的键. 键和值组成一个键值对，由写入值的 Append 方法计数.
+func (w *msgpackWriter) appendKey(key string) {
+	w.appendString(key)
+}
+
+// appendEncoded 写入 encode 通过 PrimitiveArrayEncoder 输出的值，没有输出时调用 fallback，
+// 输出多个值时写入数组. 与其他 append 开头的方法一样不增加计数.
+func (w *msgpackWriter) appendEncoded(encode func(zapcore.PrimitiveArrayEncoder), fallback func()) {
+	sub := &msgpackWriter{cfg: w.cfg}
+	encode(sub)
+	switch sub.n {
+	case 0:
+		fallback()
+	case 1:
+		w.buf = append(w.buf, sub.buf...)
+	default:
+		w.appendArrayHeader(sub.n)
+		w.buf = append(w.buf, sub.buf...)
+	}
+}
+
+// AddArray 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	w.appendKey(key)
+	return w.AppendArray(arr)
+}
+
+// AddObject 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	w.appendKey(key)
+	return w.AppendObject(obj)
+}
+
+// AddBinary 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddBinary(key string, val []byte) {
+	w.appendKey(key)
+	w.appendBinary(val)
+	w.n++
+}
+
+// AddByteString 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddByteString(key string, val []byte) {
+	w.appendKey(key)
+	w.AppendByteString(val)
+}
+
+// AddBool 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddBool(key string, val bool) {
+	w.appendKey(key)
+	w.AppendBool(val)
+}
+
+// AddComplex128 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddComplex128(key string, val complex128) {
+	w.appendKey(key)
+	w.AppendComplex128(val)
+}
+
+// AddComplex64 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddComplex64(key string, val complex64) {
+	w.appendKey(key)
+	w.AppendComplex64(val)
+}
+
+// AddDuration 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddDuration(key string, val time.Duration) {
+	w.appendKey(key)
+	w.AppendDuration(val)
+}
+
+// AddFloat64 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddFloat64(key string, val float64) {
+	w.appendKey(key)
+	w.AppendFloat64(val)
+}
+
+// AddFloat32 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddFloat32(key string, val float32) {
+	w.appendKey(key)
+	w.AppendFloat32(val)
+}
+
+// AddInt 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddInt(key string, val int) { w.AddInt64(key, int64(val)) }
+
+// AddInt64 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddInt64(key string, val int64) {
+	w.appendKey(key)
+	w.AppendInt64(val)
+}
+
+// AddInt32 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddInt32(key string, val int32) { w.AddInt64(key, int64(val)) }
+
+// AddInt16 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddInt16(key string, val int16) { w.AddInt64(key, int64(val)) }
+
+// AddInt8 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddInt8(key string, val int8) { w.AddInt64(key, int64(val)) }
+
+// AddString 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddString(key, val string) {
+	w.appendKey(key)
+	w.AppendString(val)
+}
+
+// AddTime 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddTime(key string, val time.Time) {
+	w.appendKey(key)
+	w.AppendTime(val)
+}
+
+// AddUint 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUint(key string, val uint) { w.AddUint64(key, uint64(val)) }
+
+// AddUint64 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUint64(key string, val uint64) {
+	w.appendKey(key)
+	w.AppendUint64(val)
+}
+
+// AddUint32 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUint32(key string, val uint32) { w.AddUint64(key, uint64(val)) }
+
+// AddUint16 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUint16(key string, val uint16) { w.AddUint64(key, uint64(val)) }
+
+// AddUint8 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUint8(key string, val uint8) { w.AddUint64(key, uint64(val)) }
+
+// AddUintptr 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddUintptr(key string, val uintptr) { w.AddUint64(key, uint64(val)) }
+
+// AddReflected 实现 zapcore.ObjectEncoder 接口.
+func (w *msgpackWriter) AddReflected(key string, val interface{}) error {
+	v, err := reflectedValue(val)
+	if err != nil {
+		return err
+	}
+	w.appendKey(key)
+	w.appendValue(v)
+	w.n++
+	return nil
+}
+
+// OpenNamespace 实现 zapcore.ObjectEncoder 接口.
+// 之后添加的字段写入新的 map，在编码结束时作为 key 的值写入外层.
+func (w *msgpackWriter) OpenNamespace(key string) {
+	w.namespaces = append(w.namespaces, msgpackNamespace{key: key, buf: w.buf, n: w.n})
+	w.buf, w.n = nil, 0
+}
+
+// AppendArray 实现 zapcore.ArrayEncoder 接口.
+func (w *msgpackWriter) AppendArray(arr zapcore.ArrayMarshaler) error {
+	sub := &msgpackWriter{cfg: w.cfg}
+	err := arr.MarshalLogArray(sub)
+	w.appendArrayHeader(sub.n)
+	w.buf = append(w.buf, sub.buf...)
+	w.n++
+	return err
+}
+
+// AppendObject 实现 zapcore.ArrayEncoder 接口.
+func (w *msgpackWriter) AppendObject(obj zapcore.ObjectMarshaler) error {
+	sub := &msgpackWriter{cfg: w.cfg}
+	err := obj.MarshalLogObject(sub)
+	sub.closeNamespaces()
+	w.appendMapHeader(sub.n)
+	w.buf = append(w.buf, sub.buf...)
+	w.n++
+	return err
+}
+
+// AppendReflected 实现 zapcore.ArrayEncoder 接口.
+func (w *msgpackWriter) AppendReflected(val interface{}) error {
+	v, err := reflectedValue(val)
+	if err != nil {
+		return err
+	}
+	w.appendValue(v)
+	w.n++
+	return nil
+}
+
+// AppendBool 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendBool(val bool) {
+	if val {
+		w.buf = append(w.buf, 0xc3)
+	} else {
+		w.buf = append(w.buf, 0xc2)
+	}
+	w.n++
+}
+
+// AppendByteString 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendByteString(val []byte) {
+	w.appendStringHeader(len(val))
+	w.buf = append(w.buf, val...)
+	w.n++
+}
+
+// AppendComplex128 实现 zapcore.PrimitiveArrayEncoder 接口，复数编码为字符串，例如 "1+2i".
+func (w *msgpackWriter) AppendComplex128(val complex128) {
+	s := strconv.FormatComplex(val, 'f', -1, 128)
+	w.AppendString(strings.TrimSuffix(strings.TrimPrefix(s, "("), ")"))
+}
+
+// AppendComplex64 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendComplex64(val complex64) {
+	s := strconv.FormatComplex(complex128(val), 'f', -1, 64)
+	w.AppendString(strings.TrimSuffix(strings.TrimPrefix(s, "("), ")"))
+}
+
+// AppendFloat64 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendFloat64(val float64) {
+	w.buf = append(w.buf, 0xcb)
+	w.buf = binary.BigEndian.AppendUint64(w.buf, math.Float64bits(val))
+	w.n++
+}
+
+// AppendFloat32 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendFloat32(val float32) {
+	w.buf = append(w.buf, 0xca)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, math.Float32bits(val))
+	w.n++
+}
+
+// AppendInt 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendInt(val int) { w.AppendInt64(int64(val)) }
+
+// AppendInt64 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendInt64(val int64) {
+	w.appendInt(val)
+	w.n++
+}
+
+// AppendInt32 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendInt32(val int32) { w.AppendInt64(int64(val)) }
+
+// AppendInt16 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendInt16(val int16) { w.AppendInt64(int64(val)) }
+
+// AppendInt8 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendInt8(val int8) { w.AppendInt64(int64(val)) }
+
+// AppendString 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendString(val string) {
+	w.appendString(val)
+	w.n++
+}
+
+// AppendUint 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUint(val uint) { w.AppendUint64(uint64(val)) }
+
+// AppendUint64 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUint64(val uint64) {
+	w.appendUint(val)
+	w.n++
+}
+
+// AppendUint32 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUint32(val uint32) { w.AppendUint64(uint64(val)) }
+
+// AppendUint16 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUint16(val uint16) { w.AppendUint64(uint64(val)) }
+
+// AppendUint8 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUint8(val uint8) { w.AppendUint64(uint64(val)) }
+
+// AppendUintptr 实现 zapcore.PrimitiveArrayEncoder 接口.
+func (w *msgpackWriter) AppendUintptr(val uintptr) { w.AppendUint64(uint64(val)) }
+
+// AppendDuration 实现 zapcore.ArrayEncoder 接口，使用 EncodeDuration 编码，没有配置时编码为纳秒数.
+func (w *msgpackWriter) AppendDuration(val time.Duration) {
+	w.appendEncoded(func(enc zapcore.PrimitiveArrayEncoder) {
+		if w.cfg != nil && w.cfg.EncodeDuration != nil {
+			w.cfg.EncodeDuration(val, enc)
+		}
+	}, func() { w.appendInt(int64(val)) })
+	w.n++
+}
+
+// AppendTime 实现 zapcore.ArrayEncoder 接口，使用 EncodeTime 编码，没有配置时编码为 Unix 纳秒数.
+func (w *msgpackWriter) AppendTime(val time.Time) {
+	w.appendEncoded(func(enc zapcore.PrimitiveArrayEncoder) {
+		if w.cfg != nil && w.cfg.EncodeTime != nil {
+			w.cfg.EncodeTime(val, enc)
+		}
+	}, func() { w.appendInt(val.UnixNano()) })
+	w.n++
+}
+
+// appendInt 使用能容纳 val 的最短格式写入整数.
+func (w *msgpackWriter) appendInt(val int64) {
+	switch {
+	case val >= 0:
+		w.appendUint(uint64(val))
+	case val >= -32:
+		w.buf = append(w.buf, byte(val))
+	case val >= math.MinInt8:
+		w.buf = append(w.buf, 0xd0, byte(val))
+	case val >= math.MinInt16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xd1), uint16(val))
+	case val >= math.MinInt32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xd2), uint32(val))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xd3), uint64(val))
+	}
+}
+
+// appendUint 使用能容纳 val 的最短格式写入无符号整数.
+func (w *msgpackWriter) appendUint(val uint64) {
+	switch {
+	case val <= math.MaxInt8:
+		w.buf = append(w.buf, byte(val))
+	case val <= math.MaxUint8:
+		w.buf = append(w.buf, 0xcc, byte(val))
+	case val <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xcd), uint16(val))
+	case val <= math.MaxUint32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xce), uint32(val))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xcf), val)
+	}
+}
+
+// appendString 写入字符串.
+func (w *msgpackWriter) appendString(val string) {
+	w.appendStringHeader(len(val))
+	w.buf = append(w.buf, val...)
+}
+
+// appendStringHeader 写入长度为 n 的字符串的头部.
+func (w *msgpackWriter) appendStringHeader(n int) {
+	switch {
+	case n < 32:
+		w.buf = append(w.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xda), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdb), uint32(n))
+	}
+}
+
+// appendBinary 写入二进制数据.
+func (w *msgpackWriter) appendBinary(val []byte) {
+	switch n := len(val); {
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xc5), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xc6), uint32(n))
+	}
+	w.buf = append(w.buf, val...)
+}
+
+// appendArrayHeader 写入包含 n 个元素的数组的头部.
+func (w *msgpackWriter) appendArrayHeader(n int) {
+	switch {
+	case n < 16:
+		w.buf = append(w.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xdc), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdd), uint32(n))
+	}
+}
+
+// appendMapHeader 写入包含 n 个键值对的 map 的头部.
+func (w *msgpackWriter) appendMapHeader(n int) {
+	switch {
+	case n < 16:
+		w.buf = append(w.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xde), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdf), uint32(n))
+	}
+}
+
+// appendValue 写入 reflectedValue 返回的值.
+func (w *msgpackWriter) appendValue(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		w.buf = append(w.buf, 0xc0)
+	case bool:
+		if v {
+			w.buf = append(w.buf, 0xc3)
+		} else {
+			w.buf = append(w.buf, 0xc2)
+		}
+	case string:
+		w.appendString(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			w.appendInt(i)
+		} else if f, err := v.Float64(); err == nil {
+			w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xcb), math.Float64bits(f))
+		} else {
+			w.appendString(v.String())
+		}
+	case []interface{}:
+		w.appendArrayHeader(len(v))
+		for _, e := range v {
+			w.appendValue(e)
+		}
+	case map[string]interface{}:
+		w.appendMapHeader(len(v))
+		for k, e := range v {
+			w.appendString(k)
+			w.appendValue(e)
+		}
+	}
+}
+
+// reflectedValue 将任意值通过 encoding/json 转换为由 map、切片、字符串、数字、布尔值和 nil 组成的值，
+// 与 json 编码器对反射字段的处理保持一致.
+func reflectedValue(val interface{}) (interface{}, error) {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ugorji/go/codec"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// decodeMsgpackStream 按长度前缀拆分 msgpack 格式的输出，并解码每条日志.
+func decodeMsgpackStream(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var h codec.MsgpackHandle
+	h.WriteExt = true // 按新版规范区分 str 和 bin 类型
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	var records []map[string]interface{}
+	r := bytes.NewReader(data)
+	for {
+		var n uint32
+		if err := binary.Read(r, binary.BigEndian, &n); errors.Is(err, io.EOF) {
+			return records
+		} else if err != nil {
+			t.Fatalf("read length: %v", err)
+		}
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		var record map[string]interface{}
+		if err := codec.NewDecoderBytes(frame, &h).Decode(&record); err != nil {
+			t.Fatalf("decode frame: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// TestMsgpackEncoder 测试 msgpack 编码器使用配置的字段名，并正确编码各种类型的字段.
+func TestMsgpackEncoder(t *testing.T) {
+	opts := NewOptions()
+	opts.Apply(WithUTC(true), WithMessageKey("message"))
+	enc := newEncoder("msgpack", newEncoderConfig(opts), opts)
+	enc.AddString("service", "api")
+	enc.OpenNamespace("request")
+
+	ent := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Date(2025, 1, 2, 0, 4, 5, 123000000, time.UTC),
+		LoggerName: "http",
+		Message:    "slow request",
+		Caller:     zapcore.NewEntryCaller(0, "pkg/file.go", 10, true),
+	}
+	fields := []zapcore.Field{
+		zap.Int("status", -404),
+		zap.Uint64("bytes", 1<<40),
+		zap.Float64("ratio", 0.5),
+		zap.Bool("cached", true),
+		zap.Duration("latency", 1500*time.Millisecond),
+		zap.Binary("raw", []byte{0x0a, 0x00}),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Any("meta", map[string]int{"retries": 2}),
+		zap.Complex128("c", 1+2i),
+		zap.Error(errors.New("timeout")),
+	}
+	buf, err := enc.EncodeEntry(ent, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry error: %v", err)
+	}
+	records := decodeMsgpackStream(t, buf.Bytes())
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	r := records[0]
+
+	for key, want := range map[string]interface{}{
+		"ts": "2025-01-02T00:04:05.123Z", "level": "WARN", "logger": "http",
+		"caller": "pkg/file.go:10", "message": "slow request", "service": "api",
+	} {
+		if r[key] != want {
+			t.Errorf("%s = %#v, want %#v", key, r[key], want)
+		}
+	}
+	req, ok := r["request"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("request = %#v, want a nested map", r["request"])
+	}
+	for key, want := range map[string]interface{}{
+		"status": int64(-404), "bytes": uint64(1 << 40), "ratio": 0.5, "cached": true,
+		"latency": 1.5, "c": "1+2i", "error": "timeout",
+	} {
+		if req[key] != want {
+			t.Errorf("request.%s = %#v (%T), want %#v", key, req[key], req[key], want)
+		}
+	}
+	if raw, _ := req["raw"].([]byte); !bytes.Equal(raw, []byte{0x0a, 0x00}) {
+		t.Errorf("request.raw = %#v, want the binary value", req["raw"])
+	}
+	if tags, _ := req["tags"].([]interface{}); len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("request.tags = %#v", req["tags"])
+	}
+	if meta, _ := req["meta"].(map[string]interface{}); meta["retries"] != int64(2) {
+		t.Errorf("request.meta = %#v", req["meta"])
+	}
+}
+
+// TestMsgpackFormat 测试使用 msgpack 格式的 logger 输出可以按长度前缀拆分的日志流.
+func TestMsgpackFormat(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(WithOutputPaths(nil), WithFormat("msgpack"), WithWriter(&out), WithProcessFields(false))
+	l.With(zap.String("user", "alice")).Info("first")
+	l.Info("second\nline")
+	l.Close()
+
+	records := decodeMsgpackStream(t, out.Bytes())
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0]["msg"] != "first" || records[0]["user"] != "alice" || records[1]["msg"] != "second\nline" {
+		t.Errorf("records = %v", records)
+	}
+	if _, ok := records[1]["user"]; ok {
+		t.Error("With 添加的字段不应该出现在其他日志中")
+	}
+}
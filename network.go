@@ -0,0 +1,234 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	networkDialTimeout  = 5 * time.Second
+	networkWriteTimeout = 5 * time.Second
+	networkMinBackoff   = 100 * time.Millisecond
+	networkMaxBackoff   = 30 * time.Second
+	networkMaxPending   = 1000
+)
+
+// networkWriter 是将每条编码后的日志写入 TCP 或 UDP 连接的 zapcore.WriteSyncer.
+// 首次连接在后台进行，不会阻塞 Init，期间的日志暂存在内存中，连接建立后按顺序写入.
+// 连接断开时日志写入 fallback（默认为 stderr），同时在后台按指数退避重新连接，
+// 连接恢复后继续写入网络. 连接状态的变化写入错误输出.
+type networkWriter struct {
+	network  string
+	addr     string
+	fallback zapcore.WriteSyncer
+	errOut   zapcore.WriteSyncer
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+	// pending 是首次连接完成之前写入的日志，ready 关闭后为 nil
+	pending [][]byte
+	ready   chan struct{}
+
+	reconnect chan struct{}
+	stopCh    chan struct{}
+	done      chan struct{}
+	once      sync.Once
+}
+
+// newNetworkWriter 创建一个 networkWriter，在后台建立连接并重连.
+func newNetworkWriter(network, addr string, fallback, errOut zapcore.WriteSyncer) *networkWriter {
+	w := &networkWriter{
+		network:   network,
+		addr:      addr,
+		fallback:  fallback,
+		errOut:    errOut,
+		ready:     make(chan struct{}),
+		reconnect: make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+// Write 实现 io.Writer 接口.
+// 首次连接完成之前暂存日志，超过上限时写入 fallback.
+// 写入失败时关闭连接，将这条日志写入 fallback 并触发重连.
+func (w *networkWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.isConnecting() {
+		if len(w.pending) < networkMaxPending {
+			w.pending = append(w.pending, append([]byte(nil), p...))
+			w.mu.Unlock()
+			return len(p), nil
+		}
+		w.mu.Unlock()
+		return w.fallback.Write(p)
+	}
+	conn := w.conn
+	if conn != nil {
+		_ = conn.SetWriteDeadline(time.Now().Add(networkWriteTimeout))
+		_, err := conn.Write(p)
+		if err == nil {
+			w.mu.Unlock()
+			return len(p), nil
+		}
+		_ = conn.Close()
+		w.conn = nil
+		w.mu.Unlock()
+		w.reportError(fmt.Errorf("write: %w, falling back to stderr", err))
+		w.scheduleReconnect()
+	} else {
+		w.mu.Unlock()
+	}
+	return w.fallback.Write(p)
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+// 等待首次连接完成并写入暂存的日志，之后每条日志都直接写入连接，只需要同步 fallback.
+func (w *networkWriter) Sync() error {
+	<-w.ready
+	return w.fallback.Sync()
+}
+
+// isConnecting 判断首次连接是否仍在进行. 必须在持有 mu 时调用.
+func (w *networkWriter) isConnecting() bool {
+	select {
+	case <-w.ready:
+		return false
+	default:
+		return true
+	}
+}
+
+// connectFirst 进行首次连接，然后将暂存的日志按顺序写入连接，连接失败时写入 fallback.
+func (w *networkWriter) connectFirst() {
+	err := w.connect()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	defer close(w.ready)
+	pending := w.pending
+	w.pending = nil
+	if err != nil {
+		w.reportError(fmt.Errorf("%w, falling back to stderr", err))
+		w.scheduleReconnect()
+	}
+	for i, p := range pending {
+		if w.conn == nil {
+			for _, p := range pending[i:] {
+				_, _ = w.fallback.Write(p)
+			}
+			return
+		}
+		_ = w.conn.SetWriteDeadline(time.Now().Add(networkWriteTimeout))
+		if _, err := w.conn.Write(p); err != nil {
+			_ = w.conn.Close()
+			w.conn = nil
+			w.reportError(fmt.Errorf("write: %w, falling back to stderr", err))
+			w.scheduleReconnect()
+			_, _ = w.fallback.Write(p)
+		}
+	}
+}
+
+// Close 停止后台重连并关闭连接.
+func (w *networkWriter) Close() error {
+	w.once.Do(func() { close(w.stopCh) })
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// connect 在超时时间内建立连接.
+func (w *networkWriter) connect() error {
+	conn, err := net.DialTimeout(w.network, w.addr, networkDialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		_ = conn.Close()
+		return errors.New("writer closed")
+	}
+	if w.conn != nil {
+		// 已经有可用的连接，例如多次写入失败触发了重复的重连通知
+		_ = conn.Close()
+		return nil
+	}
+	w.conn = conn
+	return nil
+}
+
+// scheduleReconnect 通知后台重新连接.
+func (w *networkWriter) scheduleReconnect() {
+	select {
+	case w.reconnect <- struct{}{}:
+	default:
+	}
+}
+
+// loop 进行首次连接，之后在连接断开时按指数退避重新连接，直到连接成功或 Close 被调用.
+func (w *networkWriter) loop() {
+	defer close(w.done)
+	w.connectFirst()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-w.reconnect:
+		}
+
+		backoff := networkMinBackoff
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-time.After(backoff):
+			}
+			if err := w.connect(); err == nil {
+				w.reportError(errors.New("reconnected"))
+				break
+			}
+			backoff = min(backoff*2, networkMaxBackoff)
+		}
+	}
+}
+
+// reportError 将连接状态写入错误输出.
+func (w *networkWriter) reportError(err error) {
+	fmt.Fprintf(w.errOut, "%v network output %s://%s: %v\n", time.Now(), w.network, w.addr, err)
+	_ = w.errOut.Sync()
+}
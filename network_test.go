@@ -0,0 +1,214 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestNetworkOutputTCP 测试日志逐行写入 TCP 连接，Close 后连接被关闭.
+func TestNetworkOutputTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	l := NewLogger(WithOutputPaths(nil), WithNetworkOutput("tcp", ln.Addr().String()))
+	l.Info("first")
+	l.Warn("second")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for line := range lines {
+		got = append(got, line)
+	}
+	if len(got) != 2 || !strings.Contains(got[0], `"msg":"first"`) || !strings.Contains(got[1], `"level":"WARN"`) {
+		t.Errorf("lines = %q, want two json lines", got)
+	}
+}
+
+// TestNetworkOutputUDP 测试每条日志作为一个 UDP 数据报发送.
+func TestNetworkOutputUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	l := NewLogger(WithOutputPaths(nil), WithNetworkOutput("udp", pc.LocalAddr().String()))
+	defer l.Close()
+	l.Info("datagram")
+
+	buf := make([]byte, 64*1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf[:n]), `"msg":"datagram"`) {
+		t.Errorf("datagram = %q", buf[:n])
+	}
+}
+
+// lockedBuffer 是线程安全的 bytes.Buffer.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write 实现 io.Writer 接口.
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String 返回写入的内容.
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestNetworkWriterReconnect 测试连接不可用时写入 fallback，服务恢复后自动重新连接.
+func TestNetworkWriterReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var fallback, errOut lockedBuffer
+	w := newNetworkWriter("tcp", addr, zapcore.AddSync(&fallback), zapcore.AddSync(&errOut))
+	defer w.Close()
+
+	if _, err := w.Write([]byte("while down\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if fallback.String() != "while down\n" {
+		t.Errorf("fallback = %q, want the line written while disconnected", fallback.String())
+	}
+	if !strings.Contains(errOut.String(), "falling back to stderr") {
+		t.Errorf("error output = %q, want the dial error", errOut.String())
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("listen again on %s: %v", addr, err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(errOut.String(), "reconnected") {
+		if time.Now().After(deadline) {
+			t.Fatalf("not reconnected, error output = %q", errOut.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := w.Write([]byte("after reconnect\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case line := <-received:
+		if line != "after reconnect\n" {
+			t.Errorf("received = %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("line not received after reconnect")
+	}
+}
+
+// TestNetworkWriterConnectInBackground 测试创建 networkWriter 不等待连接，连接建立之前写入的日志按顺序发送.
+func TestNetworkWriterConnectInBackground(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var fallback, errOut lockedBuffer
+	w := newNetworkWriter("tcp", ln.Addr().String(), zapcore.AddSync(&fallback), zapcore.AddSync(&errOut))
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+	var got []string
+	for scanner.Scan() {
+		got = append(got, scanner.Text())
+	}
+	if strings.Join(got, ",") != "first,second,third" {
+		t.Errorf("lines = %q, want first, second and third in order", got)
+	}
+	if fallback.String() != "" || errOut.String() != "" {
+		t.Errorf("fallback = %q, error output = %q, want both empty", fallback.String(), errOut.String())
+	}
+}
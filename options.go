@@ -18,13 +18,23 @@ package log
 
 import (
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap/zapcore"
 )
 
 // Options 定义了日志记录器的配置项
 type Options struct {
-	// OutputPaths 是一个输出路径的列表，可以是 stdout, stderr, 或者文件路径
+	// OutputPaths 是一个输出路径的列表，可以是 stdout, stderr, 或者文件路径.
+	// 文件以追加方式写入，不进行轮转，需要轮转时使用 Filename.
 	// 默认为 ["stdout"].
 	OutputPaths []string
 	// ErrorOutputPaths 是一个错误日志输出路径的列表，用于记录 logger 内部的错误
@@ -35,11 +45,18 @@ type Options struct {
 	// 默认为 "info".
 	Level string
 	// Format 指定日志的输出格式.
-	// 可选值: "json", "console". 默认为 "console".
+	// 可选值: "json", "console", "gelf", "msgpack". 默认为 "console".
+	// msgpack 格式的每条日志以 4 字节大端序的长度前缀开头，适合写入文件或网络流由采集端拆分.
 	Format string
+	// Color 是否在控制台输出中使用彩色的日志级别.
+	// 只对 console 格式的 stdout/stderr 输出生效，文件输出始终不包含 ANSI 转义码.
+	// 默认为 false.
+	Color bool
 	// DisableCaller 禁止在日志中记录调用者的文件名和行号.
 	// 默认为 false.
 	DisableCaller bool
+	// CallerSkip 是记录调用者时额外跳过的调用层数，用于在封装了本包函数的辅助函数中记录真实的调用位置.
+	CallerSkip int
 	// DisableStacktrace 禁止自动捕获堆栈跟踪.
 	// 默认情况下，在开发环境中，WarnLevel 及更高级别的日志会捕获堆栈，
 	// 在生产环境中，ErrorLevel 及更高级别的日志会捕获堆栈.
@@ -55,10 +72,229 @@ type Options struct {
 	// Compress 决定是否压缩轮转后的日志文件.
 	// 默认为 false.
 	Compress bool
+	// CompressAlgorithm 是压缩轮转后日志文件的算法，可选值为 "gzip" 和 "zstd"，为空时使用 gzip.
+	CompressAlgorithm string
+	// RotateInterval 是按时间轮转日志文件的间隔，例如 24 * time.Hour 表示每天本地零点轮转.
+	// 与 MaxSize 同时生效，先满足的条件触发轮转. 轮转后的文件以时间段命名，例如 app-2025-01-02.log.
+	// 默认为 0，表示只按大小轮转.
+	RotateInterval time.Duration
 	// Development 是否为开发模式.
 	// 开发模式下会自动启用更详细的日志输出和堆栈跟踪.
 	// 默认为 false.
 	Development bool
+	// ErrorOutputFormat 指定 logger 内部错误的输出格式.
+	// 可选值: "json", "console". 为空时保持 zap 默认的纯文本格式.
+	ErrorOutputFormat string
+	// SamplingInitial 是每秒内相同级别和消息的日志中，完整记录的前 N 条.
+	// 大于 0 时启用采样. 默认为 0，表示不采样.
+	SamplingInitial int
+	// SamplingThereafter 是超过 SamplingInitial 之后，每 N 条相同的日志记录 1 条.
+	// 为 0 时超过 SamplingInitial 的日志全部丢弃.
+	SamplingThereafter int
+	// RedactKeys 是需要脱敏的字段名列表，不区分大小写.
+	// 匹配的字段无论类型如何，其值都会在编码前被替换为 "***".
+	RedactKeys []string
+	// FieldSchema 声明字段的值类型，仅在开发模式下生效.
+	// 字段以不符合声明的类型记录时，会额外输出一条 warn 级别的日志，用于尽早发现破坏下游类型化解析的问题.
+	FieldSchema map[string]reflect.Kind
+	// EntryFilters 是日志条目的过滤函数列表.
+	// 任意一个过滤函数返回 false 时整条日志被丢弃，dpanic, panic 和 fatal 级别的日志除外.
+	EntryFilters []EntryFilter
+	// SamplingSchedule 是按一天中的时间段使用的采样参数.
+	// 当前时间落在某个时间段内时使用该时间段的采样参数，否则使用 SamplingInitial/SamplingThereafter.
+	SamplingSchedule []SamplingWindow
+	// ContextSinks 是支持 context 的额外输出目标.
+	// 通过 FromContext 获取的 logger 写入这些目标时，会遵循请求 context 的截止时间和取消信号.
+	ContextSinks []ContextWriteSyncer
+	// EventLogSource 是写入 Windows 事件日志时使用的事件源名称.
+	// 为空时不写入事件日志. 在非 Windows 平台上会回退到文件或 stderr 输出.
+	EventLogSource string
+	// LevelOutputs 是按级别额外输出的目标列表.
+	// 达到指定级别的日志除写入主输出外，还会同时写入对应的目标.
+	LevelOutputs []LevelOutput
+	// StringInterning 表示是否驻留日志消息和字段键.
+	// 启用后相同的字符串共享同一份存储，适合消息种类较少、吞吐量很高的服务.
+	StringInterning bool
+	// TimeFormat 是日志时间的格式，可以是 time 包的布局字符串，也可以是以下快捷名称:
+	// "iso8601"（默认）、"iso8601millis"、"rfc3339"、"rfc3339nano"、"epoch"（秒）、"epochMillis"、"epochNanos".
+	TimeFormat string
+	// TimeZone 是日志时间使用的时区，为 nil 时使用本地时区. 对 epoch 格式无效.
+	TimeZone *time.Location
+	// LevelEncoder 是日志级别的编码方式，可选值为 "capital"（默认）、"capitalColor"、"lowercase"、"color"
+	// 和 "stackdriver"（Cloud Logging 的严重性，例如 WARNING、CRITICAL）.
+	// 为空时，如果处于开发模式、使用 console 格式并且输出到终端，控制台输出会自动使用 capitalColor.
+	LevelEncoder string
+	// LevelSinks 是按级别区间路由的远程输出目标列表.
+	// 每个目标接收从自身最低级别开始、到下一个更高的最低级别之前的日志.
+	LevelSinks []LevelSink
+	// SpanEvents 表示是否将通过 FromContext 记录的日志同时记录为 OpenTelemetry span 事件.
+	SpanEvents bool
+	// BufferSize 是文件输出的缓冲区大小（以字节为单位），为 0 时不缓冲.
+	BufferSize int
+	// FlushInterval 是缓冲区的定时刷新间隔，为 0 时使用 zap 的默认值（30 秒）.
+	FlushInterval time.Duration
+	// KafkaBrokers 是 Kafka 输出的 broker 地址列表.
+	KafkaBrokers []string
+	// KafkaTopic 是 Kafka 输出的 topic，为空时不输出到 Kafka.
+	KafkaTopic string
+	// KafkaKeyField 是作为 Kafka 消息 key 的字段名，例如 traceID，为空时不设置 key.
+	KafkaKeyField string
+	// HTTPOutputURL 是 HTTP 输出的端点地址，为空时不输出到 HTTP 端点.
+	HTTPOutputURL string
+	// HTTPOutputOptions 是 HTTP 输出的配置项.
+	HTTPOutputOptions []HTTPSinkOption
+	// SyslogNetwork 是 syslog 输出的网络类型，例如 "udp" 或 "tcp"，为空时连接本机的 syslog 服务.
+	SyslogNetwork string
+	// SyslogAddr 是 syslog 输出的服务地址，为空时连接本机的 syslog 服务.
+	SyslogAddr string
+	// SyslogTag 是 syslog 输出的标签，为空时使用程序名.
+	SyslogTag string
+	// Syslog 表示是否输出到 syslog.
+	Syslog bool
+	// Journald 表示是否以原生字段输出到 systemd-journald.
+	Journald bool
+	// GELFHost 是 gelf 格式日志的 host 字段，为空时使用 os.Hostname().
+	GELFHost string
+	// SentryDSN 是 Sentry 项目的 DSN，为空时不发送到 Sentry.
+	SentryDSN string
+	// SentryLevel 是发送到 Sentry 的最低日志级别，默认为 error.
+	SentryLevel string
+	// EncoderKeys 是日志中各个固定字段的字段名，为 nil 时使用 DefaultEncoderKeys.
+	EncoderKeys *EncoderKeys
+	// StackdriverProject 是 Google Cloud 项目 ID，不为空时 traceID 字段改写为 Cloud Logging 的链路关联字段.
+	StackdriverProject string
+	// CloudWatchGroup 是 CloudWatch Logs 输出的日志组，为空时不输出到 CloudWatch Logs.
+	CloudWatchGroup string
+	// CloudWatchStream 是 CloudWatch Logs 输出的日志流.
+	CloudWatchStream string
+	// DedupFields 是重复字段的处理策略，可选值为 "last"、"first" 和 "error"，为空时不去重.
+	DedupFields string
+	// RequestIDGenerator 是生成 requestID 的函数，为 nil 时生成 UUID v4.
+	RequestIDGenerator func() string
+	// Hooks 是每条日志写入后按顺序调用的回调函数列表.
+	Hooks []func(zapcore.Entry) error
+	// MetricsRegisterer 是注册日志指标的 Prometheus Registerer，为 nil 时不注册.
+	MetricsRegisterer prometheus.Registerer
+	// Writers 是额外的日志输出，与 OutputPaths 中的输出同时写入.
+	Writers []io.Writer
+	// ErrorWriters 是额外的错误输出，与 ErrorOutputPaths 中的输出同时写入.
+	ErrorWriters []io.Writer
+	// FileMode 是新建日志文件的权限，为 0 时使用 0600.
+	FileMode os.FileMode
+	// DirMode 是新建日志目录的权限，为 0 时使用 0755.
+	DirMode os.FileMode
+	// StrictPaths 表示 Validate 是否检查输出路径中的文件能否创建.
+	StrictPaths bool
+	// DedupWindow 是折叠连续重复日志的时间窗口，为 0 时不折叠.
+	DedupWindow time.Duration
+	// NetworkOutput 是网络输出的协议，可选值为 "tcp" 和 "udp".
+	NetworkOutput string
+	// NetworkAddr 是网络输出的地址，为空时不输出到网络.
+	NetworkAddr string
+	// LevelRules 是按 logger 名称设置的日志级别，键为 Named 设置的名称，值为日志级别.
+	LevelRules map[string]string
+	// ProcessFields 表示是否为每条日志添加 host 和 pid 字段，为 nil 时只在非开发模式下添加.
+	ProcessFields *bool
+	// Hostname 是 host 字段的值，为空时使用 os.Hostname.
+	Hostname string
+	// RequestIDContextKeys 是自身的 requestID key 不存在时依次查找的其他 context key，
+	// 用于兼容其他中间件保存的 requestID.
+	RequestIDContextKeys []interface{}
+	// GoroutineID 表示是否为每条日志添加写入日志的 goroutine ID 字段 goid.
+	GoroutineID bool
+	// TraceFieldNamespace 是 FromContext 添加的 traceID、spanID 和 requestID 所在的嵌套对象的名称，为空时作为顶层字段.
+	TraceFieldNamespace string
+	// ConsoleSeparator 是 console 格式中各部分之间的分隔符，为空时使用制表符.
+	ConsoleSeparator string
+	// FieldSamplingKey 是按值采样的字段名，为空时不按字段值采样.
+	FieldSamplingKey string
+	// FieldSamplingInitial 是每秒内相同级别和字段值的日志中，完整记录的前 N 条.
+	FieldSamplingInitial int
+	// FieldSamplingThereafter 是超过 FieldSamplingInitial 之后，每 N 条相同字段值的日志记录 1 条.
+	// 为 0 时超过 FieldSamplingInitial 的日志全部丢弃.
+	FieldSamplingThereafter int
+	// EncryptionKey 是加密日志文件使用的 AES 密钥，为空时不加密.
+	EncryptionKey []byte
+	// FieldTransforms 是在编码前依次应用于每个字段的转换函数列表.
+	FieldTransforms []FieldTransform
+	// Clock 是日志时间戳和采样使用的时钟，为 nil 时使用系统时钟.
+	Clock zapcore.Clock
+	// ContextDeadlineField 表示 FromContext 是否在 context 设置了截止时间时添加 deadlineRemaining 字段.
+	ContextDeadlineField bool
+	// Sinks 是使用独立格式和级别的额外输出目标列表.
+	Sinks []Sink
+	// StacktraceLevel 是记录堆栈的最低日志级别，为空时开发模式为 warn，生产模式为 error.
+	// DisableStacktrace 为 true 时不生效.
+	StacktraceLevel string
+	// RingBufferSize 是在内存中保存的最近日志条数，为 0 时不保存.
+	RingBufferSize int
+	// LevelColors 是彩色日志级别使用的颜色，键为日志级别，值为以空格分隔的颜色和样式名称，例如 "bold red".
+	// 未指定的级别使用默认颜色，只在启用彩色日志级别时生效.
+	LevelColors map[string]string
+	// SerializedWrites 表示是否加锁写入日志文件，保证每条日志作为整体写入，不与其他 goroutine 的日志交错.
+	SerializedWrites bool
+	// SamplingBelowLevel 是采样的级别上限，只有低于该级别的日志被采样，为空时所有级别都被采样.
+	SamplingBelowLevel string
+	// ReplaceZapGlobals 表示 Init 时是否将全局日志记录器注册为 zap 的全局 logger.
+	ReplaceZapGlobals bool
+	// RedirectStdLog 表示 Init 时是否将标准库 log 包的输出重定向到全局日志记录器.
+	RedirectStdLog bool
+
+	// stdout 和 stderr 替换 stdout 和 stderr 输出，只由 CaptureOutput 设置
+	stdout io.Writer
+	stderr io.Writer
+	// ring 是 WithRingBuffer 启用时 newLogger 创建的环形缓冲区
+	ring *ringBuffer
+	// errorOutput 和 stacktrace 是 newLogger 使用的错误输出和记录堆栈的级别，供 slog 等不经过 zap.Logger 的入口使用.
+	// 不记录堆栈时 stacktrace 为 nil
+	errorOutput zapcore.WriteSyncer
+	stacktrace  zapcore.LevelEnabler
+}
+
+// EncoderKeys 定义了日志中各个固定字段的字段名. 字段名为空时日志中不包含该字段.
+type EncoderKeys struct {
+	// MessageKey 是日志消息的字段名，默认为 msg.
+	MessageKey string
+	// LevelKey 是日志级别的字段名，默认为 level.
+	LevelKey string
+	// TimeKey 是日志时间的字段名，默认为 ts.
+	TimeKey string
+	// CallerKey 是调用者的字段名，默认为 caller.
+	CallerKey string
+	// NameKey 是 logger 名称的字段名，默认为 logger.
+	NameKey string
+	// StacktraceKey 是堆栈的字段名，默认为空，即不输出堆栈.
+	StacktraceKey string
+}
+
+// DefaultEncoderKeys 返回默认的字段名.
+func DefaultEncoderKeys() EncoderKeys {
+	return EncoderKeys{
+		MessageKey: "msg",
+		LevelKey:   "level",
+		TimeKey:    "ts",
+		CallerKey:  "caller",
+		NameKey:    "logger",
+	}
+}
+
+// LevelOutput 定义了一个按级别路由的额外输出目标.
+type LevelOutput struct {
+	// MinLevel 是写入该目标的最低日志级别.
+	MinLevel string
+	// Paths 是输出路径的列表，可以是 stdout, stderr, 或者文件路径.
+	// 文件路径使用 lumberjack 写入，并沿用 Options 中的轮转配置.
+	Paths []string
+}
+
+// Sink 定义了一个使用独立格式和级别的输出目标.
+type Sink struct {
+	// Paths 是输出路径的列表，可以是 stdout, stderr, 或者文件路径，文件沿用 Options 中的轮转配置.
+	Paths []string
+	// Format 是该目标的日志格式，可选值与 Options.Format 相同，为空时使用 Options.Format.
+	Format string
+	// Level 是写入该目标的最低日志级别，为空时为 info.
+	Level string
 }
 
 // Option 是一个将配置项应用于 Options 的函数.
@@ -81,6 +317,201 @@ func (o *Options) Apply(opts ...Option) {
 	}
 }
 
+// Clone 返回 o 的深拷贝，修改副本的切片、map 和指针字段不会影响 o，适合从同一份基础配置派生多个日志记录器.
+// io.Writer、函数和 Core 等元素本身不会被复制.
+func (o *Options) Clone() *Options {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	c.OutputPaths = slices.Clone(o.OutputPaths)
+	c.ErrorOutputPaths = slices.Clone(o.ErrorOutputPaths)
+	c.RedactKeys = slices.Clone(o.RedactKeys)
+	c.FieldSchema = maps.Clone(o.FieldSchema)
+	c.EntryFilters = slices.Clone(o.EntryFilters)
+	c.FieldTransforms = slices.Clone(o.FieldTransforms)
+	c.SamplingSchedule = slices.Clone(o.SamplingSchedule)
+	c.ContextSinks = slices.Clone(o.ContextSinks)
+	c.LevelSinks = slices.Clone(o.LevelSinks)
+	c.KafkaBrokers = slices.Clone(o.KafkaBrokers)
+	c.HTTPOutputOptions = slices.Clone(o.HTTPOutputOptions)
+	c.Hooks = slices.Clone(o.Hooks)
+	c.Writers = slices.Clone(o.Writers)
+	c.ErrorWriters = slices.Clone(o.ErrorWriters)
+	c.LevelRules = maps.Clone(o.LevelRules)
+	c.RequestIDContextKeys = slices.Clone(o.RequestIDContextKeys)
+	c.LevelColors = maps.Clone(o.LevelColors)
+	c.EncryptionKey = slices.Clone(o.EncryptionKey)
+	if o.LevelOutputs != nil {
+		c.LevelOutputs = make([]LevelOutput, len(o.LevelOutputs))
+		for i, lo := range o.LevelOutputs {
+			lo.Paths = slices.Clone(lo.Paths)
+			c.LevelOutputs[i] = lo
+		}
+	}
+	if o.Sinks != nil {
+		c.Sinks = make([]Sink, len(o.Sinks))
+		for i, sink := range o.Sinks {
+			sink.Paths = slices.Clone(sink.Paths)
+			c.Sinks[i] = sink
+		}
+	}
+	if o.EncoderKeys != nil {
+		keys := *o.EncoderKeys
+		c.EncoderKeys = &keys
+	}
+	if o.ProcessFields != nil {
+		enabled := *o.ProcessFields
+		c.ProcessFields = &enabled
+	}
+	return &c
+}
+
+// Validate 检查配置是否有效，例如日志级别不能为空、日志格式必须是 json、console 或 gelf.
+// 启用 StrictPaths 时还会检查输出路径中的文件能否创建. Validate 不会创建任何文件或目录.
+func (o *Options) Validate() error {
+	if o == nil {
+		return fmt.Errorf("log options cannot be nil")
+	}
+
+	// 验证日志级别
+	if o.Level == "" {
+		return fmt.Errorf("log level cannot be empty")
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+		return fmt.Errorf("log level must be one of: debug, info, warn, error, dpanic, panic, fatal, got %s", o.Level)
+	}
+
+	// 验证日志格式，为空时使用 console 格式
+	switch o.Format {
+	case "", "json", "console", "gelf", "msgpack":
+	default:
+		return fmt.Errorf("log format must be one of: json, console, gelf, msgpack, got %s", o.Format)
+	}
+	for _, sink := range o.Sinks {
+		switch sink.Format {
+		case "", "json", "console", "gelf", "msgpack":
+		default:
+			return fmt.Errorf("log sink format must be one of: json, console, gelf, msgpack, got %s", sink.Format)
+		}
+		if err := level.UnmarshalText([]byte(sink.Level)); err != nil {
+			return fmt.Errorf("log sink level must be one of: debug, info, warn, error, dpanic, panic, fatal, got %s", sink.Level)
+		}
+	}
+	if o.StacktraceLevel != "" {
+		if err := level.UnmarshalText([]byte(o.StacktraceLevel)); err != nil {
+			return fmt.Errorf("log stacktrace level must be one of: debug, info, warn, error, dpanic, panic, fatal, got %s", o.StacktraceLevel)
+		}
+	}
+	if o.SamplingBelowLevel != "" {
+		if err := level.UnmarshalText([]byte(o.SamplingBelowLevel)); err != nil {
+			return fmt.Errorf("log sampling below level must be one of: debug, info, warn, error, dpanic, panic, fatal, got %s", o.SamplingBelowLevel)
+		}
+	}
+	if err := validateLevelColors(o.LevelColors); err != nil {
+		return err
+	}
+	if o.ErrorOutputFormat != "" && o.ErrorOutputFormat != "json" && o.ErrorOutputFormat != "console" {
+		return fmt.Errorf("log error output format must be one of: json, console, got %s", o.ErrorOutputFormat)
+	}
+
+	// 验证网络输出的协议
+	if o.NetworkAddr != "" {
+		switch o.NetworkOutput {
+		case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		default:
+			return fmt.Errorf("log network output must be one of: tcp, udp, got %s", o.NetworkOutput)
+		}
+	}
+
+	// 验证压缩算法
+	switch o.CompressAlgorithm {
+	case "", CompressGzip, CompressZstd:
+	default:
+		return fmt.Errorf("log compress algorithm must be one of: gzip, zstd, got %s", o.CompressAlgorithm)
+	}
+
+	// 验证加密密钥，AES 只支持 16、24 或 32 字节的密钥
+	switch len(o.EncryptionKey) {
+	case 0, 16, 24, 32:
+	default:
+		return fmt.Errorf("log encryption key must be 16, 24 or 32 bytes, got %d", len(o.EncryptionKey))
+	}
+
+	// 验证轮转配置
+	if o.MaxSize < 0 {
+		return fmt.Errorf("log max size must be non-negative, got %d", o.MaxSize)
+	}
+	if o.MaxAge < 0 {
+		return fmt.Errorf("log max age must be non-negative, got %d", o.MaxAge)
+	}
+	if o.MaxBackups < 0 {
+		return fmt.Errorf("log max backups must be non-negative, got %d", o.MaxBackups)
+	}
+
+	if !o.StrictPaths {
+		return nil
+	}
+	// 错误输出只支持 stdout 和 stderr，其他路径会被忽略
+	for _, path := range o.ErrorOutputPaths {
+		if !isConsolePath(path) {
+			return fmt.Errorf("log error output path must be stdout or stderr, got %q", path)
+		}
+	}
+	paths := append([]string{o.Filename}, o.OutputPaths...)
+	for _, lo := range o.LevelOutputs {
+		paths = append(paths, lo.Paths...)
+	}
+	for _, sink := range o.Sinks {
+		paths = append(paths, sink.Paths...)
+	}
+	for _, path := range paths {
+		if path == "" || isConsolePath(path) {
+			continue
+		}
+		if err := checkLogFilePath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isConsolePath 判断输出路径是否是 stdout 或 stderr.
+func isConsolePath(path string) bool {
+	switch strings.ToLower(path) {
+	case "stdout", "stderr":
+		return true
+	}
+	return false
+}
+
+// checkLogFilePath 检查日志文件能否创建: 文件已经存在时不能是目录，
+// 否则最近的已存在的上级路径必须是目录.
+func checkLogFilePath(path string) error {
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("log file %q is a directory", path)
+		}
+		return nil
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("log file %q: %q is not a directory", path, dir)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("log file %q: %w", path, err)
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return fmt.Errorf("log file %q: %w", path, err)
+		}
+	}
+}
+
 // WithLevel 设置日志级别.
 // 如果提供的级别无效，将使用默认的 "info" 级别.
 func WithLevel(level string) Option {
@@ -101,6 +532,14 @@ func WithFormat(format string) Option {
 	}
 }
 
+// WithColor 设置是否在控制台输出中使用彩色的日志级别.
+// 同时配置了文件输出时，文件中的日志级别不会包含颜色.
+func WithColor(color bool) Option {
+	return func(o *Options) {
+		o.Color = color
+	}
+}
+
 // WithOutputPaths 设置日志输出路径.
 func WithOutputPaths(paths []string) Option {
 	return func(o *Options) {
@@ -164,6 +603,27 @@ func WithCompress(compress bool) Option {
 	}
 }
 
+// WithCompressAlgorithm 设置压缩轮转后日志文件的算法，可选值为 CompressGzip 和 CompressZstd，并启用压缩.
+// 使用 zstd 时轮转由本包完成而不是 lumberjack，MaxBackups 和 MaxAge 同样作用于压缩后的文件.
+// 无效的算法会被忽略.
+func WithCompressAlgorithm(algorithm string) Option {
+	return func(o *Options) {
+		switch algorithm {
+		case CompressGzip, CompressZstd:
+			o.Compress = true
+			o.CompressAlgorithm = algorithm
+		}
+	}
+}
+
+// WithRotateInterval 设置按时间轮转日志文件的间隔.
+// 以天为单位的间隔按本地零点对齐，例如 WithRotateInterval(24 * time.Hour) 每天生成一个新文件.
+func WithRotateInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RotateInterval = d
+	}
+}
+
 // WithDevelopment 设置是否为开发模式.
 func WithDevelopment(development bool) Option {
 	return func(o *Options) {
@@ -171,6 +631,644 @@ func WithDevelopment(development bool) Option {
 	}
 }
 
+// WithErrorOutputEncoder 设置 logger 内部错误的输出格式.
+// 当错误输出与应用日志由同一管道收集时，可以使用 "json" 以便统一解析.
+func WithErrorOutputEncoder(format string) Option {
+	return func(o *Options) {
+		o.ErrorOutputFormat = format
+	}
+}
+
+// WithSampling 启用日志采样，防止热点路径在高负载下刷屏.
+// 每秒内相同级别和消息的日志，先完整记录前 initial 条，之后每 thereafter 条记录 1 条.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *Options) {
+		o.SamplingInitial = initial
+		o.SamplingThereafter = thereafter
+	}
+}
+
+// WithSamplingDisabled 禁用日志采样，这也是默认行为.
+func WithSamplingDisabled() Option {
+	return func(o *Options) {
+		o.SamplingInitial = 0
+		o.SamplingThereafter = 0
+	}
+}
+
+// WithRedactKeys 设置需要脱敏的字段名，例如 password, token, authorization.
+// 匹配的字段（不区分大小写）无论类型如何，其值都会在编码前被替换为 "***"，对所有输出格式生效.
+func WithRedactKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.RedactKeys = keys
+	}
+}
+
+// WithFieldSchema 声明字段的值类型，在开发模式下校验字段类型.
+// 例如声明 userID 为 reflect.Int 后，以字符串记录 userID 会额外输出一条 warn 日志.
+// 生产模式下不做任何校验.
+func WithFieldSchema(schema map[string]reflect.Kind) Option {
+	return func(o *Options) {
+		o.FieldSchema = schema
+	}
+}
+
+// WithEntryFilter 添加一个日志条目过滤函数，返回 false 时整条日志被丢弃.
+// 适用于丢弃无法修改的第三方库输出的健康检查等日志. dpanic, panic 和 fatal 级别的日志不会被丢弃.
+func WithEntryFilter(filter func(zapcore.Entry, []zapcore.Field) bool) Option {
+	return func(o *Options) {
+		if filter != nil {
+			o.EntryFilters = append(o.EntryFilters, filter)
+		}
+	}
+}
+
+// WithScheduledSampling 设置按一天中的时间段使用的采样参数.
+// 例如在业务高峰期积极采样以控制成本，在其他时间完整记录.
+// 不在任何时间段内时使用 WithSampling 的配置（默认不采样）.
+func WithScheduledSampling(schedule []SamplingWindow) Option {
+	return func(o *Options) {
+		o.SamplingSchedule = schedule
+	}
+}
+
+// WithContextSink 添加一个支持 context 的输出目标.
+// 通过 FromContext 获取的 logger 写入该目标时，会将请求的 context 传递给 WriteContext，
+// 这样正在关闭的请求不会因为慢速的远程输出而被阻塞.
+func WithContextSink(ws ContextWriteSyncer) Option {
+	return func(o *Options) {
+		if ws != nil {
+			o.ContextSinks = append(o.ContextSinks, ws)
+		}
+	}
+}
+
+// WithWindowsEventLog 将日志同时写入 Windows 事件日志，source 为事件源名称，未注册时会自动注册.
+// error 及以上级别对应错误事件，warn 对应警告事件，其他级别对应信息事件.
+// 在非 Windows 平台上，如果没有配置其他输出，日志会回退到 stderr.
+func WithWindowsEventLog(source string) Option {
+	return func(o *Options) {
+		o.EventLogSource = source
+	}
+}
+
+// WithLevelOutput 添加一个按级别路由的输出目标.
+// 级别不低于 minLevel 的日志会同时写入主输出和 paths，例如将 warn 及以上的日志另外写入 error.log.
+// 如果提供的级别无效，该选项将被忽略.
+func WithLevelOutput(minLevel string, paths []string) Option {
+	return func(o *Options) {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(minLevel)); err != nil {
+			return
+		}
+		o.LevelOutputs = append(o.LevelOutputs, LevelOutput{MinLevel: minLevel, Paths: paths})
+	}
+}
+
+// WithStringInterning 设置是否驻留日志消息和字段键.
+// 启用后重复出现的相同字符串共享同一份存储，在缓冲或异步输出日志时可以降低内存占用和 GC 压力.
+func WithStringInterning(enabled bool) Option {
+	return func(o *Options) {
+		o.StringInterning = enabled
+	}
+}
+
+// WithTimeFormat 设置日志时间的格式.
+// layout 可以是 time 包的布局字符串（例如 time.RFC3339Nano），也可以是快捷名称
+// "iso8601"、"iso8601millis"、"rfc3339"、"rfc3339nano"、"epoch"、"epochMillis" 或 "epochNanos".
+// 该格式同时作用于 console、json 编码器以及错误输出的编码.
+func WithTimeFormat(layout string) Option {
+	return func(o *Options) {
+		o.TimeFormat = layout
+	}
+}
+
+// WithISO8601Millis 设置日志时间使用毫秒精度、带冒号时区偏移的 ISO8601 格式，
+// 例如 2025-01-02T15:04:05.123+08:00，UTC 时间以 Z 结尾. 时区由 WithTimeZone 或 WithUTC 决定，
+// 便于比较不同地区服务的日志.
+func WithISO8601Millis() Option {
+	return WithTimeFormat("iso8601millis")
+}
+
+// WithTimeZone 设置日志时间使用的时区，例如 time.UTC. 传入 nil 时使用本地时区.
+func WithTimeZone(loc *time.Location) Option {
+	return func(o *Options) {
+		o.TimeZone = loc
+	}
+}
+
+// WithUTC 设置日志时间是否使用 UTC 时区.
+func WithUTC(utc bool) Option {
+	return func(o *Options) {
+		if utc {
+			o.TimeZone = time.UTC
+		} else {
+			o.TimeZone = nil
+		}
+	}
+}
+
+// WithLevelEncoder 设置日志级别的编码方式.
+// kind 可选值为 "capital"、"capitalColor"、"lowercase"、"color" 和 "stackdriver"，带颜色的编码方式作用于所有输出.
+// 如果提供的值无效，该选项将被忽略.
+func WithLevelEncoder(kind string) Option {
+	return func(o *Options) {
+		if _, ok := levelEncoders[kind]; ok {
+			o.LevelEncoder = kind
+		}
+	}
+}
+
+// WithLevelSink 添加一个按级别区间路由的远程输出目标.
+// 目标接收从 minLevel 开始、到其他目标中下一个更高的 minLevel 之前的日志，例如:
+//
+//	WithLevelSink("debug", cheap), WithLevelSink("warn", alerting)
+//
+// 会将 debug 和 info 写入 cheap，将 warn 及以上写入 alerting. 这些目标是主输出之外的额外输出.
+// 如果提供的级别无效或 sink.Writer 为 nil，该选项将被忽略.
+func WithLevelSink(minLevel string, sink SinkConfig) Option {
+	return func(o *Options) {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(minLevel)); err != nil || sink.Writer == nil {
+			return
+		}
+		o.LevelSinks = append(o.LevelSinks, LevelSink{MinLevel: minLevel, Sink: sink})
+	}
+}
+
+// WithSpanEvents 设置是否将日志同时记录为 OpenTelemetry span 事件.
+// 启用后，通过 FromContext 获取的 logger 在 context 中有正在记录的 span 时，会将日志作为 span 事件添加，
+// 字段转换为事件的属性，error 及以上级别的日志还会将 span 标记为错误. 没有正在记录的 span 时不做任何事情.
+func WithSpanEvents(enabled bool) Option {
+	return func(o *Options) {
+		o.SpanEvents = enabled
+	}
+}
+
+// WithBufferedWrites 为文件输出启用缓冲写入.
+// 日志先写入大小为 size 字节的缓冲区，缓冲区写满或每隔 flushInterval 时写入文件，
+// 调用 Sync 也会立即刷新缓冲区. 控制台输出仍然不缓冲，以保证交互式输出的及时性.
+// 程序退出前应调用 Close，避免缓冲区中的日志丢失. size 不大于 0 时该选项被忽略.
+func WithBufferedWrites(size int, flushInterval time.Duration) Option {
+	return func(o *Options) {
+		if size <= 0 {
+			return
+		}
+		o.BufferSize = size
+		o.FlushInterval = flushInterval
+	}
+}
+
+// WithKafkaOutput 将日志同时发送到 Kafka 的 topic，每条日志以 json 格式作为一条消息.
+// 消息按批次异步发送，broker 连接断开后会自动重连，发送失败的情况会写入错误输出.
+// 如果无法创建生产者（例如 brokers 为空），日志会回退到 ErrorOutputPaths.
+// 程序退出前应调用 Close，确保剩余的日志被发送.
+func WithKafkaOutput(brokers []string, topic string) Option {
+	return func(o *Options) {
+		o.KafkaBrokers = brokers
+		o.KafkaTopic = topic
+	}
+}
+
+// WithKafkaKeyField 使用字段 field 的值作为 Kafka 消息的 key，例如 "traceID"，
+// 这样同一个请求的日志会写入同一个分区，保持顺序.
+func WithKafkaKeyField(field string) Option {
+	return func(o *Options) {
+		o.KafkaKeyField = field
+	}
+}
+
+// WithHTTPOutput 将日志同时按批次 POST 到 url，请求体是以换行分隔的 json 日志，
+// 适用于 Loki、Datadog 等日志接入端点. 可以通过 opts 配置 header、gzip 压缩、批次大小和重试策略.
+// 发送在后台进行，重试后仍然失败的批次会被丢弃并写入错误输出，不会阻塞日志调用.
+// 调用 Sync 会发送所有待发送的日志，程序退出前应调用 Close.
+func WithHTTPOutput(url string, opts ...HTTPSinkOption) Option {
+	return func(o *Options) {
+		o.HTTPOutputURL = url
+		o.HTTPOutputOptions = opts
+	}
+}
+
+// WithSyslogOutput 将日志同时以 json 格式写入 syslog，严重性由日志级别决定，例如 error 为 LOG_ERR，
+// warn 为 LOG_WARNING. network 和 addr 都为空时写入本机的 syslog 服务，否则写入远程服务，
+// 例如 WithSyslogOutput("udp", "logs.example.com:514", "myapp"). tag 为空时使用程序名.
+// 连接在后台建立，不会阻塞 Init；无法连接或写入失败时日志回退到错误输出，并在后台自动重新连接.
+func WithSyslogOutput(network, addr, tag string) Option {
+	return func(o *Options) {
+		o.Syslog = true
+		o.SyslogNetwork = network
+		o.SyslogAddr = addr
+		o.SyslogTag = tag
+	}
+}
+
+// WithJournald 将日志同时通过 journald 原生协议写入 systemd journal. 日志级别映射为 PRIORITY，消息写入 MESSAGE，
+// 字段的键转换为大写的 journald 字段名，例如 traceID 写为 TRACEID，开头的下划线会被去掉.
+// 不在 systemd 下运行时输出一条提示到错误输出，并忽略 journald 输出.
+func WithJournald() Option {
+	return func(o *Options) {
+		o.Journald = true
+	}
+}
+
+// WithGELFHost 设置 gelf 格式日志的 host 字段，默认为 os.Hostname().
+func WithGELFHost(host string) Option {
+	return func(o *Options) {
+		o.GELFHost = host
+	}
+}
+
+// WithSentry 将不低于 minLevel 的日志作为事件发送到 Sentry，minLevel 为空或无效时使用 error.
+// 事件包含消息、级别、堆栈和日志字段，traceID 和 requestID 会作为事件的标签以便关联.
+// 事件在后台发送，Sentry 不可达时事件会被丢弃，不会阻塞日志调用. Sync 和 Close 最多等待 2 秒发送剩余事件.
+func WithSentry(dsn string, minLevel string) Option {
+	return func(o *Options) {
+		o.SentryDSN = dsn
+		o.SentryLevel = minLevel
+	}
+}
+
+// WithCallerSkip 设置记录调用者时额外跳过的调用层数. 在自己的辅助函数中封装本包的函数时，
+// 每多一层封装加 1，使调用者信息指向真实的调用位置. 禁用调用者信息时该选项无效.
+func WithCallerSkip(n int) Option {
+	return func(o *Options) {
+		o.CallerSkip = n
+	}
+}
+
+// WithEncoderKeys 设置日志中各个固定字段的字段名，字段名为空的字段不会输出.
+// 可以从 DefaultEncoderKeys 开始只修改需要的字段名.
+func WithEncoderKeys(keys EncoderKeys) Option {
+	return func(o *Options) {
+		o.EncoderKeys = &keys
+	}
+}
+
+// withEncoderKey 返回修改单个字段名的 Option，set 修改 EncoderKeys 中对应的字段名.
+func withEncoderKey(set func(keys *EncoderKeys)) Option {
+	return func(o *Options) {
+		if o.EncoderKeys == nil {
+			keys := DefaultEncoderKeys()
+			o.EncoderKeys = &keys
+		}
+		set(o.EncoderKeys)
+	}
+}
+
+// WithMessageKey 设置日志消息的字段名，为空时不输出日志消息.
+func WithMessageKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.MessageKey = key })
+}
+
+// WithLevelKey 设置日志级别的字段名，为空时不输出日志级别.
+func WithLevelKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.LevelKey = key })
+}
+
+// WithTimeKey 设置日志时间的字段名，为空时不输出日志时间.
+func WithTimeKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.TimeKey = key })
+}
+
+// WithCallerKey 设置调用者的字段名，为空时不输出调用者.
+func WithCallerKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.CallerKey = key })
+}
+
+// WithNameKey 设置 logger 名称的字段名，为空时不输出 logger 名称.
+func WithNameKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.NameKey = key })
+}
+
+// WithStacktraceKey 设置堆栈的字段名，为空时不输出堆栈. 默认不输出堆栈.
+func WithStacktraceKey(key string) Option {
+	return withEncoderKey(func(keys *EncoderKeys) { keys.StacktraceKey = key })
+}
+
+// WithStackdriver 配置 Google Cloud Logging 识别的 json 日志：字段名为 severity、message 和 time，
+// 级别编码为 Cloud Logging 的严重性，时间为 RFC3339 格式. FromContext 等添加的 traceID 字段改写为
+// logging.googleapis.com/trace 字段，值为 projects/<projectID>/traces/<traceID>，spanID 字段改写为
+// logging.googleapis.com/spanId，使日志与 Cloud Trace 中的链路关联.
+func WithStackdriver(projectID string) Option {
+	return func(o *Options) {
+		o.Format = "json"
+		o.LevelEncoder = "stackdriver"
+		o.TimeFormat = "rfc3339nano"
+		keys := DefaultEncoderKeys()
+		keys.MessageKey = "message"
+		keys.LevelKey = "severity"
+		keys.TimeKey = "time"
+		o.EncoderKeys = &keys
+		o.StackdriverProject = projectID
+	}
+}
+
+// WithCloudWatchOutput 将日志同时按批次发送到 CloudWatch Logs 的日志组 group 和日志流 stream，
+// 每条日志以 json 格式作为一条日志事件，时间字段为 ISO8601 格式的 timestamp. 日志流不存在时会自动创建.
+// 使用 AWS SDK 的默认凭证链（环境变量、共享配置文件、ECS 任务角色或 Lambda 执行角色），
+// 无法获取凭证时日志回退到 stderr. 发送在后台进行，不会阻塞日志调用，程序退出前应调用 Close.
+func WithCloudWatchOutput(group, stream string) Option {
+	return func(o *Options) {
+		o.CloudWatchGroup = group
+		o.CloudWatchStream = stream
+	}
+}
+
+// WithDedupFields 设置同一条日志中重复字段的处理策略，包括 With、FromContext 添加的字段和调用时传入的字段.
+// strategy 为 "last" 时保留最后出现的字段，例如调用时传入的 traceID 覆盖 FromContext 添加的 traceID;
+// 为 "first" 时保留最先出现的字段; 为 "error" 时保留最后出现的字段，并将重复的字段名写入错误输出.
+// 去重需要在每次写入时重新编码 With 添加的字段，会带来一定的性能开销. 如果提供的值无效，该选项将被忽略.
+func WithDedupFields(strategy string) Option {
+	return func(o *Options) {
+		switch strategy {
+		case DedupLast, DedupFirst, DedupError:
+			o.DedupFields = strategy
+		}
+	}
+}
+
+// WithRequestIDGenerator 设置 EnsureRequestID 和 HTTP 中间件生成 requestID 的函数，例如生成 ULID 或雪花 ID.
+// 生成函数返回空字符串时回退到 UUID v4.
+func WithRequestIDGenerator(gen func() string) Option {
+	return func(o *Options) {
+		o.RequestIDGenerator = gen
+	}
+}
+
+// WithHook 添加一个在每条日志写入后调用的回调函数，例如按级别统计日志数量:
+//
+//	log.WithHook(func(e zapcore.Entry) error {
+//		logsTotal.WithLabelValues(e.Level.String()).Inc()
+//		return nil
+//	})
+//
+// 回调函数对所有通过级别和采样的日志调用，多个回调函数按添加顺序调用.
+// 回调函数返回的错误写入错误输出，不会影响日志的写入和其他回调函数.
+func WithHook(hook func(zapcore.Entry) error) Option {
+	return func(o *Options) {
+		if hook != nil {
+			o.Hooks = append(o.Hooks, hook)
+		}
+	}
+}
+
+// WithMetrics 在创建日志记录器时将 PrometheusCollector 注册到 reg，例如 prometheus.DefaultRegisterer.
+// 多次 Init 或创建多个 Logger 时只会注册一次，不会因为重复注册而 panic. 注册失败时错误写入错误输出.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(o *Options) {
+		o.MetricsRegisterer = reg
+	}
+}
+
+// WithWriter 添加一个日志输出，例如管道、测试中的 bytes.Buffer 或者自定义的轮转写入器.
+// 写入时会加锁，w 不需要是线程安全的. 彩色的日志级别不会写入 w.
+func WithWriter(w io.Writer) Option {
+	return func(o *Options) {
+		if w != nil {
+			o.Writers = append(o.Writers, w)
+		}
+	}
+}
+
+// WithErrorWriter 添加一个错误输出，用于记录 logger 内部的错误. 写入时会加锁，w 不需要是线程安全的.
+func WithErrorWriter(w io.Writer) Option {
+	return func(o *Options) {
+		if w != nil {
+			o.ErrorWriters = append(o.ErrorWriters, w)
+		}
+	}
+}
+
+// WithFileMode 设置新建日志文件的权限，默认为 0600. 已经存在的文件保持原有的权限，
+// 轮转后的新文件沿用原文件的权限. 实际权限仍受进程 umask 的限制.
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *Options) {
+		o.FileMode = mode
+	}
+}
+
+// WithDirMode 设置新建日志目录的权限，默认为 0755. 实际权限仍受进程 umask 的限制.
+func WithDirMode(mode os.FileMode) Option {
+	return func(o *Options) {
+		o.DirMode = mode
+	}
+}
+
+// WithStrictPaths 设置 Validate 和 InitE 是否检查输出路径，启用后 ErrorOutputPaths 中 stdout 和 stderr
+// 以外的路径，以及无法创建的日志文件（例如上级路径是普通文件）都会被视为错误.
+func WithStrictPaths(strict bool) Option {
+	return func(o *Options) {
+		o.StrictPaths = strict
+	}
+}
+
+// WithDedupWindow 折叠连续重复的日志: 级别、消息和字段都相同的日志第一条立即写入，
+// 之后距上一条不超过 d 的重复日志被丢弃，安静了 d 之后输出一条消息带有 "(repeated N times)" 的汇总日志.
+// 重复达到 1000 条时也会立即输出汇总. 适用于依赖反复故障时持续产生相同错误日志的场景.
+func WithDedupWindow(d time.Duration) Option {
+	return func(o *Options) {
+		o.DedupWindow = d
+	}
+}
+
+// WithNetworkOutput 将 json 格式的日志逐行写入 TCP 或 UDP 连接，network 为 "tcp" 或 "udp".
+// 连接断开时日志写入 stderr，同时在后台按指数退避重新连接. Close 会关闭连接.
+func WithNetworkOutput(network, addr string) Option {
+	return func(o *Options) {
+		o.NetworkOutput = network
+		o.NetworkAddr = addr
+	}
+}
+
+// WithLevelRules 按 logger 名称设置日志级别，例如 {"http.*": "info", "db.query": "debug"}.
+// 名称按前缀匹配，最长的前缀优先，末尾的 ".*" 可以省略; 没有匹配的 logger 使用全局级别.
+// 无效的级别会被忽略. 之后仍可以通过 SetLoggerLevel 在运行时调整.
+func WithLevelRules(rules map[string]string) Option {
+	return func(o *Options) {
+		o.LevelRules = rules
+	}
+}
+
+// WithProcessFields 设置是否为每条日志添加 host 和 pid 字段. 未设置时只在非开发模式下添加.
+func WithProcessFields(enabled bool) Option {
+	return func(o *Options) {
+		o.ProcessFields = &enabled
+	}
+}
+
+// WithHostname 设置 host 字段的值，用于容器中 os.Hostname 返回容器 ID 等场景.
+// 未设置 GELFHost 时也作为 GELF 格式的 host.
+func WithHostname(hostname string) Option {
+	return func(o *Options) {
+		o.Hostname = hostname
+	}
+}
+
+// WithRequestIDContextKeys 设置查找 requestID 的其他 context key，例如 chi 的 middleware.RequestIDKey.
+// RequestIDFromContext 和 FromContext 先查找 ContextWithRequestID 保存的 requestID，
+// 不存在时再按注册顺序查找这些 key，只使用字符串类型的非空值. 多次调用会追加 key.
+func WithRequestIDContextKeys(keys ...interface{}) Option {
+	return func(o *Options) {
+		o.RequestIDContextKeys = append(o.RequestIDContextKeys, keys...)
+	}
+}
+
+// WithGoroutineID 设置是否为每条日志添加写入日志的 goroutine ID 字段 goid，用于排查并发问题.
+// goroutine ID 通过解析 runtime.Stack 的输出获取，每条日志都有额外的开销，默认关闭.
+func WithGoroutineID(enabled bool) Option {
+	return func(o *Options) {
+		o.GoroutineID = enabled
+	}
+}
+
+// WithTraceFieldNamespace 设置 FromContext 添加的 traceID、spanID 和 requestID 所在的嵌套对象的名称，
+// 例如 "trace" 时 json 格式输出 {"trace":{"traceID":"...","requestID":"..."}}，console 格式的字段部分同样为嵌套对象.
+// 为空时保持顶层字段. 设置后 WithStackdriver 和 Sentry 标签不会识别这些嵌套的字段.
+func WithTraceFieldNamespace(name string) Option {
+	return func(o *Options) {
+		o.TraceFieldNamespace = name
+	}
+}
+
+// WithConsoleSeparator 设置 console 格式中时间、级别、调用者、消息和字段之间的分隔符，例如单个空格.
+// 只影响 console 格式，json 格式忽略这个选项.
+func WithConsoleSeparator(sep string) Option {
+	return func(o *Options) {
+		o.ConsoleSeparator = sep
+	}
+}
+
+// WithFieldSampling 按字段 field 的值对日志采样，例如按 userID 采样，避免一个用户的大量日志挤占其他用户.
+// 每秒内相同级别和字段值的日志完整记录前 initial 条，之后每 thereafter 条记录 1 条，
+// 没有该字段的日志不采样. 字段可以在调用时传入，也可以通过 With 添加.
+// 最多同时记录 4096 个字段值的计数，超过一秒未出现的字段值会被清理.
+func WithFieldSampling(field string, initial, thereafter int) Option {
+	return func(o *Options) {
+		o.FieldSamplingKey = field
+		o.FieldSamplingInitial = initial
+		o.FieldSamplingThereafter = thereafter
+	}
+}
+
+// WithEncryptedFile 使用 AES-GCM 加密写入磁盘的日志文件，key 的长度必须为 16、24 或 32 字节.
+// Filename、OutputPaths 和 LevelOutputs 中的文件都会加密，每次写入加密为一条带有方案和 nonce 头部的记录，
+// 轮转和压缩作用于密文，可以使用 DecryptFile 解密. stdout、stderr、WithWriter 和远程输出仍然是明文.
+// 密钥无效时文件写入会失败并报告到错误输出，不会写入明文.
+func WithEncryptedFile(key []byte) Option {
+	return func(o *Options) {
+		o.EncryptionKey = append([]byte(nil), key...)
+	}
+}
+
+// WithFieldTransform 添加一个字段转换函数，用于全局改写或丢弃字段，例如生产环境去掉 debug_ 开头的字段，
+// 或者将 traceID 重命名为 trace_id. 转换函数返回 false 时丢弃该字段，多个转换函数按添加顺序依次应用.
+// 通过 With 添加的字段、自动添加的 traceID 和 requestID 以及每次调用传入的字段都会经过转换，
+// 转换在脱敏之后进行，因此重命名敏感字段不会泄露原始值.
+func WithFieldTransform(transform func(zapcore.Field) (zapcore.Field, bool)) Option {
+	return func(o *Options) {
+		if transform != nil {
+			o.FieldTransforms = append(o.FieldTransforms, transform)
+		}
+	}
+}
+
+// WithClock 设置日志时间戳和采样使用的时钟，主要用于在测试中注入固定的时钟以断言准确的时间戳.
+// 默认使用系统时钟.
+func WithClock(clock zapcore.Clock) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithContextDeadlineField 设置 FromContext 是否在 context 设置了截止时间时添加 deadlineRemaining 字段，
+// 值为调用 FromContext 时距离截止时间的剩余时间，已经超时时为负数，用于诊断超时. 没有截止时间时不添加.
+// 通过 ContextWithLogger 缓存的 logger 不会更新该字段.
+func WithContextDeadlineField(enable bool) Option {
+	return func(o *Options) {
+		o.ContextDeadlineField = enable
+	}
+}
+
+// WithSink 添加一个使用独立格式和级别的输出目标，与主输出和其他目标同时写入.
+// 例如同时向终端输出 info 及以上的彩色 console 日志、向文件输出 debug 及以上的 json 日志：
+//
+//	log.Init(log.WithLevel("debug"), log.WithOutputPaths(nil),
+//		log.WithSink([]string{"stdout"}, "console", "info"),
+//		log.WithSink([]string{"app.log"}, "json", "debug"))
+//
+// 日志同时受 logger 的级别限制，因此 logger 的级别需要不高于所有目标中最低的级别.
+// stdout 和 stderr 在启用 WithColor 或开发模式下连接终端时使用彩色的日志级别.
+// 不使用该选项时只有 Format 决定的主输出，行为不变.
+func WithSink(paths []string, format string, level string) Option {
+	return func(o *Options) {
+		o.Sinks = append(o.Sinks, Sink{Paths: slices.Clone(paths), Format: format, Level: level})
+	}
+}
+
+// WithStacktraceLevel 设置记录堆栈的最低日志级别，例如 "panic" 表示只有 panic 和 fatal 级别的日志记录堆栈.
+// 默认开发模式下为 warn，生产模式下为 error. 启用 DisableStacktrace 时该选项被忽略.
+func WithStacktraceLevel(level string) Option {
+	return func(o *Options) {
+		o.StacktraceLevel = level
+	}
+}
+
+// WithRingBuffer 在内存中保存最近写入的 n 条日志，可以通过 RecentLogs 获取，
+// 例如用于 /debug/recentlogs 接口或在崩溃报告中附加最近的日志. 日志使用主输出的格式编码，级别与主输出相同.
+func WithRingBuffer(n int) Option {
+	return func(o *Options) {
+		o.RingBufferSize = n
+	}
+}
+
+// WithLevelColors 设置彩色日志级别使用的颜色，例如:
+//
+//	log.WithLevelColors(map[string]string{"debug": "gray", "info": "default", "warn": "yellow", "error": "bold red"})
+//
+// 可用的颜色为 default、black、red、green、yellow、blue、magenta、cyan、white 和 gray，样式为 bold 和 underline.
+// 只在启用了彩色日志级别时生效（WithColor、开发模式下输出到终端，或者 capitalColor 和 color 编码方式），
+// json 格式和不使用颜色的输出不受影响.
+func WithLevelColors(colors map[string]string) Option {
+	return func(o *Options) {
+		o.LevelColors = maps.Clone(colors)
+	}
+}
+
+// WithSerializedWrites 设置是否加锁写入日志文件. 启用后每条日志在锁内完整写入，
+// 底层只写入了一部分时继续写入剩余部分，多个 goroutine 并发记录较大的日志时不会交错.
+// 加锁会增加少量开销，默认关闭.
+func WithSerializedWrites(enable bool) Option {
+	return func(o *Options) {
+		o.SerializedWrites = enable
+	}
+}
+
+// WithSamplingBelowLevel 启用只对低于 level 的日志采样，例如 WithSamplingBelowLevel("warn", 100, 100)
+// 对 debug 和 info 日志采样，warn 及以上的日志总是被记录. initial 和 thereafter 的含义与 SamplingInitial 和 SamplingThereafter 相同.
+func WithSamplingBelowLevel(level string, initial, thereafter int) Option {
+	return func(o *Options) {
+		o.SamplingBelowLevel = level
+		o.SamplingInitial = initial
+		o.SamplingThereafter = thereafter
+	}
+}
+
+// WithReplaceZapGlobals 设置 Init 时是否将全局日志记录器注册为 zap 的全局 logger，
+// 效果与调用 ReplaceGlobals 相同.
+func WithReplaceZapGlobals(enabled bool) Option {
+	return func(o *Options) {
+		o.ReplaceZapGlobals = enabled
+	}
+}
+
+// WithRedirectStdLog 设置 Init 时是否将标准库 log 包的输出重定向到全局日志记录器，
+// 效果与调用 RedirectStdLog 相同.
+func WithRedirectStdLog(enabled bool) Option {
+	return func(o *Options) {
+		o.RedirectStdLog = enabled
+	}
+}
+
 // LogConfigProvider 日志配置提供者接口
 // 用于统一不同包的 LogConfig 类型转换为 log.Options
 type LogConfigProvider interface {
@@ -245,10 +1343,10 @@ func (c *Config) Validate() error {
 
 	// 验证日志格式
 	validFormats := map[string]bool{
-		"json": true, "console": true,
+		"json": true, "console": true, "gelf": true, "msgpack": true,
 	}
 	if c.Format != "" && !validFormats[c.Format] {
-		return fmt.Errorf("log.format must be one of: json, console, got %s", c.Format)
+		return fmt.Errorf("log.format must be one of: json, console, gelf, msgpack, got %s", c.Format)
 	}
 
 	// 验证 MaxSize
@@ -0,0 +1,121 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRateLimitKeys 是 RateLimitedLogger 最多记录的键数量.
+// 超出后会淘汰最早记录的键，避免状态无限增长.
+const maxRateLimitKeys = 1024
+
+// RateLimitedLogger 按键限制日志的输出频率，每个键在一个时间窗口内最多输出一条日志.
+// 窗口内重复的日志会被丢弃，下一次允许输出时附加 suppressed 字段，记录期间丢弃的数量.
+// 适用于重连循环等会持续产生相同日志的场景. 日志写入全局日志记录器，这个类型是线程安全的.
+type RateLimitedLogger struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	states map[string]*rateLimitState
+	order  []string
+
+	// now 返回当前时间，测试中可以替换
+	now func() time.Time
+}
+
+// rateLimitState 记录一个键最后一次输出的时间和之后丢弃的数量.
+type rateLimitState struct {
+	last       time.Time
+	suppressed int
+}
+
+// NewRateLimitedLogger 创建一个每个键每隔 window 最多输出一条日志的 RateLimitedLogger.
+func NewRateLimitedLogger(window time.Duration) *RateLimitedLogger {
+	return &RateLimitedLogger{
+		window: window,
+		states: make(map[string]*rateLimitState),
+		now:    time.Now,
+	}
+}
+
+// Debug 在 key 允许输出时记录一条 debug 级别的日志.
+func (l *RateLimitedLogger) Debug(key, msg string, fields ...zap.Field) {
+	l.log(zapcore.DebugLevel, key, msg, fields)
+}
+
+// Info 在 key 允许输出时记录一条 info 级别的日志.
+func (l *RateLimitedLogger) Info(key, msg string, fields ...zap.Field) {
+	l.log(zapcore.InfoLevel, key, msg, fields)
+}
+
+// Warn 在 key 允许输出时记录一条 warn 级别的日志.
+func (l *RateLimitedLogger) Warn(key, msg string, fields ...zap.Field) {
+	l.log(zapcore.WarnLevel, key, msg, fields)
+}
+
+// Error 在 key 允许输出时记录一条 error 级别的日志.
+func (l *RateLimitedLogger) Error(key, msg string, fields ...zap.Field) {
+	l.log(zapcore.ErrorLevel, key, msg, fields)
+}
+
+// log 在 key 允许输出时记录日志，并附加之前丢弃的数量.
+func (l *RateLimitedLogger) log(lvl zapcore.Level, key, msg string, fields []zap.Field) {
+	suppressed, ok := l.allow(key)
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		// 避免修改调用者的切片
+		fields = append(fields[:len(fields):len(fields)], zap.Int("suppressed", suppressed))
+	}
+	// 跳过 log 和 Debug/Info/Warn/Error 两层调用，调用者信息指向 RateLimitedLogger 的使用者
+	if ce := std.Load().WithOptions(zap.AddCallerSkip(2)).Check(lvl, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// allow 判断 key 当前是否允许输出，允许时返回上一次输出之后丢弃的数量.
+func (l *RateLimitedLogger) allow(key string) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if s, ok := l.states[key]; ok {
+		if now.Sub(s.last) < l.window {
+			s.suppressed++
+			logDropped.WithLabelValues(dropReasonRateLimit).Inc()
+			return 0, false
+		}
+		suppressed := s.suppressed
+		s.last, s.suppressed = now, 0
+		return suppressed, true
+	}
+
+	// 新的键，超出上限时淘汰最早记录的键
+	if len(l.order) >= maxRateLimitKeys {
+		delete(l.states, l.order[0])
+		l.order = l.order[1:]
+	}
+	l.states[key] = &rateLimitState{last: now}
+	l.order = append(l.order, key)
+	return 0, true
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestRateLimitedLogger 测试窗口内重复的日志被丢弃，下一次输出时附加丢弃的数量.
+func TestRateLimitedLogger(t *testing.T) {
+	logs := observeStd(t)
+
+	now := time.Unix(0, 0)
+	l := NewRateLimitedLogger(10 * time.Second)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		l.Warn("reconnect", "reconnecting to broker")
+		now = now.Add(time.Second)
+	}
+	l.Info("other", "different key")
+	now = now.Add(5 * time.Second)
+	l.Warn("reconnect", "reconnecting to broker")
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()["suppressed"]; ok {
+		t.Errorf("第一条日志不应该有 suppressed 字段")
+	}
+	if got := entries[2].ContextMap()["suppressed"]; got != int64(4) {
+		t.Errorf("suppressed = %v, want 4", got)
+	}
+}
+
+// TestRateLimitedLoggerBounded 测试记录的键数量有上限.
+func TestRateLimitedLoggerBounded(t *testing.T) {
+	observeStd(t)
+
+	l := NewRateLimitedLogger(time.Hour)
+	for i := 0; i < maxRateLimitKeys+10; i++ {
+		l.Debug(fmt.Sprintf("key-%d", i), "message")
+	}
+	if len(l.states) != maxRateLimitKeys || len(l.order) != maxRateLimitKeys {
+		t.Errorf("states = %d, order = %d, want %d", len(l.states), len(l.order), maxRateLimitKeys)
+	}
+	if _, ok := l.states["key-0"]; ok {
+		t.Error("最早的键应该被淘汰")
+	}
+}
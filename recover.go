@@ -0,0 +1,147 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// exit 是 RecoverAndExit 退出进程的函数，测试中可以替换.
+var exit = os.Exit
+
+// RecoverOption 是配置 Recover 的函数.
+type RecoverOption func(*recoverOptions)
+
+// recoverOptions 是 Recover 的配置项.
+type recoverOptions struct {
+	repanic bool
+}
+
+// RecoverRepanic 设置记录日志后是否重新 panic，默认不重新 panic，即吞掉 panic.
+func RecoverRepanic(enabled bool) RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = enabled
+	}
+}
+
+// Recover 恢复当前 goroutine 的 panic，并使用 FromContext(ctx) 记录一条 error 级别的日志，
+// 日志包含 panic 的值和 panic 发生处的堆栈，调用者信息也指向 panic 发生的位置. 它必须直接被 defer 调用:
+//
+//	go func() {
+//		defer log.Recover(ctx)
+//		...
+//	}()
+//
+// 默认记录日志后吞掉 panic，使用 RecoverRepanic(true) 在记录后重新 panic.
+func Recover(ctx context.Context, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	logPanic(FromContext(ctx), zapcore.ErrorLevel, r)
+	if o.repanic {
+		panic(r)
+	}
+}
+
+// RecoverAndExit 恢复当前 goroutine 的 panic，记录一条 fatal 级别的日志，然后调用 Close 刷新日志并以状态码 1 退出.
+// 适合在 main goroutine 中直接被 defer 调用.
+func RecoverAndExit() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	logPanic(GetLogger().WithOptions(zap.WithFatalHook(recoverExitHook{})), zapcore.FatalLevel, r)
+}
+
+// recoverExitHook 是 RecoverAndExit 的 zapcore.CheckWriteHook，在日志写入后关闭全局日志记录器并退出.
+type recoverExitHook struct{}
+
+// OnWrite 实现 zapcore.CheckWriteHook 接口.
+func (recoverExitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	_ = Close()
+	exit(1)
+}
+
+// logPanic 使用 logger 记录被恢复的 panic.
+// 自动堆栈会指向 Recover 本身，因此关闭自动堆栈，改为记录 panic 发生处的堆栈.
+func logPanic(logger *zap.Logger, lvl zapcore.Level, r any) {
+	site, stack := panicStack()
+	never := zap.LevelEnablerFunc(func(zapcore.Level) bool { return false })
+	ce := logger.WithOptions(zap.AddStacktrace(never)).Check(lvl, "panic recovered")
+	if ce == nil {
+		return
+	}
+	if ce.Caller.Defined && site.PC != 0 {
+		ce.Caller = zapcore.NewEntryCaller(site.PC, site.File, site.Line, true)
+		ce.Caller.Function = site.Function
+	}
+	ce.Write(zap.Any("panic", r), zap.String("panicStack", stack))
+}
+
+// panicStack 返回 panic 发生处的栈帧和从该处开始的堆栈.
+// 被 defer 调用时，panic 发生处的栈帧仍在调用栈中，位于 runtime.gopanic 和运行时内部的栈帧之后.
+// 找不到 runtime.gopanic 时（例如不是在 panic 过程中调用），返回调用 Recover 处的堆栈.
+func panicStack() (runtime.Frame, string) {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	var all []runtime.Frame
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		all = append(all, frame)
+		if !more {
+			break
+		}
+	}
+
+	// panicStack、logPanic 和 Recover 之后的栈帧
+	start := min(3, len(all))
+	for i, frame := range all {
+		if frame.Function == "runtime.gopanic" {
+			start = i + 1
+			for start < len(all) && strings.HasPrefix(all[start].Function, "runtime.") {
+				start++
+			}
+			break
+		}
+	}
+	all = all[start:]
+	if len(all) == 0 {
+		return runtime.Frame{}, ""
+	}
+
+	var b strings.Builder
+	for i, frame := range all {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+	return all[0], b.String()
+}
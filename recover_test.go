@@ -0,0 +1,102 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// panicSite 在固定的位置 panic，用于检查记录的调用者和堆栈.
+func panicSite() {
+	var m map[string]int
+	m["boom"] = 1
+}
+
+// TestRecover 测试 Recover 记录 panic 的值、context 中的字段和 panic 发生处的堆栈，默认吞掉 panic.
+func TestRecover(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	prev := std.Swap(zap.New(core, zap.AddCaller()))
+	t.Cleanup(func() { std.Store(prev) })
+
+	func() {
+		defer Recover(ContextWithRequestID(context.Background(), "req-1"))
+		panicSite()
+	}()
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("entries = %v, want one error entry", entries)
+	}
+	e := entries[0]
+	fields := e.ContextMap()
+	if fields["requestID"] != "req-1" {
+		t.Errorf("requestID = %v, want req-1", fields["requestID"])
+	}
+	if !strings.Contains(fields["panic"].(string), "nil map") {
+		t.Errorf("panic = %v", fields["panic"])
+	}
+	stack, _ := fields["panicStack"].(string)
+	if !strings.HasPrefix(stack, "github.com/go-anyway/framework-log.panicSite\n") {
+		t.Errorf("panicStack should start at the panic site, got %q", stack)
+	}
+	if !strings.HasSuffix(e.Caller.Function, ".panicSite") || !strings.HasSuffix(e.Caller.File, "recover_test.go") {
+		t.Errorf("caller = %+v, want the panic site", e.Caller)
+	}
+}
+
+// TestRecoverRepanic 测试 RecoverRepanic 在记录日志后重新 panic.
+func TestRecoverRepanic(t *testing.T) {
+	logs := observeStd(t)
+
+	defer func() {
+		if r := recover(); r != "again" {
+			t.Errorf("recover() = %v, want again", r)
+		}
+		if logs.Len() != 1 {
+			t.Errorf("entries = %d, want 1", logs.Len())
+		}
+	}()
+	defer Recover(context.Background(), RecoverRepanic(true))
+	panic("again")
+}
+
+// TestRecoverAndExit 测试 RecoverAndExit 记录 fatal 级别的日志后以状态码 1 退出.
+func TestRecoverAndExit(t *testing.T) {
+	logs := observeStd(t)
+	code := -1
+	prevExit := exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = prevExit })
+
+	func() {
+		defer RecoverAndExit()
+		panic("fatal")
+	}()
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if logs.Len() != 1 || logs.All()[0].Level != zapcore.FatalLevel {
+		t.Errorf("entries = %v, want one fatal entry", logs.All())
+	}
+}
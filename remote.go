@@ -0,0 +1,149 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRemoteLevelBody 是远程级别配置响应体的最大长度.
+const maxRemoteLevelBody = 64 * 1024
+
+// defaultRemoteLevelInterval 是 interval 不大于 0 时拉取远程级别配置的间隔.
+const defaultRemoteLevelInterval = 30 * time.Second
+
+// remoteLevel 是远程级别配置的 JSON 格式.
+type remoteLevel struct {
+	// Level 是全局日志级别
+	Level string `json:"level"`
+	// Loggers 是按 logger 名称覆盖的日志级别
+	Loggers map[string]string `json:"loggers"`
+}
+
+// WatchRemoteLevel 按 interval 周期性地从 url 拉取日志级别并应用到全局 logger，返回停止拉取的函数.
+// 响应体可以是纯文本的级别（例如 "debug"），也可以是 JSON:
+//
+//	{"level": "info", "loggers": {"db": "debug"}}
+//
+// JSON 中的 loggers 会替换之前按 logger 名称设置的全部级别.
+// 请求失败、响应状态码不是 200 或内容无效时保留上一次生效的级别，并记录一条 warn 日志.
+// interval 不大于 0 时使用 30 秒.
+func WatchRemoteLevel(url string, interval time.Duration) func() {
+	if interval <= 0 {
+		interval = defaultRemoteLevelInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &http.Client{Timeout: interval}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := pollRemoteLevel(ctx, client, url); err != nil && ctx.Err() == nil {
+				Warn("poll remote log level failed", zap.String("url", url), zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// pollRemoteLevel 拉取一次远程级别配置并应用.
+func pollRemoteLevel(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteLevelBody))
+	if err != nil {
+		return err
+	}
+	return applyRemoteLevel(body)
+}
+
+// applyRemoteLevel 解析远程级别配置，全部有效时才应用到全局 logger.
+func applyRemoteLevel(body []byte) error {
+	body = bytes.TrimSpace(body)
+	cfg := remoteLevel{Level: string(body)}
+	isJSON := bytes.HasPrefix(body, []byte("{"))
+	if isJSON {
+		cfg = remoteLevel{}
+		if err := json.Unmarshal(body, &cfg); err != nil {
+			return fmt.Errorf("decode remote level: %w", err)
+		}
+	}
+
+	// JSON 中省略 level 时保持全局级别不变
+	if cfg.Level == "" && !isJSON {
+		return fmt.Errorf("empty remote level")
+	}
+	var level zapcore.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return err
+		}
+	}
+	named := make(map[string]zapcore.Level, len(cfg.Loggers))
+	for name, lvl := range cfg.Loggers {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(lvl)); err != nil {
+			return fmt.Errorf("logger %s: %w", name, err)
+		}
+		named[name] = l
+	}
+
+	d := currentLevel()
+	if cfg.Level != "" {
+		d.level.SetLevel(level)
+	}
+	if isJSON {
+		d.setNamed(named)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestWatchRemoteLevel 测试全局 logger 跟随远程返回的级别变化，请求失败时保留上一次的级别.
+func TestWatchRemoteLevel(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths([]string{"stderr"}))
+
+	var body atomic.Value
+	body.Store("debug")
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	defer srv.Close()
+
+	stop := WatchRemoteLevel(srv.URL, 10*time.Millisecond)
+	defer stop()
+
+	waitFor(t, func() bool { return std.Load().Core().Enabled(zapcore.DebugLevel) })
+
+	body.Store(`{"level": "error", "loggers": {"db": "debug"}}`)
+	waitFor(t, func() bool { return GetLevel() == "error" })
+	if Named("db").Check(zapcore.DebugLevel, "x") == nil {
+		t.Error("db logger 应该使用远程返回的 debug 级别")
+	}
+	if std.Load().Check(zapcore.WarnLevel, "x") != nil {
+		t.Error("全局 logger 应该使用远程返回的 error 级别")
+	}
+
+	// 失败时保留上一次生效的级别
+	fail.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	if GetLevel() != "error" {
+		t.Errorf("level = %s, want error after failures", GetLevel())
+	}
+
+	fail.Store(false)
+	body.Store("warn")
+	waitFor(t, func() bool { return GetLevel() == "warn" })
+
+	stop()
+	body.Store("debug")
+	time.Sleep(50 * time.Millisecond)
+	if GetLevel() != "warn" {
+		t.Errorf("level = %s, want warn after stop", GetLevel())
+	}
+}
+
+// TestWatchRemoteLevelDefaultInterval 测试 interval 不大于 0 时使用默认间隔，不会 panic.
+func TestWatchRemoteLevelDefaultInterval(t *testing.T) {
+	initStd(t, WithLevel("info"), WithOutputPaths([]string{"stderr"}))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("debug"))
+	}))
+	defer srv.Close()
+
+	stop := WatchRemoteLevel(srv.URL, 0)
+	defer stop()
+
+	waitFor(t, func() bool { return std.Load().Core().Enabled(zapcore.DebugLevel) })
+}
+
+// waitFor 等待 cond 成立，超时后测试失败.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// repeatSummaryThreshold 是连续重复的日志达到多少条时立即输出一条汇总，避免长时间没有输出.
+const repeatSummaryThreshold = 1000
+
+// repeatCore 是折叠连续重复日志的 zapcore.Core 包装器.
+// 级别、logger 名称、消息、调用者和字段都相同，并且距上一条不超过 window 的日志视为重复:
+// 第一条立即写入，之后的重复日志被丢弃，在安静了 window、出现不同的日志或重复达到阈值时，
+// 输出一条消息带有 "(repeated N times)" 的汇总日志.
+// 为了廉价地比较字段，每条日志用精简的 json Encoder 编码后计算哈希值.
+type repeatCore struct {
+	zapcore.Core
+	enc    zapcore.Encoder
+	state  *repeatState
+	errOut zapcore.WriteSyncer
+}
+
+// repeatState 记录最近一条写入的日志，通过 With 创建的 Core 共享同一个状态.
+type repeatState struct {
+	window time.Duration
+	errOut zapcore.WriteSyncer
+
+	mu         sync.Mutex
+	hash       uint64
+	last       time.Time
+	core       zapcore.Core
+	ent        zapcore.Entry
+	fields     []zapcore.Field
+	suppressed int
+	timer      *time.Timer
+}
+
+// newRepeatCore 创建一个在 window 内折叠连续重复日志的 repeatCore.
+func newRepeatCore(core zapcore.Core, window time.Duration, errOut zapcore.WriteSyncer) *repeatCore {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.EpochNanosTimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	})
+	return &repeatCore{
+		Core:   core,
+		enc:    enc,
+		state:  &repeatState{window: window, errOut: errOut},
+		errOut: errOut,
+	}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *repeatCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &repeatCore{Core: c.Core.With(fields), enc: enc, state: c.state, errOut: c.errOut}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *repeatCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *repeatCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	hash, err := c.hash(ent, fields)
+	if err != nil {
+		// 无法计算哈希值时不折叠，直接写入
+		writeThrough(c.Core, c.errOut, ent, fields)
+		return nil
+	}
+
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.core != nil && hash == s.hash && ent.Time.Sub(s.last) < s.window {
+		s.last = ent.Time
+		s.suppressed++
+		logDropped.WithLabelValues(dropReasonRepeat).Inc()
+		if s.suppressed >= repeatSummaryThreshold {
+			s.flush()
+		} else if s.timer == nil {
+			s.timer = time.AfterFunc(s.window, s.flushLocked)
+		} else {
+			s.timer.Reset(s.window)
+		}
+		return nil
+	}
+
+	s.flush()
+	writeThrough(c.Core, c.errOut, ent, fields)
+	s.hash, s.last, s.core, s.ent = hash, ent.Time, c.Core, ent
+	s.fields = append(s.fields[:0], fields...)
+	return nil
+}
+
+// Sync 实现 zapcore.Core 接口，先输出尚未输出的汇总日志.
+func (c *repeatCore) Sync() error {
+	c.state.flushLocked()
+	return c.Core.Sync()
+}
+
+// hash 返回日志的级别、logger 名称、消息、调用者和字段的哈希值，不包括时间和堆栈.
+func (c *repeatCore) hash(ent zapcore.Entry, fields []zapcore.Field) (uint64, error) {
+	buf, err := c.enc.EncodeEntry(zapcore.Entry{
+		Level:      ent.Level,
+		LoggerName: ent.LoggerName,
+		Message:    ent.Message,
+		Caller:     ent.Caller,
+	}, fields)
+	if err != nil {
+		return 0, err
+	}
+	defer buf.Free()
+	h := fnv.New64a()
+	_, _ = h.Write(buf.Bytes())
+	return h.Sum64(), nil
+}
+
+// flushLocked 加锁后输出尚未输出的汇总日志.
+func (s *repeatState) flushLocked() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flush()
+}
+
+// flush 输出尚未输出的汇总日志，调用者需要持有 s.mu.
+// 汇总日志使用重复日志的级别、消息和字段，时间为最后一次重复的时间.
+func (s *repeatState) flush() {
+	if s.suppressed == 0 {
+		return
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	ent := s.ent
+	ent.Message = fmt.Sprintf("%s (repeated %d times)", ent.Message, s.suppressed)
+	ent.Time = s.last
+	ent.Stack = ""
+	s.suppressed = 0
+	writeThrough(s.core, s.errOut, ent, s.fields)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestRepeatCore 测试连续重复的日志只写入第一条，出现不同的日志时输出汇总.
+func TestRepeatCore(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRepeatCore(obs, time.Hour, zapcore.AddSync(&bytes.Buffer{})))
+
+	for range 5 {
+		logger.Error("dial failed", zap.String("addr", "db:5432"))
+	}
+	logger.Error("dial failed", zap.String("addr", "cache:6379"))
+	logger.With(zap.String("pool", "primary")).Error("dial failed", zap.String("addr", "cache:6379"))
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	want := []string{"dial failed", "dial failed (repeated 4 times)", "dial failed", "dial failed"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if summary := logs.All()[1]; summary.ContextMap()["addr"] != "db:5432" || summary.Level != zapcore.ErrorLevel {
+		t.Errorf("summary = %+v, want the repeated entry's level and fields", summary)
+	}
+}
+
+// TestRepeatCoreQuietPeriod 测试安静了一个时间窗口后输出汇总，之后相同的日志重新立即写入.
+func TestRepeatCoreQuietPeriod(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRepeatCore(obs, 50*time.Millisecond, zapcore.AddSync(&bytes.Buffer{})))
+
+	for range 3 {
+		logger.Warn("flapping")
+	}
+	deadline := time.Now().Add(time.Second)
+	for logs.FilterMessage("flapping (repeated 2 times)").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no summary after the quiet period, got %d entries", logs.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	logger.Warn("flapping")
+	if n := logs.FilterMessage("flapping").Len(); n != 2 {
+		t.Errorf("entries after the quiet period = %d, want 2", n)
+	}
+}
+
+// TestRepeatCoreSync 测试 Sync 输出尚未输出的汇总日志.
+func TestRepeatCoreSync(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRepeatCore(obs, time.Hour, zapcore.AddSync(&bytes.Buffer{})))
+
+	logger.Info("same")
+	logger.Info("same")
+	_ = logger.Sync()
+
+	if logs.FilterMessage("same (repeated 1 times)").Len() != 1 {
+		t.Errorf("entries = %v, want a summary after Sync", logs.All())
+	}
+}
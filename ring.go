@@ -0,0 +1,90 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"sync"
+)
+
+// ringBuffer 是保存最近 N 条编码后日志的 zapcore.WriteSyncer.
+// 每个位置复用自己的字节切片，写满之后写入不再分配内存.
+type ringBuffer struct {
+	mu    sync.Mutex
+	slots [][]byte
+	next  int
+	full  bool
+}
+
+// newRingBuffer 创建一个保存最近 n 条日志的 ringBuffer.
+func newRingBuffer(n int) *ringBuffer {
+	return &ringBuffer{slots: make([][]byte, n)}
+}
+
+// Write 实现 io.Writer 接口，每次写入是一条编码后的日志，末尾的换行符被去掉.
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	r.mu.Lock()
+	r.slots[r.next] = append(r.slots[r.next][:0], line...)
+	r.next++
+	if r.next == len(r.slots) {
+		r.next, r.full = 0, true
+	}
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (r *ringBuffer) Sync() error {
+	return nil
+}
+
+// lines 按从旧到新的顺序返回保存的日志.
+func (r *ringBuffer) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	if r.full {
+		out = make([]string, 0, len(r.slots))
+		for _, b := range r.slots[r.next:] {
+			out = append(out, string(b))
+		}
+	} else {
+		out = make([]string, 0, r.next)
+	}
+	for _, b := range r.slots[:r.next] {
+		out = append(out, string(b))
+	}
+	return out
+}
+
+// RecentLogs 按从旧到新的顺序返回全局日志记录器最近写入的日志，每条日志使用主输出的格式编码.
+// 需要通过 WithRingBuffer 启用，否则返回 nil.
+func RecentLogs() []string {
+	if ring := stdOpts.Load().ring; ring != nil {
+		return ring.lines()
+	}
+	return nil
+}
+
+// RecentLogs 按从旧到新的顺序返回 Logger 最近写入的日志，需要通过 WithRingBuffer 启用，否则返回 nil.
+func (l *Logger) RecentLogs() []string {
+	if l.opts.ring != nil {
+		return l.opts.ring.lines()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRingBuffer 测试环形缓冲区按从旧到新的顺序保存最近 N 条日志.
+func TestRingBuffer(t *testing.T) {
+	l := NewLogger(WithOutputPaths(nil), WithFormat("json"), WithRingBuffer(3))
+	defer l.Close()
+
+	l.Info("first")
+	if got := l.RecentLogs(); len(got) != 1 || !strings.Contains(got[0], `"msg":"first"`) {
+		t.Fatalf("RecentLogs() = %q, want the first entry", got)
+	}
+	for i := range 5 {
+		l.Info(fmt.Sprintf("message %d", i))
+	}
+	l.Debug("below level")
+
+	got := l.RecentLogs()
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3", len(got))
+	}
+	for i, line := range got {
+		if want := fmt.Sprintf(`"msg":"message %d"`, i+2); !strings.Contains(line, want) || strings.HasSuffix(line, "\n") {
+			t.Errorf("entry %d = %q, want %s without newline", i, line, want)
+		}
+	}
+
+	if got := NewLogger(WithOutputPaths(nil)).RecentLogs(); got != nil {
+		t.Errorf("RecentLogs() without ring buffer = %q, want nil", got)
+	}
+}
+
+// TestRingBufferConcurrent 测试并发写入环形缓冲区.
+func TestRingBufferConcurrent(t *testing.T) {
+	r := newRingBuffer(8)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				_, _ = r.Write([]byte(fmt.Sprintf("%d-%d\n", i, j)))
+				_ = r.lines()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := r.lines(); len(got) != 8 {
+		t.Errorf("got %d entries, want 8", len(got))
+	}
+}
+
+// BenchmarkRingBuffer 测试写满之后写入环形缓冲区的开销.
+func BenchmarkRingBuffer(b *testing.B) {
+	r := newRingBuffer(128)
+	line := []byte(`{"level":"info","msg":"benchmark message"}` + "\n")
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = r.Write(line)
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	megabyte = 1024 * 1024
+	day      = 24 * time.Hour
+
+	// defaultMaxSize 是未配置 MaxSize 时的默认大小（以MB为单位），与 lumberjack 保持一致.
+	defaultMaxSize = 100
+	// lumberjackNoRotate 是交给 lumberjack 的 MaxSize，足够大以保证 lumberjack 自身不会触发轮转.
+	lumberjackNoRotate = 1 << 30
+)
+
+// 轮转后日志文件的压缩算法.
+const (
+	// CompressGzip 使用 gzip 压缩，压缩后的文件以 .gz 结尾.
+	CompressGzip = "gzip"
+	// CompressZstd 使用 zstd 压缩，压缩后的文件以 .zst 结尾，解压速度比 gzip 快.
+	CompressZstd = "zstd"
+)
+
+// compressAlgorithm 返回配置的压缩算法，未设置时使用 gzip.
+func compressAlgorithm(opts *Options) string {
+	if opts.CompressAlgorithm == "" {
+		return CompressGzip
+	}
+	return opts.CompressAlgorithm
+}
+
+// newFileWriter 根据配置创建写入 filename 的文件写入器.
+// 配置了 RotateInterval 或者使用 gzip 以外的压缩算法时使用 rotatingWriter，否则直接使用 lumberjack.
+func newFileWriter(filename string, opts *Options) io.Writer {
+	if opts.RotateInterval > 0 || (opts.Compress && compressAlgorithm(opts) != CompressGzip) {
+		return newRotatingWriter(filename, opts)
+	}
+	return &lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    opts.MaxSize,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAge,
+		Compress:   opts.Compress,
+	}
+}
+
+// rotatingWriter 是按时间间隔和文件大小轮转的文件写入器.
+// 文件的打开和写入仍由 lumberjack 完成，轮转、压缩和清理由 rotatingWriter 负责，
+// 轮转后的文件以所属时间段命名，例如 app-2025-01-02.log.
+// interval 为 0 时只按大小轮转，轮转后的文件以轮转时间命名，例如 app-2025-01-02T15-04-05.000.log.
+type rotatingWriter struct {
+	mu       sync.Mutex
+	file     *lumberjack.Logger
+	filename string
+	interval time.Duration
+	maxSize  int64
+
+	maxBackups int
+	maxAge     int
+	compress   bool
+	algorithm  string
+
+	// now 返回当前时间，测试中可以替换
+	now func() time.Time
+
+	size   int64
+	period time.Time // 当前文件所属时间段的起点
+	next   time.Time // 下一次按时间轮转的时间点
+
+	millMu sync.Mutex
+}
+
+// newRotatingWriter 创建一个 rotatingWriter.
+func newRotatingWriter(filename string, opts *Options) *rotatingWriter {
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+	w := &rotatingWriter{
+		file: &lumberjack.Logger{
+			Filename: filename,
+			MaxSize:  lumberjackNoRotate,
+		},
+		filename:   filename,
+		interval:   opts.RotateInterval,
+		maxSize:    int64(maxSize) * megabyte,
+		maxBackups: opts.MaxBackups,
+		maxAge:     opts.MaxAge,
+		compress:   opts.Compress,
+		algorithm:  compressAlgorithm(opts),
+		now:        time.Now,
+	}
+	w.init()
+	return w
+}
+
+// init 根据已存在的日志文件确定当前时间段和文件大小.
+// 上次运行遗留的文件如果属于更早的时间段，会在下一次写入时被轮转.
+func (w *rotatingWriter) init() {
+	modTime := w.now()
+	if info, err := os.Stat(w.filename); err == nil {
+		w.size = info.Size()
+		modTime = info.ModTime()
+	}
+	if w.interval > 0 {
+		w.period = w.periodStart(modTime)
+		w.next = w.nextPeriod(w.period)
+	}
+}
+
+// Write 实现 io.Writer 接口.
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	switch {
+	case w.interval > 0 && !now.Before(w.next):
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+		w.period = w.periodStart(now)
+		w.next = w.nextPeriod(w.period)
+	case w.size > 0 && w.size+int64(len(p)) > w.maxSize:
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close 关闭当前日志文件.
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// rotate 关闭当前文件并将其重命名为所属时间段的备份文件.
+// 新文件以原文件的权限创建，在下一次写入时由 lumberjack 打开.
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.size = 0
+	info, err := os.Stat(w.filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	stamp := w.period
+	if w.interval == 0 {
+		stamp = w.now()
+	}
+	if err := os.Rename(w.filename, w.backupName(stamp)); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	// 新文件沿用原文件的权限，与 lumberjack 按大小轮转的行为一致
+	if err == nil {
+		if f, err := os.OpenFile(w.filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm()); err == nil {
+			_ = f.Close()
+		}
+	}
+	go w.mill()
+	return nil
+}
+
+// backupName 返回时间段 period 对应的、尚未被占用的备份文件名.
+// 同一时间段内因大小多次轮转时，依次追加 .1, .2 等序号.
+func (w *rotatingWriter) backupName(period time.Time) string {
+	dir := filepath.Dir(w.filename)
+	prefix, ext := w.prefixAndExt()
+	stamp := period.Format(w.layout())
+
+	name := filepath.Join(dir, prefix+stamp+ext)
+	for i := 1; fileExists(name) || fileExists(name+".gz") || fileExists(name+".zst"); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s%s.%d%s", prefix, stamp, i, ext))
+	}
+	return name
+}
+
+// layout 返回备份文件名中时间戳的格式，精度与轮转间隔一致.
+func (w *rotatingWriter) layout() string {
+	switch {
+	case w.interval == 0:
+		return "2006-01-02T15-04-05.000"
+	case w.interval%day == 0:
+		return "2006-01-02"
+	case w.interval%time.Hour == 0:
+		return "2006-01-02T15"
+	case w.interval%time.Minute == 0:
+		return "2006-01-02T15-04"
+	default:
+		return "2006-01-02T15-04-05"
+	}
+}
+
+// periodStart 返回 t 所在时间段的起点.
+// 以天为单位的间隔按本地时区的零点对齐.
+func (w *rotatingWriter) periodStart(t time.Time) time.Time {
+	if w.interval%day == 0 {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+	return t.Truncate(w.interval)
+}
+
+// nextPeriod 返回 period 之后下一个时间段的起点.
+func (w *rotatingWriter) nextPeriod(period time.Time) time.Time {
+	if w.interval%day == 0 {
+		return period.AddDate(0, 0, int(w.interval/day))
+	}
+	return period.Add(w.interval)
+}
+
+// prefixAndExt 返回备份文件名的前缀和扩展名，例如 app.log 返回 "app-" 和 ".log".
+func (w *rotatingWriter) prefixAndExt() (string, string) {
+	base := filepath.Base(w.filename)
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// mill 压缩轮转后的文件，并根据 MaxBackups 和 MaxAge 清理旧文件.
+func (w *rotatingWriter) mill() {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups := w.backups()
+	var remaining []backupFile
+	cutoff := w.now().Add(-time.Duration(w.maxAge) * day)
+	for i, b := range backups {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && b.modTime.Before(cutoff)) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		remaining = append(remaining, b)
+	}
+
+	if !w.compress {
+		return
+	}
+	for _, b := range remaining {
+		if strings.HasSuffix(b.path, ".gz") || strings.HasSuffix(b.path, ".zst") {
+			continue
+		}
+		if w.algorithm == CompressZstd {
+			_ = zstdFile(b.path)
+		} else {
+			_ = gzipFile(b.path)
+		}
+	}
+}
+
+// backupFile 描述一个轮转后的备份文件.
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// backups 返回所有备份文件，按修改时间从新到旧排序.
+func (w *rotatingWriter) backups() []backupFile {
+	dir := filepath.Dir(w.filename)
+	prefix, ext := w.prefixAndExt()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !w.isBackup(name, prefix, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	return files
+}
+
+// isBackup 判断 name 是否符合 backupName 生成的格式：prefix + 时间戳[.N] + ext[.gz|.zst].
+// 时间戳无法按 layout 解析的同目录文件（例如 app-error.log）不视为备份.
+func (w *rotatingWriter) isBackup(name, prefix, ext string) bool {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".zst")
+	if len(name) <= len(prefix)+len(ext) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return false
+	}
+	stamp := name[len(prefix) : len(name)-len(ext)]
+	if _, err := time.ParseInLocation(w.layout(), stamp, time.Local); err == nil {
+		return true
+	}
+	i := strings.LastIndexByte(stamp, '.')
+	if i < 0 {
+		return false
+	}
+	if n, err := strconv.Atoi(stamp[i+1:]); err != nil || n < 1 {
+		return false
+	}
+	_, err := time.ParseInLocation(w.layout(), stamp[:i], time.Local)
+	return err == nil
+}
+
+// gzipFile 将文件压缩为 path.gz 并删除原文件.
+func gzipFile(path string) error {
+	return compressFile(path, ".gz", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+}
+
+// zstdFile 将文件压缩为 path.zst 并删除原文件.
+func zstdFile(path string) error {
+	return compressFile(path, ".zst", func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w)
+	})
+}
+
+// compressFile 使用 newWriter 创建的压缩器将文件压缩为 path+ext，成功后删除原文件.
+// 压缩失败时删除不完整的压缩文件，保留原文件.
+func compressFile(path, ext string, newWriter func(io.Writer) (io.WriteCloser, error)) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+ext, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path + ext)
+		}
+	}()
+
+	cw, err := newWriter(dst)
+	if err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if _, err = io.Copy(cw, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = cw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	// 保留原文件的修改时间，清理备份时按修改时间排序
+	_ = os.Chtimes(path+ext, info.ModTime(), info.ModTime())
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// fileExists 判断文件是否存在.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
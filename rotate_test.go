@@ -0,0 +1,261 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// TestRotatingWriterDaily 测试按天轮转并以日期命名备份文件.
+func TestRotatingWriterDaily(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	now := time.Date(2025, 1, 2, 23, 59, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.RotateInterval = day
+	w := newRotatingWriter(filename, opts)
+	w.now = func() time.Time { return now }
+	w.init()
+	defer w.Close()
+
+	if _, err := w.Write([]byte("day one\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := w.Write([]byte("day two\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(dir, "app-2025-01-02.log"))
+	if err != nil {
+		t.Fatalf("读取备份文件失败: %v", err)
+	}
+	if string(backup) != "day one\n" {
+		t.Errorf("backup = %q, want %q", backup, "day one\n")
+	}
+	current, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("读取当前文件失败: %v", err)
+	}
+	if string(current) != "day two\n" {
+		t.Errorf("current = %q, want %q", current, "day two\n")
+	}
+}
+
+// TestRotatingWriterSize 测试在同一时间段内按大小轮转时追加序号.
+func TestRotatingWriterSize(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.RotateInterval = day
+	w := newRotatingWriter(filename, opts)
+	w.now = func() time.Time { return now }
+	w.maxSize = 10
+	w.init()
+	defer w.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	for _, name := range []string{"app-2025-01-02.log", "app-2025-01-02.1.log"} {
+		if !fileExists(filepath.Join(dir, name)) {
+			t.Errorf("备份文件 %s 不存在", name)
+		}
+	}
+}
+
+// TestRotatingWriterFileMode 测试轮转后的新文件沿用原文件的权限.
+func TestRotatingWriterFileMode(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(filename, nil, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 2, 23, 59, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.RotateInterval = day
+	w := newRotatingWriter(filename, opts)
+	w.now = func() time.Time { return now }
+	w.init()
+	defer w.Close()
+
+	if _, err := w.Write([]byte("day one\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := w.Write([]byte("day two\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o640))
+	}
+}
+
+// TestRotatingWriterZstd 测试只按大小轮转时使用 zstd 压缩备份文件，并按 MaxBackups 清理压缩后的文件.
+func TestRotatingWriterZstd(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.MaxBackups = 2
+	WithCompressAlgorithm(CompressZstd)(opts)
+	w, ok := newFileWriter(filename, opts).(*rotatingWriter)
+	if !ok {
+		t.Fatal("zstd 压缩应该使用 rotatingWriter")
+	}
+	w.now = func() time.Time { return now }
+	w.maxSize = 10
+	defer w.Close()
+
+	for i := range 5 {
+		now = now.Add(time.Second)
+		if _, err := w.Write([]byte(fmt.Sprintf("line %d...\n", i))); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	var backups []string
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w.millMu.Lock()
+		backups = backups[:0]
+		for _, b := range w.backups() {
+			backups = append(backups, filepath.Base(b.path))
+		}
+		w.millMu.Unlock()
+		if len(backups) == 2 && strings.HasSuffix(backups[0], ".zst") && strings.HasSuffix(backups[1], ".zst") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backups = %v, want 2 zstd files", backups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if backups[0] != "app-2025-01-02T10-00-05.000.log.zst" {
+		t.Errorf("newest backup = %s, want app-2025-01-02T10-00-05.000.log.zst", backups[0])
+	}
+	f, err := os.Open(filepath.Join(dir, backups[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dec, err := zstd.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dec.Close()
+	content, err := io.ReadAll(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "line 3...\n" {
+		t.Errorf("backup content = %q, want %q", content, "line 3...\n")
+	}
+}
+
+// TestRotatingWriterSiblingLog 测试同目录下前缀相同的其他日志文件不会被当作备份压缩或清理.
+func TestRotatingWriterSiblingLog(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	sibling := filepath.Join(dir, "app-error.log")
+	if err := os.WriteFile(sibling, []byte("live\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.Local)
+	opts := NewOptions()
+	opts.RotateInterval = day
+	opts.MaxBackups = 1
+	opts.Compress = true
+	w := newRotatingWriter(filename, opts)
+	w.now = func() time.Time { return now }
+	w.init()
+	defer w.Close()
+
+	for i := range 3 {
+		if _, err := w.Write([]byte(fmt.Sprintf("day %d\n", i))); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+	if _, err := w.Write([]byte("today\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	var backups []string
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w.millMu.Lock()
+		backups = backups[:0]
+		for _, b := range w.backups() {
+			backups = append(backups, filepath.Base(b.path))
+		}
+		w.millMu.Unlock()
+		if len(backups) == 1 && strings.HasSuffix(backups[0], ".gz") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backups = %v, want 1 gzip file", backups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	content, err := os.ReadFile(sibling)
+	if err != nil {
+		t.Fatalf("app-error.log 被删除: %v", err)
+	}
+	if string(content) != "live\n" {
+		t.Errorf("app-error.log = %q, want %q", content, "live\n")
+	}
+	if fileExists(sibling + ".gz") {
+		t.Error("app-error.log 不应该被压缩")
+	}
+}
+
+// TestRotatingWriterIsBackup 测试备份文件名的识别.
+func TestRotatingWriterIsBackup(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		name     string
+		want     bool
+	}{
+		{day, "app-2025-01-02.log", true},
+		{day, "app-2025-01-02.3.log.gz", true},
+		{day, "app-2025-01-02.log.zst", true},
+		{day, "app-error.log", false},
+		{day, "app-2025-01-02.x.log", false},
+		{day, "app-.log", false},
+		{time.Hour, "app-2025-01-02T10.log", true},
+		{0, "app-2025-01-02T10-00-05.000.log", true},
+		{0, "app-2025-01-02T10-00-05.000.2.log.gz", true},
+		{0, "app-2025-01-02.log", false},
+	}
+	for _, tt := range tests {
+		w := &rotatingWriter{filename: "app.log", interval: tt.interval}
+		prefix, ext := w.prefixAndExt()
+		if got := w.isBackup(tt.name, prefix, ext); got != tt.want {
+			t.Errorf("isBackup(%q) with interval %v = %v, want %v", tt.name, tt.interval, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxFieldSamplingKeys 是按字段值采样时最多记录的键数量.
+// 超出后先清理已经过期的键，仍然超出时清空所有状态，避免状态无限增长.
+const maxFieldSamplingKeys = 4096
+
+// SamplingWindow 定义一天中某个时间段内使用的采样参数.
+type SamplingWindow struct {
+	// Start 是时间段的起点，表示相对于本地零点的偏移，例如 9 * time.Hour 表示 09:00.
+	Start time.Duration
+	// End 是时间段的终点（不包含）. 小于 Start 时表示跨越零点的时间段，例如 22:00 到次日 06:00.
+	End time.Duration
+	// Initial 是每秒内相同级别和消息的日志中，完整记录的前 N 条.
+	Initial int
+	// Thereafter 是超过 Initial 之后，每 N 条相同的日志记录 1 条.
+	Thereafter int
+}
+
+// contains 判断一天中的偏移 offset 是否落在时间段内.
+func (w SamplingWindow) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// scheduledSamplerCore 是按一天中的时间段选择采样参数的 zapcore.Core 包装器.
+// 每个时间段使用独立的采样器，不在任何时间段内时使用 fallback.
+type scheduledSamplerCore struct {
+	zapcore.Core
+	windows  []SamplingWindow
+	samplers []zapcore.Core
+	clock    zapcore.Clock
+}
+
+// newScheduledSamplerCore 创建一个按时间段采样的 Core.
+// core 是未采样的 Core，fallback 是不在任何时间段内时使用的 Core.
+func newScheduledSamplerCore(core, fallback zapcore.Core, windows []SamplingWindow, clock zapcore.Clock) zapcore.Core {
+	samplers := make([]zapcore.Core, len(windows))
+	for i, w := range windows {
+		samplers[i] = zapcore.NewSamplerWithOptions(core, time.Second, w.Initial, w.Thereafter, zapcore.SamplerHook(countSampled))
+	}
+	return &scheduledSamplerCore{
+		Core:     fallback,
+		windows:  windows,
+		samplers: samplers,
+		clock:    clock,
+	}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *scheduledSamplerCore) With(fields []zapcore.Field) zapcore.Core {
+	samplers := make([]zapcore.Core, len(c.samplers))
+	for i, s := range c.samplers {
+		samplers[i] = s.With(fields)
+	}
+	return &scheduledSamplerCore{
+		Core:     c.Core.With(fields),
+		windows:  c.windows,
+		samplers: samplers,
+		clock:    c.clock,
+	}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *scheduledSamplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.active().Check(ent, ce)
+}
+
+// active 返回当前时间所在时间段的采样器.
+func (c *scheduledSamplerCore) active() zapcore.Core {
+	now := c.clock.Now()
+	y, m, d := now.Date()
+	offset := now.Sub(time.Date(y, m, d, 0, 0, 0, 0, now.Location()))
+	for i, w := range c.windows {
+		if w.contains(offset) {
+			return c.samplers[i]
+		}
+	}
+	return c.Core
+}
+
+// fieldSamplerCore 是按字段值对日志采样的 zapcore.Core 包装器.
+// 每秒内同一级别、字段值相同的日志完整记录前 initial 条，之后每 thereafter 条记录 1 条.
+// 本次调用传入的字段只在 Write 中可见，因此在 Write 中决定是否丢弃，没有该字段的日志不采样.
+type fieldSamplerCore struct {
+	zapcore.Core
+	errOut zapcore.WriteSyncer
+	field  string
+	// value 是通过 With 添加的字段的值，hasValue 表示是否添加过
+	value    string
+	hasValue bool
+	state    *fieldSamplerState
+}
+
+// fieldSamplerState 是所有派生的 fieldSamplerCore 共享的计数状态.
+type fieldSamplerState struct {
+	initial    uint64
+	thereafter uint64
+	clock      zapcore.Clock
+
+	mu     sync.Mutex
+	counts map[fieldSampleKey]*fieldSampleCount
+}
+
+// fieldSampleKey 是采样计数的键.
+type fieldSampleKey struct {
+	level zapcore.Level
+	value string
+}
+
+// fieldSampleCount 记录一个键在当前一秒内的日志数量.
+type fieldSampleCount struct {
+	resetAt time.Time
+	n       uint64
+}
+
+// newFieldSamplerCore 创建一个按字段 field 的值采样的 fieldSamplerCore.
+func newFieldSamplerCore(core zapcore.Core, field string, initial, thereafter int, clock zapcore.Clock, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &fieldSamplerCore{
+		Core:   core,
+		errOut: errOut,
+		field:  field,
+		state: &fieldSamplerState{
+			initial:    uint64(max(initial, 0)),
+			thereafter: uint64(max(thereafter, 0)),
+			clock:      clock,
+			counts:     make(map[fieldSampleKey]*fieldSampleCount),
+		},
+	}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *fieldSamplerCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if value, ok := fieldValue(fields, c.field); ok {
+		clone.value, clone.hasValue = value, true
+	}
+	return &clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *fieldSamplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *fieldSamplerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	value, ok := fieldValue(fields, c.field)
+	if !ok {
+		value, ok = c.value, c.hasValue
+	}
+	if ok && !c.state.allow(fieldSampleKey{level: ent.Level, value: value}) {
+		logDropped.WithLabelValues(dropReasonSampling).Inc()
+		return nil
+	}
+	writeThrough(c.Core, c.errOut, ent, fields)
+	return nil
+}
+
+// allow 判断 key 的这条日志是否应该记录.
+func (s *fieldSamplerState) allow(key fieldSampleKey) bool {
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cnt, ok := s.counts[key]
+	if !ok {
+		if len(s.counts) >= maxFieldSamplingKeys {
+			s.evictLocked(now)
+		}
+		cnt = &fieldSampleCount{}
+		s.counts[key] = cnt
+	}
+	if !now.Before(cnt.resetAt) {
+		cnt.resetAt, cnt.n = now.Add(time.Second), 0
+	}
+	cnt.n++
+	if cnt.n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (cnt.n-s.initial)%s.thereafter == 0
+}
+
+// evictLocked 清理已经过期的键，没有过期的键时清空所有状态.
+func (s *fieldSamplerState) evictLocked(now time.Time) {
+	for key, cnt := range s.counts {
+		if !now.Before(cnt.resetAt) {
+			delete(s.counts, key)
+		}
+	}
+	if len(s.counts) >= maxFieldSamplingKeys {
+		clear(s.counts)
+	}
+}
+
+// fieldValue 返回 fields 中最后一个名为 key 的字段的值.
+func fieldValue(fields []zapcore.Field, key string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Key != key || f.Type == zapcore.SkipType || f.Type == zapcore.NamespaceType {
+			continue
+		}
+		if f.Type == zapcore.StringType {
+			return f.String, true
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return fmt.Sprint(enc.Fields[key]), true
+	}
+	return "", false
+}
+
+// noSampleMarker 是 NoSample 字段携带的值.
+type noSampleMarker struct{}
+
+// sampleMarker 是 Sample 字段携带的值.
+type sampleMarker struct {
+	n uint64
+}
+
+// NoSample 返回一个标记字段，带有该字段的日志不经过采样，总是被记录，例如采样开启时也必须保留的关键日志.
+// 该字段的类型为 zapcore.SkipType，不会出现在输出中. 通过 With 添加时，派生 logger 的所有日志都不采样.
+func NoSample() zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: noSampleMarker{}}
+}
+
+// Sample 返回一个标记字段，带有该字段的日志不经过全局采样，而是相同级别和消息的日志每 n 条记录 1 条（包括第一条），
+// 用于已知会大量重复的调用. n 小于等于 1 时所有日志都被记录.
+// 该字段的类型为 zapcore.SkipType，不会出现在输出中.
+func Sample(n int) zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: sampleMarker{n: uint64(max(n, 1))}}
+}
+
+// sampleOverrideCore 是识别 NoSample 和 Sample 字段的 zapcore.Core 包装器.
+// 本次调用传入的字段只在 Write 中可见，因此 Check 总是添加自身，在 Write 中选择经过采样的 Core 或未采样的 Core.
+type sampleOverrideCore struct {
+	zapcore.Core
+	unsampled zapcore.Core
+	// sampling 表示 Core 是否经过采样，为 false 时 Core 与 unsampled 相同
+	sampling bool
+	level    *dynamicLevel
+	// override 是通过 With 添加的标记，为 nil 表示没有添加
+	override interface{}
+	errOut   zapcore.WriteSyncer
+	state    *callSampleState
+}
+
+// callSampleState 是 Sample 字段的计数状态，所有派生的 sampleOverrideCore 共享.
+type callSampleState struct {
+	mu     sync.Mutex
+	counts map[callSampleKey]uint64
+}
+
+// callSampleKey 是 Sample 字段计数的键.
+type callSampleKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// newSampleOverrideCore 创建一个 sampleOverrideCore. sampled 是经过采样的 Core，unsampled 是采样之前的 Core，
+// level 用于在 Check 中按 logger 名称判断级别.
+func newSampleOverrideCore(sampled, unsampled zapcore.Core, level *dynamicLevel, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &sampleOverrideCore{
+		Core:      sampled,
+		unsampled: unsampled,
+		sampling:  sampled != unsampled,
+		level:     level,
+		errOut:    errOut,
+		state:     &callSampleState{counts: make(map[callSampleKey]uint64)},
+	}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *sampleOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.unsampled = clone.Core
+	if c.sampling {
+		clone.unsampled = c.unsampled.With(fields)
+	}
+	if marker := sampleOverride(fields); marker != nil {
+		clone.override = marker
+	}
+	return &clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *sampleOverrideCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= c.level.levelFor(ent.LoggerName) && c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *sampleOverrideCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	marker := sampleOverride(fields)
+	if marker == nil {
+		marker = c.override
+	}
+	switch m := marker.(type) {
+	case noSampleMarker:
+		writeThrough(c.unsampled, c.errOut, ent, fields)
+	case sampleMarker:
+		if !c.state.allow(callSampleKey{level: ent.Level, message: ent.Message}, m.n) {
+			logDropped.WithLabelValues(dropReasonSampling).Inc()
+			return nil
+		}
+		writeThrough(c.unsampled, c.errOut, ent, fields)
+	default:
+		writeThrough(c.Core, c.errOut, ent, fields)
+	}
+	return nil
+}
+
+// allow 判断 key 的这条日志是否应该记录，每 n 条记录 1 条.
+// 键的数量超过 maxFieldSamplingKeys 时清空所有状态.
+func (s *callSampleState) allow(key callSampleKey, n uint64) bool {
+	if n <= 1 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cnt, ok := s.counts[key]
+	if !ok && len(s.counts) >= maxFieldSamplingKeys {
+		clear(s.counts)
+	}
+	s.counts[key] = cnt + 1
+	return cnt%n == 0
+}
+
+// sampleOverride 返回 fields 中最后一个 NoSample 或 Sample 字段携带的标记，没有时返回 nil.
+func sampleOverride(fields []zapcore.Field) interface{} {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Type != zapcore.SkipType {
+			continue
+		}
+		switch fields[i].Interface.(type) {
+		case noSampleMarker, sampleMarker:
+			return fields[i].Interface
+		}
+	}
+	return nil
+}
+
+// belowLevelSamplerCore 是只对低于指定级别的日志采样的 zapcore.Core 包装器.
+// 低于 level 的日志交给经过采样的 Core，其他日志交给未采样的 Core，总是被记录.
+type belowLevelSamplerCore struct {
+	zapcore.Core
+	unsampled zapcore.Core
+	level     zapcore.Level
+}
+
+// newBelowLevelSamplerCore 创建一个 belowLevelSamplerCore. sampled 是经过采样的 Core，unsampled 是采样之前的 Core.
+func newBelowLevelSamplerCore(sampled, unsampled zapcore.Core, level zapcore.Level) zapcore.Core {
+	return &belowLevelSamplerCore{Core: sampled, unsampled: unsampled, level: level}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *belowLevelSamplerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &belowLevelSamplerCore{Core: c.Core.With(fields), unsampled: c.unsampled.With(fields), level: c.level}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *belowLevelSamplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.level {
+		return c.Core.Check(ent, ce)
+	}
+	return c.unsampled.Check(ent, ce)
+}
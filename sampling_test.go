@@ -0,0 +1,157 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeClock 是返回固定时间的 zapcore.Clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                         { return c.now }
+func (c *fakeClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+// TestScheduledSampling 测试采样参数在时间段边界切换.
+func TestScheduledSampling(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	clock := &fakeClock{now: time.Date(2025, 1, 2, 17, 59, 59, 0, time.Local)}
+	schedule := []SamplingWindow{
+		{Start: 9 * time.Hour, End: 18 * time.Hour, Initial: 1, Thereafter: 0},
+		{Start: 18 * time.Hour, End: 9 * time.Hour, Initial: 100, Thereafter: 100},
+	}
+	logger := zap.New(newScheduledSamplerCore(inner, inner, schedule, clock))
+
+	for i := 0; i < 5; i++ {
+		logger.Info("business hours")
+	}
+	if n := logs.FilterMessage("business hours").Len(); n != 1 {
+		t.Errorf("业务时间段内 got %d entries, want 1", n)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	for i := 0; i < 5; i++ {
+		logger.Info("off hours")
+	}
+	if n := logs.FilterMessage("off hours").Len(); n != 5 {
+		t.Errorf("非业务时间段内 got %d entries, want 5", n)
+	}
+}
+
+// TestSamplingWindowContains 测试跨越零点的时间段.
+func TestSamplingWindowContains(t *testing.T) {
+	w := SamplingWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	for offset, want := range map[time.Duration]bool{
+		23 * time.Hour: true,
+		time.Hour:      true,
+		12 * time.Hour: false,
+		6 * time.Hour:  false,
+	} {
+		if got := w.contains(offset); got != want {
+			t.Errorf("contains(%v) = %v, want %v", offset, got, want)
+		}
+	}
+}
+
+// TestFieldSampling 测试按字段值独立采样，没有该字段的日志不采样，下一秒重新计数.
+func TestFieldSampling(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	clock := &fakeClock{now: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)}
+	logger := zap.New(newFieldSamplerCore(inner, "userID", 2, 3, clock, zapcore.AddSync(nil)))
+
+	for i := 0; i < 8; i++ {
+		logger.Info("noisy", zap.String("userID", "alice"))
+	}
+	logger.With(zap.String("userID", "bob")).Info("quiet")
+	for i := 0; i < 8; i++ {
+		logger.Info("anonymous")
+	}
+
+	// 前 2 条完整记录，之后第 5 和第 8 条记录
+	if n := logs.FilterMessage("noisy").Len(); n != 4 {
+		t.Errorf("alice got %d entries, want 4", n)
+	}
+	if n := logs.FilterMessage("quiet").Len(); n != 1 {
+		t.Errorf("bob got %d entries, want 1", n)
+	}
+	if n := logs.FilterMessage("anonymous").Len(); n != 8 {
+		t.Errorf("没有字段的日志 got %d entries, want 8", n)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	logger.Info("next second", zap.String("userID", "alice"))
+	if n := logs.FilterMessage("next second").Len(); n != 1 {
+		t.Errorf("下一秒 got %d entries, want 1", n)
+	}
+}
+
+// TestFieldSamplingBounded 测试字段值的计数状态有上限，过期的字段值被清理.
+func TestFieldSamplingBounded(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)}
+	inner, _ := observer.New(zapcore.DebugLevel)
+	core := newFieldSamplerCore(inner, "userID", 1, 0, clock, zapcore.AddSync(nil)).(*fieldSamplerCore)
+	logger := zap.New(core)
+
+	for i := 0; i < maxFieldSamplingKeys; i++ {
+		logger.Info("user", zap.Int("userID", i))
+	}
+	if n := len(core.state.counts); n != maxFieldSamplingKeys {
+		t.Fatalf("got %d keys, want %d", n, maxFieldSamplingKeys)
+	}
+
+	// 达到上限时先清理过期的字段值
+	clock.now = clock.now.Add(time.Second)
+	logger.Info("user", zap.Int("userID", -1))
+	if n := len(core.state.counts); n != 1 {
+		t.Errorf("got %d keys after expiry, want 1", n)
+	}
+
+	// 没有过期的字段值时清空所有状态
+	for i := 0; i < maxFieldSamplingKeys+10; i++ {
+		logger.Info("user", zap.Int("userID", i))
+	}
+	if n := len(core.state.counts); n > maxFieldSamplingKeys {
+		t.Errorf("got %d keys, want at most %d", n, maxFieldSamplingKeys)
+	}
+}
+
+// TestSampleOverride 测试 NoSample 字段的日志不经过采样，Sample 字段的日志每 n 条记录 1 条.
+func TestSampleOverride(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	dl := newDynamicLevel(zapcore.InfoLevel)
+	sampled := zapcore.NewSamplerWithOptions(inner, time.Minute, 1, 0)
+	logger := zap.New(newSampleOverrideCore(sampled, inner, dl, zapcore.AddSync(nil)))
+
+	for i := 0; i < 5; i++ {
+		logger.Info("sampled")
+		logger.Info("critical", NoSample())
+		logger.With(NoSample()).Info("critical with")
+		logger.Info("noisy", Sample(2))
+	}
+	logger.Debug("debug", NoSample())
+
+	for msg, want := range map[string]int{"sampled": 1, "critical": 5, "critical with": 5, "noisy": 3, "debug": 0} {
+		if n := logs.FilterMessage(msg).Len(); n != want {
+			t.Errorf("%s got %d entries, want %d", msg, n, want)
+		}
+	}
+	for _, e := range logs.All() {
+		for _, f := range e.Context {
+			if f.Type != zapcore.SkipType {
+				t.Errorf("unexpected field %s", f.Key)
+			}
+		}
+	}
+
+	// 按名称设置的级别同样生效
+	dl.setLoggerLevel("db", zapcore.ErrorLevel)
+	logger.Named("db").Warn("named", NoSample())
+	if n := logs.FilterMessage("named").Len(); n != 0 {
+		t.Errorf("named got %d entries, want 0", n)
+	}
+}
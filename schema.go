@@ -0,0 +1,156 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// schemaCore 是按字段类型声明校验字段值的 zapcore.Core 包装器.
+// 字段类型与声明不一致时，会额外输出一条 warn 级别的日志，原日志照常写入.
+type schemaCore struct {
+	zapcore.Core
+	schema map[string]reflect.Kind
+	errOut zapcore.WriteSyncer
+}
+
+// newSchemaCore 创建一个按 schema 校验字段类型的 schemaCore.
+func newSchemaCore(core zapcore.Core, schema map[string]reflect.Kind, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &schemaCore{Core: core, schema: schema, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *schemaCore) With(fields []zapcore.Field) zapcore.Core {
+	c.validate(zapcore.Entry{Time: time.Now()}, fields)
+	return &schemaCore{Core: c.Core.With(fields), schema: c.schema, errOut: c.errOut}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *schemaCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *schemaCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.validate(ent, fields)
+	writeThrough(c.Core, c.errOut, ent, fields)
+	return nil
+}
+
+// validate 校验字段类型，对每个不一致的字段输出一条 warn 级别的日志.
+func (c *schemaCore) validate(ent zapcore.Entry, fields []zapcore.Field) {
+	for _, f := range fields {
+		want, ok := c.schema[f.Key]
+		if !ok {
+			continue
+		}
+		got, ok := fieldKind(f)
+		if !ok || kindCompatible(want, got) {
+			continue
+		}
+		writeThrough(c.Core, c.errOut, zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       ent.Time,
+			LoggerName: ent.LoggerName,
+			Message:    "log field type does not match schema",
+			Caller:     ent.Caller,
+		}, []zapcore.Field{
+			zap.String("field", f.Key),
+			zap.Stringer("want", want),
+			zap.Stringer("got", got),
+			zap.String("entry", ent.Message),
+		})
+	}
+}
+
+// fieldKind 返回字段值对应的 reflect.Kind. 不携带值的字段返回 false.
+func fieldKind(f zapcore.Field) (reflect.Kind, bool) {
+	switch f.Type {
+	case zapcore.BoolType:
+		return reflect.Bool, true
+	case zapcore.Int64Type, zapcore.DurationType:
+		return reflect.Int64, true
+	case zapcore.Int32Type:
+		return reflect.Int32, true
+	case zapcore.Int16Type:
+		return reflect.Int16, true
+	case zapcore.Int8Type:
+		return reflect.Int8, true
+	case zapcore.Uint64Type:
+		return reflect.Uint64, true
+	case zapcore.Uint32Type:
+		return reflect.Uint32, true
+	case zapcore.Uint16Type:
+		return reflect.Uint16, true
+	case zapcore.Uint8Type:
+		return reflect.Uint8, true
+	case zapcore.UintptrType:
+		return reflect.Uintptr, true
+	case zapcore.Float64Type:
+		return reflect.Float64, true
+	case zapcore.Float32Type:
+		return reflect.Float32, true
+	case zapcore.Complex128Type:
+		return reflect.Complex128, true
+	case zapcore.Complex64Type:
+		return reflect.Complex64, true
+	case zapcore.StringType, zapcore.ByteStringType:
+		return reflect.String, true
+	case zapcore.BinaryType:
+		return reflect.Slice, true
+	case zapcore.TimeType, zapcore.TimeFullType:
+		return reflect.Struct, true
+	case zapcore.NamespaceType, zapcore.SkipType, zapcore.UnknownType:
+		return reflect.Invalid, false
+	default:
+		if f.Interface == nil {
+			return reflect.Invalid, false
+		}
+		return reflect.ValueOf(f.Interface).Kind(), true
+	}
+}
+
+// kindCompatible 判断实际类型是否符合声明的类型.
+// 同一类别的数值类型（有符号整数、无符号整数、浮点数、复数）视为兼容，
+// 因为 zap.Int 等构造函数会统一使用最宽的类型存储.
+func kindCompatible(want, got reflect.Kind) bool {
+	if want == got {
+		return true
+	}
+	family := func(k reflect.Kind) reflect.Kind {
+		switch k {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.Int
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.Uint
+		case reflect.Float32, reflect.Float64:
+			return reflect.Float64
+		case reflect.Complex64, reflect.Complex128:
+			return reflect.Complex128
+		default:
+			return k
+		}
+	}
+	return family(want) == family(got)
+}
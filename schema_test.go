@@ -0,0 +1,50 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestFieldSchema 测试字段类型与声明不一致时输出警告.
+func TestFieldSchema(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	schema := map[string]reflect.Kind{"userID": reflect.Int, "name": reflect.String}
+	logger := zap.New(newSchemaCore(inner, schema, nopSyncer{}))
+
+	logger.Info("ok", zap.Int("userID", 1), zap.String("name", "alice"))
+	if n := logs.FilterLevelExact(zapcore.WarnLevel).Len(); n != 0 {
+		t.Fatalf("got %d warnings, want 0", n)
+	}
+
+	logger.Info("bad", zap.String("userID", "1"))
+
+	warnings := logs.FilterLevelExact(zapcore.WarnLevel).All()
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(warnings))
+	}
+	fields := warnings[0].ContextMap()
+	if fields["field"] != "userID" || fields["want"] != "int" || fields["got"] != "string" {
+		t.Errorf("warning fields = %v", fields)
+	}
+	if logs.FilterMessage("bad").Len() != 1 {
+		t.Error("原日志应该照常写入")
+	}
+}
+
+// TestFieldSchemaProduction 测试只有开发模式下才校验字段类型.
+func TestFieldSchemaProduction(t *testing.T) {
+	opts := NewOptions()
+	opts.Apply(WithFieldSchema(map[string]reflect.Kind{"userID": reflect.Int}), WithOutputPaths([]string{}))
+	if _, ok := New(opts).Core().(*schemaCore); ok {
+		t.Error("生产模式下不应该校验字段类型")
+	}
+
+	opts.Apply(WithDevelopment(true))
+	if _, ok := New(opts).Core().(*schemaCore); !ok {
+		t.Error("开发模式下应该校验字段类型")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sentryFlushTimeout 是 Sync 和关闭时等待 Sentry 事件发送完成的最长时间.
+const sentryFlushTimeout = 2 * time.Second
+
+// sentryFieldsContext 是保存日志字段的 Sentry 事件上下文名称.
+const sentryFieldsContext = "fields"
+
+// sentryTagKeys 是作为 Sentry 标签的字段名，用于将事件与日志和链路关联.
+var sentryTagKeys = []string{"traceID", "spanID", "requestID"}
+
+// sentryLevels 是日志级别对应的 Sentry 事件级别.
+var sentryLevels = map[zapcore.Level]sentry.Level{
+	zapcore.DebugLevel:  sentry.LevelDebug,
+	zapcore.InfoLevel:   sentry.LevelInfo,
+	zapcore.WarnLevel:   sentry.LevelWarning,
+	zapcore.ErrorLevel:  sentry.LevelError,
+	zapcore.DPanicLevel: sentry.LevelFatal,
+	zapcore.PanicLevel:  sentry.LevelFatal,
+	zapcore.FatalLevel:  sentry.LevelFatal,
+}
+
+// newSentryOutputCore 创建将日志作为事件发送到 Sentry 的 zapcore.Core，以及发送剩余事件并关闭客户端的函数.
+// 只有不低于 opts.SentryLevel 且满足 enab 的日志才会发送. 无法创建客户端时（例如 DSN 无效），
+// 错误写入 errOut，不发送到 Sentry.
+func newSentryOutputCore(opts *Options, enab zapcore.LevelEnabler, errOut zapcore.WriteSyncer) (zapcore.Core, func() error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: opts.SentryDSN})
+	if err != nil {
+		fmt.Fprintf(errOut, "%v create sentry client: %v\n", time.Now(), err)
+		return zapcore.NewNopCore(), nil
+	}
+	minLevel, err := zapcore.ParseLevel(opts.SentryLevel)
+	if opts.SentryLevel == "" || err != nil {
+		minLevel = zapcore.ErrorLevel
+	}
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= minLevel && enab.Enabled(l)
+	})
+	return newSentryCore(client, enabler), func() error {
+		client.Flush(sentryFlushTimeout)
+		client.Close()
+		return nil
+	}
+}
+
+// sentryCore 是将每条日志作为一个 Sentry 事件发送的 zapcore.Core.
+// 事件由 Sentry 客户端在后台发送，Sentry 不可达时事件会被丢弃，不会阻塞日志调用.
+type sentryCore struct {
+	zapcore.LevelEnabler
+	client *sentry.Client
+	fields []zapcore.Field
+}
+
+// newSentryCore 创建使用 client 发送事件的 sentryCore.
+func newSentryCore(client *sentry.Client, enab zapcore.LevelEnabler) *sentryCore {
+	return &sentryCore{LevelEnabler: enab, client: client}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *sentryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+// 日志字段作为事件名为 fields 的上下文，traceID、spanID 和 requestID 同时作为事件的标签.
+// 日志包含 error 字段时，使用该错误的类型、内容和堆栈作为事件的异常信息，否则使用当前的调用堆栈.
+func (c *sentryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var err error
+	for _, fs := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fs {
+			f.AddTo(enc)
+			if e, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+				err = e
+			}
+		}
+	}
+
+	event := sentry.NewEvent()
+	event.Message = ent.Message
+	event.Level = sentryLevels[ent.Level]
+	event.Logger = ent.LoggerName
+	event.Timestamp = ent.Time
+	event.Contexts[sentryFieldsContext] = enc.Fields
+	for _, key := range sentryTagKeys {
+		if v, ok := enc.Fields[key].(string); ok && v != "" {
+			event.Tags[key] = v
+		}
+	}
+	exception := sentry.Exception{Type: ent.Message}
+	if err != nil {
+		exception.Type = reflect.TypeOf(err).String()
+		exception.Value = err.Error()
+		exception.Stacktrace = sentry.ExtractStacktrace(err)
+	}
+	if exception.Stacktrace == nil {
+		exception.Stacktrace = sentry.NewStacktrace()
+	}
+	event.Exception = []sentry.Exception{exception}
+
+	c.client.CaptureEvent(event, nil, nil)
+	return nil
+}
+
+// Sync 实现 zapcore.Core 接口. 等待已捕获的事件发送完成，最多等待 sentryFlushTimeout.
+func (c *sentryCore) Sync() error {
+	c.client.Flush(sentryFlushTimeout)
+	return nil
+}
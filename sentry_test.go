@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTestSentryClient 创建使用 MockTransport 记录事件的 Sentry 客户端.
+func newTestSentryClient(t *testing.T) (*sentry.Client, *sentry.MockTransport) {
+	t.Helper()
+	transport := &sentry.MockTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@o0.ingest.sentry.io/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, transport
+}
+
+// TestSentryCore 测试日志转换为 Sentry 事件，traceID 和 requestID 作为事件的标签.
+func TestSentryCore(t *testing.T) {
+	client, transport := newTestSentryClient(t)
+	logger := zap.New(newSentryCore(client, zapcore.ErrorLevel)).Named("orders")
+
+	logger.With(zap.String("traceID", "t-1")).Error("charge failed",
+		zap.String("requestID", "r-1"), zap.Int("amount", 42), zap.Error(errors.New("card declined")))
+	logger.Warn("filtered")
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Message != "charge failed" || event.Level != sentry.LevelError || event.Logger != "orders" {
+		t.Errorf("message = %q, level = %q, logger = %q", event.Message, event.Level, event.Logger)
+	}
+	if event.Tags["traceID"] != "t-1" || event.Tags["requestID"] != "r-1" {
+		t.Errorf("tags = %v, want traceID and requestID", event.Tags)
+	}
+	if event.Contexts[sentryFieldsContext]["amount"] != int64(42) {
+		t.Errorf("fields = %v, want amount", event.Contexts[sentryFieldsContext])
+	}
+	if len(event.Exception) != 1 || event.Exception[0].Value != "card declined" || event.Exception[0].Stacktrace == nil {
+		t.Errorf("exception = %+v, want error with stacktrace", event.Exception)
+	}
+}
+
+// TestSentryMinLevel 测试只发送不低于最低级别的日志，最低级别无效时使用 error.
+func TestSentryMinLevel(t *testing.T) {
+	tests := []struct {
+		minLevel string
+		level    zapcore.Level
+		want     bool
+	}{
+		{"", zapcore.WarnLevel, false},
+		{"", zapcore.ErrorLevel, true},
+		{"warn", zapcore.WarnLevel, true},
+		{"invalid", zapcore.WarnLevel, false},
+	}
+	for _, tt := range tests {
+		opts := NewOptions()
+		opts.Apply(WithSentry("https://key@o0.ingest.sentry.io/1", tt.minLevel))
+		core, stop := newSentryOutputCore(opts, zapcore.DebugLevel, zapcore.AddSync(&bytes.Buffer{}))
+		if got := core.Enabled(tt.level); got != tt.want {
+			t.Errorf("minLevel %q: Enabled(%v) = %v, want %v", tt.minLevel, tt.level, got, tt.want)
+		}
+		_ = stop()
+	}
+}
+
+// TestSentryInvalidDSN 测试 DSN 无效时错误写入错误输出，且不发送日志.
+func TestSentryInvalidDSN(t *testing.T) {
+	var errOut bytes.Buffer
+	opts := NewOptions()
+	opts.Apply(WithSentry("not a dsn", ""))
+	core, stop := newSentryOutputCore(opts, zapcore.DebugLevel, zapcore.AddSync(&errOut))
+	if stop != nil || core.Enabled(zapcore.FatalLevel) {
+		t.Error("DSN 无效时不应该发送日志")
+	}
+	if !strings.Contains(errOut.String(), "create sentry client") {
+		t.Errorf("got %q, want sentry client error", errOut.String())
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"io"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// serializedWriter 是加锁写入的 zapcore.WriteSyncer，保证每次 Write（一条日志）作为整体写入.
+// 底层写入只写入了一部分时在锁内继续写入剩余部分，其他 goroutine 的日志不会插入到中间.
+type serializedWriter struct {
+	mu sync.Mutex
+	ws zapcore.WriteSyncer
+}
+
+// Write 实现 io.Writer 接口.
+func (w *serializedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		m, err := w.ws.Write(p[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		if m == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口.
+func (w *serializedWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ws.Sync()
+}
+
+// serializeFileSyncer 在启用 SerializedWrites 时返回加锁整体写入 ws 的 zapcore.WriteSyncer，否则直接返回 ws.
+func serializeFileSyncer(ws zapcore.WriteSyncer, opts *Options) zapcore.WriteSyncer {
+	if !opts.SerializedWrites {
+		return ws
+	}
+	return &serializedWriter{ws: ws}
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// chunkWriter 每次最多写入 chunk 个字节，模拟底层文件的部分写入.
+type chunkWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	chunk int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	runtime.Gosched()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(p) > w.chunk {
+		p = p[:w.chunk]
+	}
+	return w.buf.Write(p)
+}
+
+func (w *chunkWriter) Sync() error { return nil }
+
+// TestSerializedWriter 测试并发写入时每条日志完整写入，不与其他日志交错.
+func TestSerializedWriter(t *testing.T) {
+	w := &chunkWriter{chunk: 7}
+	ws := serializeFileSyncer(w, &Options{SerializedWrites: true})
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			line := []byte(fmt.Sprintf("goroutine-%d-%s\n", g, strings.Repeat(fmt.Sprint(g), 40)))
+			for range 50 {
+				if n, err := ws.Write(line); err != nil || n != len(line) {
+					t.Errorf("Write() = %d, %v, want %d, nil", n, err, len(line))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+	if len(lines) != 400 {
+		t.Fatalf("got %d lines, want 400", len(lines))
+	}
+	for _, line := range lines {
+		var g int
+		if _, err := fmt.Sscanf(line, "goroutine-%d-", &g); err != nil || line != fmt.Sprintf("goroutine-%d-%s", g, strings.Repeat(fmt.Sprint(g), 40)) {
+			t.Fatalf("interleaved line %q", line)
+		}
+	}
+
+	if serializeFileSyncer(w, NewOptions()) != zapcore.WriteSyncer(w) {
+		t.Error("serialized writes should be off by default")
+	}
+}
+
+// BenchmarkSerializedWrites 对比加锁整体写入与直接写入的开销.
+func BenchmarkSerializedWrites(b *testing.B) {
+	line := []byte(`{"level":"info","ts":"2025-01-02T03:04:05.000Z","msg":"benchmark message","k":"v"}` + "\n")
+	for _, serialized := range []bool{false, true} {
+		b.Run(fmt.Sprintf("serialized=%v", serialized), func(b *testing.B) {
+			ws := serializeFileSyncer(zapcore.AddSync(io.Discard), &Options{SerializedWrites: serialized})
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, _ = ws.Write(line)
+				}
+			})
+		})
+	}
+}
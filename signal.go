@@ -0,0 +1,83 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// FlushOnSignal 在进程收到 sigs 中的信号时刷新全局日志记录器，未指定时使用 SIGINT 和 SIGTERM.
+// 刷新后它会停止监听并将信号重新发送给进程本身，没有其他处理程序时进程按信号的默认行为退出;
+// 应用自己通过 signal.Notify 监听的信号不受影响，只是可能会多收到一次重新发送的信号.
+// 需要在日志刷新之后再开始自己的退出流程时，使用 FlushOnSignalNotify.
+// 返回的 stop 函数取消监听，可以多次调用.
+func FlushOnSignal(sigs ...os.Signal) (stop func()) {
+	return flushOnSignal(nil, sigs)
+}
+
+// FlushOnSignalNotify 与 FlushOnSignal 相同，但刷新日志后不会重新发送信号，而是将信号发送到 ch，
+// 应用可以从 ch 接收信号并开始退出流程，此时日志已经刷新. 与 signal.Notify 一样，发送不会阻塞，
+// ch 需要有足够的缓冲. 这个函数会持续监听，直到调用 stop.
+func FlushOnSignalNotify(ch chan<- os.Signal, sigs ...os.Signal) (stop func()) {
+	return flushOnSignal(ch, sigs)
+}
+
+// flushOnSignal 监听 sigs，收到信号时刷新日志，然后发送到 ch，ch 为 nil 时重新发送给进程本身.
+func flushOnSignal(ch chan<- os.Signal, sigs []os.Signal) func() {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+		})
+	}
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-received:
+				_ = Sync()
+				if ch != nil {
+					select {
+					case ch <- sig:
+					default:
+					}
+					continue
+				}
+				// 停止监听后重新发送信号，恢复信号的默认行为
+				stop()
+				if p, err := os.FindProcess(os.Getpid()); err == nil {
+					_ = p.Signal(sig)
+				}
+				return
+			}
+		}
+	}()
+	return stop
+}
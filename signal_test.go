@@ -0,0 +1,95 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// initBuffered 初始化写入缓冲文件的全局日志记录器，返回读取文件内容的函数.
+func initBuffered(t *testing.T) func() string {
+	t.Helper()
+	logFile := filepath.Join(t.TempDir(), "signal.log")
+	initStd(t, WithFilename(logFile), WithOutputPaths(nil), WithBufferedWrites(64*1024, time.Hour))
+	return func() string {
+		content, _ := os.ReadFile(logFile)
+		return string(content)
+	}
+}
+
+// TestFlushOnSignalNotify 测试收到信号时先刷新日志，再将信号发送到调用者的 channel.
+func TestFlushOnSignalNotify(t *testing.T) {
+	read := initBuffered(t)
+	ch := make(chan os.Signal, 1)
+	stop := FlushOnSignalNotify(ch, syscall.SIGUSR1)
+	defer stop()
+
+	Info("buffered before signal")
+	if strings.Contains(read(), "buffered before signal") {
+		t.Fatal("缓冲区未满时日志不应该写入文件")
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case sig := <-ch:
+		if sig != syscall.SIGUSR1 {
+			t.Errorf("signal = %v, want SIGUSR1", sig)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal not forwarded")
+	}
+	if !strings.Contains(read(), "buffered before signal") {
+		t.Error("信号发送到 channel 之前日志应该已经刷新")
+	}
+}
+
+// TestFlushOnSignal 测试收到信号时刷新日志，然后停止监听并重新发送信号.
+func TestFlushOnSignal(t *testing.T) {
+	read := initBuffered(t)
+	// 应用自己的处理程序，同时避免重新发送的信号终止测试进程
+	app := make(chan os.Signal, 2)
+	signal.Notify(app, syscall.SIGUSR2)
+	defer signal.Stop(app)
+
+	stop := FlushOnSignal(syscall.SIGUSR2)
+	defer stop()
+
+	Info("buffered before signal")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-app:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d signals, want the original and the re-sent one", i)
+		}
+	}
+	if !strings.Contains(read(), "buffered before signal") {
+		t.Error("收到信号后日志应该已经刷新")
+	}
+}
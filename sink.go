@@ -0,0 +1,80 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkConfig 描述一个远程输出目标.
+type SinkConfig struct {
+	// Writer 是写入已编码日志的输出目标，例如日志服务的客户端.
+	Writer zapcore.WriteSyncer
+	// Format 是写入该目标的日志格式，为空时沿用 Options.Format.
+	Format string
+}
+
+// LevelSink 将一个级别区间的日志路由到远程输出目标.
+type LevelSink struct {
+	// MinLevel 是写入该目标的最低日志级别.
+	MinLevel string
+	// Sink 是输出目标的配置.
+	Sink SinkConfig
+}
+
+// newLevelSinkCores 为按级别区间路由的输出目标创建 zapcore.Core.
+// 每个目标接收从自身 MinLevel 开始、到下一个更高 MinLevel 之前的日志，最高的目标接收其余所有更高级别的日志.
+func newLevelSinkCores(encoderConfig zapcore.EncoderConfig, sinks []LevelSink, level zapcore.LevelEnabler, opts *Options) []zapcore.Core {
+	type bounded struct {
+		min  zapcore.Level
+		sink SinkConfig
+	}
+	var ranges []bounded
+	for _, ls := range sinks {
+		var minLevel zapcore.Level
+		if err := minLevel.UnmarshalText([]byte(ls.MinLevel)); err != nil || ls.Sink.Writer == nil {
+			continue
+		}
+		ranges = append(ranges, bounded{min: minLevel, sink: ls.Sink})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].min < ranges[j].min })
+
+	cores := make([]zapcore.Core, 0, len(ranges))
+	for i, r := range ranges {
+		minLevel, maxLevel := r.min, zapcore.InvalidLevel
+		// 多个目标的最低级别相同时，它们接收相同的区间
+		for _, next := range ranges[i+1:] {
+			if next.min > minLevel {
+				maxLevel = next.min
+				break
+			}
+		}
+		enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l >= minLevel && (maxLevel == zapcore.InvalidLevel || l < maxLevel) && level.Enabled(l)
+		})
+
+		format := r.sink.Format
+		if format == "" {
+			format = opts.Format
+		}
+		cores = append(cores, zapcore.NewCore(newEncoder(format, encoderConfig, opts), r.sink.Writer, enabler))
+	}
+	return cores
+}
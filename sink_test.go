@@ -0,0 +1,42 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLevelSink 测试低级别的日志写入 sink A，高级别的日志写入 sink B.
+func TestLevelSink(t *testing.T) {
+	var cheap, alerting bytes.Buffer
+	opts := log.NewOptions()
+	opts.Apply(
+		log.WithLevel("debug"),
+		log.WithOutputPaths(nil),
+		log.WithLevelSink("debug", log.SinkConfig{Writer: zapcore.AddSync(&cheap), Format: "json"}),
+		log.WithLevelSink("warn", log.SinkConfig{Writer: zapcore.AddSync(&alerting)}),
+		log.WithLevelSink("info", log.SinkConfig{}), // Writer 为 nil，被忽略
+	)
+	logger := log.New(opts)
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	for _, msg := range []string{"debug message", "info message"} {
+		if !strings.Contains(cheap.String(), msg) || strings.Contains(alerting.String(), msg) {
+			t.Errorf("%q 应该只写入 sink A", msg)
+		}
+	}
+	for _, msg := range []string{"warn message", "error message"} {
+		if !strings.Contains(alerting.String(), msg) || strings.Contains(cheap.String(), msg) {
+			t.Errorf("%q 应该只写入 sink B", msg)
+		}
+	}
+	if !strings.HasPrefix(cheap.String(), "{") {
+		t.Errorf("sink A 应该使用 json 格式: %q", cheap.String())
+	}
+}
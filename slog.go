@@ -0,0 +1,218 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSlogHandler 返回一个将 slog 记录转发到全局 logger 的 slog.Handler.
+// 这样只支持 log/slog 的库也能输出到当前配置的日志目标.
+// 每条记录都使用调用时的全局 logger，因此 Init 重新配置后会立即生效.
+// 如果记录携带 context，会通过 FromContext 附加 traceID 和 requestID.
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+// Slog 返回一个使用 NewSlogHandler 的 *slog.Logger.
+func Slog() *slog.Logger {
+	return slog.New(NewSlogHandler())
+}
+
+// slogHandler 实现了 slog.Handler 接口.
+type slogHandler struct {
+	// fields 是通过 WithAttrs 添加的字段，已经带有分组前缀
+	fields []zap.Field
+	// groups 是通过 WithGroup 打开的分组，之后的属性键会以 "group." 为前缀
+	groups []string
+}
+
+// Enabled 实现 slog.Handler 接口.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return std.Load().Core().Enabled(slogToZapLevel(level))
+}
+
+// Handle 实现 slog.Handler 接口.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:   slogToZapLevel(r.Level),
+		Time:    r.Time,
+		Message: r.Message,
+	}
+	if r.PC != 0 && !stdOpts.Load().DisableCaller {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ent.Caller.Function = frame.Function
+	}
+
+	logger := std.Load()
+	if ctx != nil {
+		logger = FromContext(ctx)
+	}
+	ce := logger.Core().Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	// 与全局 logger 使用相同的错误输出和记录堆栈的级别
+	opts := stdOpts.Load()
+	if opts.errorOutput != nil {
+		ce.ErrorOutput = opts.errorOutput
+	}
+	if opts.stacktrace != nil && opts.stacktrace.Enabled(ent.Level) {
+		ce.Stack = slogStack(r.PC)
+	}
+
+	fields := make([]zap.Field, 0, len(h.fields)+r.NumAttrs())
+	fields = append(fields, h.fields...)
+	prefix := h.prefix()
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, prefix, attr)
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler 接口.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := h.clone()
+	prefix := h.prefix()
+	for _, attr := range attrs {
+		clone.fields = appendAttr(clone.fields, prefix, attr)
+	}
+	return clone
+}
+
+// WithGroup 实现 slog.Handler 接口.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := h.clone()
+	clone.groups = append(clone.groups, name)
+	return clone
+}
+
+// clone 返回 handler 的副本，避免与原 handler 共享切片.
+func (h *slogHandler) clone() *slogHandler {
+	return &slogHandler{
+		fields: append([]zap.Field(nil), h.fields...),
+		groups: append([]string(nil), h.groups...),
+	}
+}
+
+// prefix 返回当前分组对应的键前缀，例如 "req.".
+func (h *slogHandler) prefix() string {
+	if len(h.groups) == 0 {
+		return ""
+	}
+	return strings.Join(h.groups, ".") + "."
+}
+
+// appendAttr 将 slog 属性转换为 zap 字段追加到 fields 中.
+// 分组属性会展开为以 "group." 为前缀的字段，键为空的分组直接内联.
+func appendAttr(fields []zap.Field, prefix string, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	key := prefix + attr.Key
+	v := attr.Value
+	switch v.Kind() {
+	case slog.KindGroup:
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = key + "."
+		}
+		for _, a := range v.Group() {
+			fields = appendAttr(fields, groupPrefix, a)
+		}
+		return fields
+	case slog.KindString:
+		return append(fields, zap.String(key, v.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(key, v.Time()))
+	default:
+		return append(fields, zap.Any(key, v.Any()))
+	}
+}
+
+// slogStack 返回从 pc 所在的调用者开始的堆栈，格式与 zap 记录的堆栈相同.
+// pc 为 0 或不在当前调用栈中时返回完整的调用栈.
+func slogStack(pc uintptr) string {
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i, p := range pcs {
+		if p == pc {
+			pcs = pcs[i:]
+			break
+		}
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			return b.String()
+		}
+	}
+}
+
+// slogToZapLevel 将 slog 级别映射为 zap 级别.
+// 介于两个标准级别之间的自定义级别映射为较低的那个级别.
+func slogToZapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
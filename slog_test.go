@@ -0,0 +1,86 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSlogHandler 测试 slog 记录被转发到全局 logger，分组属性带有前缀.
+func TestSlogHandler(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	l := log.Slog().With("service", "api").WithGroup("req")
+	ctx := log.ContextWithRequestID(context.Background(), "req-1")
+	l.WarnContext(ctx, "slow request",
+		"id", 7,
+		slog.Group("client", "ip", "10.0.0.1"),
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel {
+		t.Errorf("level = %v, want warn", entries[0].Level)
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]interface{}{
+		"service":       "api",
+		"req.id":        int64(7),
+		"req.client.ip": "10.0.0.1",
+		"requestID":     "req-1",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("field %s = %v, want %v", k, fields[k], v)
+		}
+	}
+}
+
+// TestSlogHandlerEnabled 测试 slog 级别判断遵循全局 logger 的级别.
+func TestSlogHandlerEnabled(t *testing.T) {
+	log.Init(log.WithLevel("warn"), log.WithOutputPaths([]string{}))
+	defer log.Init(log.WithLevel("info"))
+
+	h := log.NewSlogHandler()
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Enabled(info) = true, want false")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Enabled(error) = false, want true")
+	}
+}
+
+// TestSlogHandlerStacktrace 测试 slog 记录与全局 logger 使用相同的堆栈级别和错误输出.
+func TestSlogHandlerStacktrace(t *testing.T) {
+	var out, errOut bytes.Buffer
+	log.Init(log.WithOutputPaths(nil), log.WithWriter(&out), log.WithErrorWriter(&errOut), log.WithFormat("json"),
+		log.WithStacktraceKey("stack"), log.WithHook(func(zapcore.Entry) error { return errors.New("hook failed") }))
+	defer log.Init()
+
+	l := log.Slog()
+	l.Info("no stack")
+	l.Error("with stack")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), out.String())
+	}
+	if strings.Contains(lines[0], `"stack"`) {
+		t.Errorf("info entry should not have a stacktrace: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"stack":"github.com/go-anyway/framework-log_test.TestSlogHandlerStacktrace`) {
+		t.Errorf("error entry should have a stacktrace starting at the caller: %s", lines[1])
+	}
+	if !strings.Contains(errOut.String(), "hook failed") {
+		t.Errorf("error output = %q, want the hook error", errOut.String())
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// spanEventCore 是将日志记录为 OpenTelemetry span 事件的 zapcore.Core.
+// 与 contextCore 一样，它从 FromContext 附加的字段中取出请求的 context，
+// context 中没有正在记录的 span 时不做任何事情.
+type spanEventCore struct {
+	zapcore.LevelEnabler
+	ctx    context.Context
+	fields []zapcore.Field
+}
+
+// newSpanEventCore 创建一个 spanEventCore.
+func newSpanEventCore(enab zapcore.LevelEnabler) zapcore.Core {
+	return &spanEventCore{LevelEnabler: enab, ctx: context.Background()}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *spanEventCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &spanEventCore{
+		LevelEnabler: c.LevelEnabler,
+		ctx:          c.ctx,
+		fields:       append([]zapcore.Field(nil), c.fields...),
+	}
+	for _, f := range fields {
+		if ctx, ok := contextFromField(f); ok {
+			clone.ctx = ctx
+			continue
+		}
+		clone.fields = append(clone.fields, f)
+	}
+	return clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *spanEventCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) && trace.SpanFromContext(c.ctx).IsRecording() {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+// error 及以上级别的日志会将 span 标记为错误.
+func (c *spanEventCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	span := trace.SpanFromContext(c.ctx)
+	attrs := []attribute.KeyValue{attribute.String("level", ent.Level.String())}
+	attrs = appendAttributes(attrs, c.fields)
+	attrs = appendAttributes(attrs, fields)
+	span.AddEvent(ent.Message, trace.WithAttributes(attrs...), trace.WithTimestamp(ent.Time))
+	if ent.Level >= zapcore.ErrorLevel {
+		span.SetStatus(codes.Error, ent.Message)
+	}
+	return nil
+}
+
+// Sync 实现 zapcore.Core 接口.
+func (c *spanEventCore) Sync() error {
+	return nil
+}
+
+// appendAttributes 将 zap 字段转换为 OpenTelemetry 属性.
+// traceID 和 spanID 已经由 span 本身表示，不会重复记录.
+func appendAttributes(attrs []attribute.KeyValue, fields []zapcore.Field) []attribute.KeyValue {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType || f.Key == "traceID" || f.Key == "spanID" {
+			continue
+		}
+		f.AddTo(enc)
+	}
+	for k, v := range enc.Fields {
+		switch v := v.(type) {
+		case string:
+			attrs = append(attrs, attribute.String(k, v))
+		case bool:
+			attrs = append(attrs, attribute.Bool(k, v))
+		case int64:
+			attrs = append(attrs, attribute.Int64(k, v))
+		case int:
+			attrs = append(attrs, attribute.Int(k, v))
+		case float64:
+			attrs = append(attrs, attribute.Float64(k, v))
+		default:
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
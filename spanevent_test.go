@@ -0,0 +1,66 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// recordingSpan 是记录事件和状态的测试 span.
+type recordingSpan struct {
+	noop.Span
+	events []string
+	attrs  []attribute.KeyValue
+	status codes.Code
+}
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.events = append(s.events, name)
+	cfg := trace.NewEventConfig(opts...)
+	s.attrs = append(s.attrs, cfg.Attributes()...)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+// TestSpanEvents 测试日志被记录为 span 事件，error 级别的日志将 span 标记为错误.
+func TestSpanEvents(t *testing.T) {
+	initStd(t, WithSpanEvents(true), WithOutputPaths(nil))
+
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	FromContext(ctx).Info("cache miss", zap.String("key", "user:1"), zap.Int("attempt", 2))
+	if span.status != codes.Unset {
+		t.Errorf("info 日志不应该修改 span 状态")
+	}
+	FromContext(ctx).Error("query failed")
+
+	if len(span.events) != 2 || span.events[0] != "cache miss" || span.events[1] != "query failed" {
+		t.Fatalf("events = %v", span.events)
+	}
+	got := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.attrs {
+		got[kv.Key] = kv.Value
+	}
+	if got["key"].AsString() != "user:1" || got["attempt"].AsInt64() != 2 {
+		t.Errorf("attrs = %v", span.attrs)
+	}
+	if _, ok := got["spanID"]; ok {
+		t.Errorf("spanID 不应该作为属性记录: %v", span.attrs)
+	}
+	if span.status != codes.Error {
+		t.Errorf("status = %v, want Error", span.status)
+	}
+
+	// 没有正在记录的 span 时不做任何事情
+	FromContext(context.Background()).Info("no span")
+	if len(span.events) != 2 {
+		t.Errorf("events = %v", span.events)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// stackdriverTraceKey 是 Cloud Logging 关联链路的字段名.
+	stackdriverTraceKey = "logging.googleapis.com/trace"
+	// stackdriverSpanIDKey 是 Cloud Logging 关联 span 的字段名.
+	stackdriverSpanIDKey = "logging.googleapis.com/spanId"
+)
+
+// stackdriverSeverities 是日志级别对应的 Cloud Logging 严重性.
+var stackdriverSeverities = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "DEBUG",
+	zapcore.InfoLevel:   "INFO",
+	zapcore.WarnLevel:   "WARNING",
+	zapcore.ErrorLevel:  "ERROR",
+	zapcore.DPanicLevel: "CRITICAL",
+	zapcore.PanicLevel:  "ALERT",
+	zapcore.FatalLevel:  "EMERGENCY",
+}
+
+// stackdriverLevelEncoder 将日志级别编码为 Cloud Logging 的严重性.
+func stackdriverLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if severity, ok := stackdriverSeverities[l]; ok {
+		enc.AppendString(severity)
+		return
+	}
+	enc.AppendString("DEFAULT")
+}
+
+// stackdriverMapper 返回将 traceID 和 spanID 字段改写为 Cloud Logging 关联字段的 fieldMapper.
+// traceID 改写为 projects/<projectID>/traces/<traceID> 的完整形式.
+func stackdriverMapper(projectID string) fieldMapper {
+	return func(f zapcore.Field) (zapcore.Field, bool) {
+		if f.Type != zapcore.StringType || f.String == "" {
+			return f, true
+		}
+		switch f.Key {
+		case "traceID":
+			return zap.String(stackdriverTraceKey, "projects/"+projectID+"/traces/"+f.String), true
+		case "spanID":
+			return zap.String(stackdriverSpanIDKey, f.String), true
+		}
+		return f, true
+	}
+}
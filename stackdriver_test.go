@@ -0,0 +1,77 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestStackdriver 测试 Stackdriver 预设输出 Cloud Logging 的字段，并将 FromContext 的 traceID 改写为完整形式.
+func TestStackdriver(t *testing.T) {
+	var buf bytes.Buffer
+	initStd(t, WithStackdriver("my-project"), WithOutputPaths(nil),
+		WithLevelSink("debug", SinkConfig{Writer: zapcore.AddSync(&buf)}))
+
+	traceID, spanID := trace.TraceID{1, 2, 3}, trace.SpanID{4, 5}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	FromContext(ctx).Warn("slow query")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("output is not json: %q", buf.String())
+	}
+	want := map[string]interface{}{
+		"severity":                      "WARNING",
+		"message":                       "slow query",
+		"logging.googleapis.com/trace":  "projects/my-project/traces/" + traceID.String(),
+		"logging.googleapis.com/spanId": spanID.String(),
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s = %v, want %v", k, line[k], v)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339, line["time"].(string)); err != nil {
+		t.Errorf("time = %v, want RFC3339: %v", line["time"], err)
+	}
+	if _, ok := line["traceID"]; ok {
+		t.Error("traceID 字段应该被改写")
+	}
+}
+
+// TestStackdriverLevelEncoder 测试日志级别编码为 Cloud Logging 的严重性.
+func TestStackdriverLevelEncoder(t *testing.T) {
+	opts := NewOptions()
+	opts.Apply(WithLevelEncoder("stackdriver"))
+	for level, severity := range stackdriverSeverities {
+		got := encodeEntry(t, opts, "json", zapcore.Entry{Level: level})
+		if want := `"level":"` + severity + `"`; !strings.Contains(got, want) {
+			t.Errorf("%v: got %q, want %s", level, got, want)
+		}
+	}
+}
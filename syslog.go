@@ -0,0 +1,309 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogWriter 是按严重性写入 syslog 的接口，*syslog.Writer 实现了该接口.
+type syslogWriter interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+	Crit(m string) error
+	Alert(m string) error
+	Emerg(m string) error
+	Close() error
+}
+
+// newSyslogOutputCore 创建写入 syslog 的 zapcore.Core，以及关闭连接的函数.
+// 连接在后台建立，不会阻塞 Init；无法连接 syslog 时（例如本机没有 syslog 服务或平台不支持），
+// 日志回退到 errOut，同时在后台按指数退避重新连接.
+func newSyslogOutputCore(enc zapcore.Encoder, opts *Options, enab zapcore.LevelEnabler, errOut zapcore.WriteSyncer) (zapcore.Core, func() error) {
+	conn := newSyslogConn(opts.SyslogNetwork, opts.SyslogAddr, opts.SyslogTag, errOut)
+	return &syslogCore{LevelEnabler: enab, enc: enc, out: conn}, conn.Close
+}
+
+// syslogEntry 是首次连接完成之前暂存的一条日志.
+type syslogEntry struct {
+	level zapcore.Level
+	msg   string
+}
+
+// syslogConn 管理到 syslog 服务的连接，行为与 networkWriter 相同：
+// 首次连接在后台进行，期间的日志暂存在内存中，连接建立后按顺序写入；
+// 连接不可用时日志写入错误输出，同时在后台按指数退避重新连接.
+type syslogConn struct {
+	network string
+	addr    string
+	tag     string
+	errOut  zapcore.WriteSyncer
+
+	mu     sync.Mutex
+	w      syslogWriter
+	closed bool
+	// pending 是首次连接完成之前写入的日志，ready 关闭后为 nil
+	pending []syslogEntry
+	ready   chan struct{}
+
+	reconnect chan struct{}
+	stopCh    chan struct{}
+	once      sync.Once
+}
+
+// newSyslogConn 创建一个 syslogConn，在后台建立连接并重连.
+func newSyslogConn(network, addr, tag string, errOut zapcore.WriteSyncer) *syslogConn {
+	c := &syslogConn{
+		network:   network,
+		addr:      addr,
+		tag:       tag,
+		errOut:    errOut,
+		ready:     make(chan struct{}),
+		reconnect: make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+	go c.loop()
+	return c
+}
+
+// write 按日志级别对应的严重性写入 syslog.
+// 首次连接完成之前暂存日志，超过上限时写入错误输出.
+// 写入失败时关闭连接，将这条日志写入错误输出并触发重连.
+func (c *syslogConn) write(level zapcore.Level, msg string) error {
+	c.mu.Lock()
+	if c.isConnecting() {
+		if len(c.pending) < networkMaxPending {
+			c.pending = append(c.pending, syslogEntry{level: level, msg: msg})
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+		return c.fallback(msg)
+	}
+	if c.w != nil {
+		err := writeSyslog(c.w, level, msg)
+		if err == nil {
+			c.mu.Unlock()
+			return nil
+		}
+		_ = c.w.Close()
+		c.w = nil
+		c.mu.Unlock()
+		c.reportError(fmt.Errorf("write: %w, falling back to error output", err))
+		c.scheduleReconnect()
+	} else {
+		c.mu.Unlock()
+	}
+	return c.fallback(msg)
+}
+
+// fallback 将一条日志写入错误输出.
+func (c *syslogConn) fallback(msg string) error {
+	_, err := c.errOut.Write([]byte(msg + "\n"))
+	return err
+}
+
+// Sync 等待首次连接完成并写入暂存的日志.
+func (c *syslogConn) Sync() error {
+	<-c.ready
+	return c.errOut.Sync()
+}
+
+// isConnecting 判断首次连接是否仍在进行. 必须在持有 mu 时调用.
+func (c *syslogConn) isConnecting() bool {
+	select {
+	case <-c.ready:
+		return false
+	default:
+		return true
+	}
+}
+
+// connectFirst 进行首次连接，然后将暂存的日志按顺序写入 syslog，连接失败时写入错误输出.
+func (c *syslogConn) connectFirst() {
+	err := c.connect()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(c.ready)
+	pending := c.pending
+	c.pending = nil
+	if err != nil {
+		c.reportError(fmt.Errorf("%w, falling back to error output", err))
+		c.scheduleReconnect()
+	}
+	for _, e := range pending {
+		if c.w == nil {
+			_ = c.fallback(e.msg)
+			continue
+		}
+		if err := writeSyslog(c.w, e.level, e.msg); err != nil {
+			_ = c.w.Close()
+			c.w = nil
+			c.reportError(fmt.Errorf("write: %w, falling back to error output", err))
+			c.scheduleReconnect()
+			_ = c.fallback(e.msg)
+		}
+	}
+}
+
+// Close 停止后台重连并关闭连接. 不等待正在进行的连接，连接完成后会被立即关闭.
+func (c *syslogConn) Close() error {
+	c.once.Do(func() { close(c.stopCh) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.w == nil {
+		return nil
+	}
+	err := c.w.Close()
+	c.w = nil
+	return err
+}
+
+// connect 连接 syslog 服务.
+func (c *syslogConn) connect() error {
+	w, err := dialSyslog(c.network, c.addr, c.tag)
+	if err != nil {
+		return fmt.Errorf("dial syslog: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		_ = w.Close()
+		return errors.New("writer closed")
+	}
+	if c.w != nil {
+		// 已经有可用的连接，例如多次写入失败触发了重复的重连通知
+		_ = w.Close()
+		return nil
+	}
+	c.w = w
+	return nil
+}
+
+// scheduleReconnect 通知后台重新连接.
+func (c *syslogConn) scheduleReconnect() {
+	select {
+	case c.reconnect <- struct{}{}:
+	default:
+	}
+}
+
+// loop 进行首次连接，之后在连接断开时按指数退避重新连接，直到连接成功或 Close 被调用.
+func (c *syslogConn) loop() {
+	c.connectFirst()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-c.reconnect:
+		}
+
+		backoff := networkMinBackoff
+		for {
+			select {
+			case <-c.stopCh:
+				return
+			case <-time.After(backoff):
+			}
+			if err := c.connect(); err == nil {
+				c.reportError(errors.New("reconnected"))
+				break
+			}
+			backoff = min(backoff*2, networkMaxBackoff)
+		}
+	}
+}
+
+// reportError 将连接状态写入错误输出.
+func (c *syslogConn) reportError(err error) {
+	fmt.Fprintf(c.errOut, "%v syslog output %s://%s: %v\n", time.Now(), c.network, c.addr, err)
+	_ = c.errOut.Sync()
+}
+
+// writeSyslog 按日志级别对应的严重性写入 syslog.
+// 日志级别映射为 syslog 严重性：debug 为 LOG_DEBUG，info 为 LOG_INFO，warn 为 LOG_WARNING，
+// error 为 LOG_ERR，dpanic 为 LOG_CRIT，panic 为 LOG_ALERT，fatal 为 LOG_EMERG.
+func writeSyslog(w syslogWriter, level zapcore.Level, msg string) error {
+	switch level {
+	case zapcore.DebugLevel:
+		return w.Debug(msg)
+	case zapcore.WarnLevel:
+		return w.Warning(msg)
+	case zapcore.ErrorLevel:
+		return w.Err(msg)
+	case zapcore.DPanicLevel:
+		return w.Crit(msg)
+	case zapcore.PanicLevel:
+		return w.Alert(msg)
+	case zapcore.FatalLevel:
+		return w.Emerg(msg)
+	default:
+		return w.Info(msg)
+	}
+}
+
+// syslogCore 是将每条日志按对应严重性写入 syslog 的 zapcore.Core.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out *syslogConn
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &syslogCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), out: c.out}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+	return c.out.write(ent.Level, msg)
+}
+
+// Sync 实现 zapcore.Core 接口. 等待首次连接完成，之后每条日志都会立即写入 syslog.
+func (c *syslogCore) Sync() error {
+	return c.out.Sync()
+}
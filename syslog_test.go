@@ -0,0 +1,175 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSyslogOutput 测试日志按级别映射的严重性写入远程 syslog 服务.
+func TestSyslogOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	opts := NewOptions()
+	opts.Apply(WithSyslogOutput("udp", conn.LocalAddr().String(), "test"))
+	core, stop := newSyslogOutputCore(zapcore.NewJSONEncoder(newEncoderConfig(opts)), opts, zapcore.DebugLevel, zapcore.AddSync(&bytes.Buffer{}))
+	if stop == nil {
+		t.Fatal("连接成功时应该返回关闭函数")
+	}
+	defer stop()
+	logger := zap.New(core)
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	// LOG_USER 的 facility 为 1，优先级为 facility*8 + severity
+	want := []struct{ prefix, msg string }{
+		{"<15>", "debug message"},
+		{"<14>", "info message"},
+		{"<12>", "warn message"},
+		{"<11>", "error message"},
+	}
+	buf := make([]byte, 4096)
+	for _, w := range want {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line := string(buf[:n])
+		if !strings.HasPrefix(line, w.prefix) || !strings.Contains(line, w.msg) || !strings.Contains(line, "test[") {
+			t.Errorf("got %q, want prefix %s and message %q", line, w.prefix, w.msg)
+		}
+	}
+}
+
+// TestSyslogFallback 测试无法连接 syslog 时日志回退到错误输出，服务恢复后自动重新连接.
+func TestSyslogFallback(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var errOut lockedBuffer
+	opts := NewOptions()
+	opts.Apply(WithSyslogOutput("tcp", addr, "test"))
+	core, stop := newSyslogOutputCore(zapcore.NewJSONEncoder(newEncoderConfig(opts)), opts, zapcore.InfoLevel, zapcore.AddSync(&errOut))
+	defer stop()
+	logger := zap.New(core)
+	logger.Info("fallback message")
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(errOut.String(), "dial syslog") || !strings.Contains(errOut.String(), "fallback message") {
+		t.Errorf("got %q, want dial error and fallback message", errOut.String())
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("listen again on %s: %v", addr, err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(errOut.String(), "reconnected") {
+		if time.Now().After(deadline) {
+			t.Fatalf("not reconnected, error output = %q", errOut.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	logger.Info("after reconnect")
+
+	select {
+	case line := <-received:
+		if !strings.Contains(line, "after reconnect") {
+			t.Errorf("got %q, want the line written after reconnecting", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog server received nothing after reconnecting")
+	}
+}
+
+// TestSyslogConnectInBackground 测试连接在后台建立，之前写入的日志在连接后按顺序写入.
+func TestSyslogConnectInBackground(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := &syslogConn{
+		network:   "udp",
+		addr:      conn.LocalAddr().String(),
+		tag:       "test",
+		errOut:    zapcore.AddSync(&bytes.Buffer{}),
+		ready:     make(chan struct{}),
+		reconnect: make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+	defer c.Close()
+	if err := c.write(zapcore.InfoLevel, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.write(zapcore.WarnLevel, "second"); err != nil {
+		t.Fatal(err)
+	}
+	go c.loop()
+	if err := c.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4096)
+	for _, want := range []string{"<14>", "<12>"} {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(buf[:n]), want) {
+			t.Errorf("got %q, want prefix %s", buf[:n], want)
+		}
+	}
+}
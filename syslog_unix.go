@@ -0,0 +1,28 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build !windows && !plan9
+
+package log
+
+import "log/syslog"
+
+// dialSyslog 连接 syslog 服务. network 和 addr 都为空时连接本机的 syslog 服务，
+// 否则连接远程服务，例如 "udp", "logs.example.com:514".
+// 写入失败时 syslog.Writer 会自动重新连接并重试一次.
+func dialSyslog(network, addr, tag string) (syslogWriter, error) {
+	return syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
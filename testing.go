@@ -0,0 +1,132 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"bytes"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// ObservedLogs 是内存中记录的日志集合，可用于在测试中断言日志内容.
+type ObservedLogs = observer.ObservedLogs
+
+// NewObserver 创建一个将所有级别的日志记录在内存中的 logger.
+// 返回的 ObservedLogs 可用于断言记录的消息和字段，而无需捕获 stdout.
+func NewObserver() (*zap.Logger, *ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core), logs
+}
+
+// SwapForTest 临时将全局日志记录器替换为 logger，返回用于恢复之前 logger 的函数.
+// 全局 logger 是共享状态，使用该函数的测试不应并行运行.
+//
+//	logger, logs := log.NewObserver()
+//	restore := log.SwapForTest(logger)
+//	defer restore()
+func SwapForTest(logger *zap.Logger) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := std.Swap(logger)
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		std.Store(prev)
+	}
+}
+
+// CaptureOutput 在执行 fn 期间将全局日志记录器的 stdout 和 stderr 输出写入内存，返回捕获的文本.
+// 全局日志记录器在 fn 期间被替换为使用相同配置、只替换了控制台输出的日志记录器，fn 返回后恢复，
+// 因此可以直接断言控制台格式，而无需读写文件或真正的 stdout. 使用该函数的测试不应并行运行.
+// 捕获期间只保留 stdout 和 stderr 输出，文件、Writers 和 Kafka 等远程输出不会收到日志.
+//
+//	stdout, _ := log.CaptureOutput(func() {
+//		log.Info("hello")
+//	})
+func CaptureOutput(fn func()) (stdout, stderr string) {
+	var outBuf, errBuf bytes.Buffer
+	o := stdOpts.Load().Clone()
+	consoleOnly(o)
+	o.stdout, o.stderr = &outBuf, &errBuf
+	logger, level, stop := newLogger(o)
+
+	mu.Lock()
+	prev, prevOpts, prevLevel := std.Swap(logger), stdOpts.Swap(o), stdLevel.Swap(level)
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		std.Store(prev)
+		stdOpts.Store(prevOpts)
+		stdLevel.Store(prevLevel)
+	}()
+	// 先于恢复执行，保证读取捕获的文本之前所有日志已经写入
+	defer func() {
+		_ = logger.Sync()
+		_ = stop()
+		stdout, stderr = outBuf.String(), errBuf.String()
+	}()
+
+	fn()
+	return
+}
+
+// consoleOnly 去掉 opts 中 stdout 和 stderr 以外的所有输出，避免捕获期间写入真实的文件和远程服务.
+func consoleOnly(opts *Options) {
+	opts.OutputPaths = consolePaths(opts.OutputPaths)
+	opts.ErrorOutputPaths = consolePaths(opts.ErrorOutputPaths)
+	opts.Filename = ""
+
+	var levelOutputs []LevelOutput
+	for _, out := range opts.LevelOutputs {
+		if out.Paths = consolePaths(out.Paths); len(out.Paths) > 0 {
+			levelOutputs = append(levelOutputs, out)
+		}
+	}
+	opts.LevelOutputs = levelOutputs
+	var sinks []Sink
+	for _, sink := range opts.Sinks {
+		if sink.Paths = consolePaths(sink.Paths); len(sink.Paths) > 0 {
+			sinks = append(sinks, sink)
+		}
+	}
+	opts.Sinks = sinks
+
+	opts.Writers, opts.ErrorWriters = nil, nil
+	opts.ContextSinks, opts.LevelSinks = nil, nil
+	opts.KafkaBrokers, opts.KafkaTopic = nil, ""
+	opts.HTTPOutputURL, opts.HTTPOutputOptions = "", nil
+	opts.NetworkOutput, opts.NetworkAddr = "", ""
+	opts.Syslog, opts.Journald = false, false
+	opts.CloudWatchGroup, opts.CloudWatchStream = "", ""
+	opts.SentryDSN = ""
+	opts.EventLogSource = ""
+}
+
+// consolePaths 返回 paths 中的 stdout 和 stderr，与 getConsoleWriteSyncer 一样不区分大小写.
+func consolePaths(paths []string) []string {
+	var console []string
+	for _, path := range paths {
+		if lower := strings.ToLower(path); lower == "stdout" || lower == "stderr" {
+			console = append(console, path)
+		}
+	}
+	return console
+}
@@ -0,0 +1,132 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSwapForTest 测试使用观察者 logger 断言日志内容.
+func TestSwapForTest(t *testing.T) {
+	prev := log.GetLogger()
+	logger, logs := log.NewObserver()
+	restore := log.SwapForTest(logger)
+
+	log.FromContext(log.ContextWithRequestID(context.Background(), "req-1")).
+		Info("user created", zap.String("user", "alice"))
+
+	entries := logs.FilterMessage("user created").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["user"] != "alice" || fields["requestID"] != "req-1" {
+		t.Errorf("fields = %v, want user=alice and requestID=req-1", fields)
+	}
+
+	restore()
+	if log.GetLogger() != prev {
+		t.Error("restore() 没有恢复之前的全局 logger")
+	}
+}
+
+// TestNop 测试 Nop 返回的 logger 不启用任何级别，可以替换全局日志记录器.
+func TestNop(t *testing.T) {
+	defer log.SwapForTest(log.Nop())()
+
+	if log.Enabled("error") {
+		t.Error("Nop logger 不应该启用任何级别")
+	}
+	log.FromContext(log.ContextWithRequestID(context.Background(), "req-1")).Error("silent")
+}
+
+// TestNamed 测试命名 logger 以点号连接名称.
+func TestNamed(t *testing.T) {
+	logger, logs := log.NewObserver()
+	defer log.SwapForTest(logger)()
+
+	log.Named("http").Named("router").Info("route matched")
+	log.FromContext(log.ContextWithRequestID(context.Background(), "req-1")).Named("db").Info("query")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if name := entries[0].LoggerName; name != "http.router" {
+		t.Errorf("LoggerName = %q, want http.router", name)
+	}
+	if name := entries[1].LoggerName; name != "db" {
+		t.Errorf("LoggerName = %q, want db", name)
+	}
+}
+
+// TestCaptureOutput 测试捕获全局日志记录器的控制台输出和内部错误输出，结束后恢复之前的 logger.
+func TestCaptureOutput(t *testing.T) {
+	log.Init(log.WithOutputPaths([]string{"stdout"}), log.WithFormat("json"), log.WithProcessFields(false),
+		log.WithHook(func(zapcore.Entry) error { return errors.New("hook failed") }))
+	defer log.Init()
+	prev := log.GetLogger()
+
+	stdout, stderr := log.CaptureOutput(func() {
+		log.Info("captured", zap.String("user", "alice"))
+	})
+
+	if !strings.Contains(stdout, `"msg":"captured","user":"alice"}`) {
+		t.Errorf("stdout = %q, want the json entry", stdout)
+	}
+	if !strings.Contains(stderr, "hook failed") {
+		t.Errorf("stderr = %q, want the hook error", stderr)
+	}
+	if log.GetLogger() != prev {
+		t.Error("CaptureOutput 没有恢复之前的全局 logger")
+	}
+}
+
+// TestCaptureOutputConsoleOnly 测试捕获期间文件和 Writers 等输出不会收到日志.
+func TestCaptureOutputConsoleOnly(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	var w bytes.Buffer
+	log.Init(log.WithOutputPaths([]string{"stdout", filepath.Join(dir, "out.log")}), log.WithFilename(filename),
+		log.WithWriter(&w), log.WithSink([]string{"stderr", filepath.Join(dir, "sink.log")}, "json", "info"))
+	defer log.Init()
+
+	stdout, stderr := log.CaptureOutput(func() {
+		log.Info("captured")
+	})
+
+	if !strings.Contains(stdout, "captured") || !strings.Contains(stderr, "captured") {
+		t.Errorf("stdout = %q, stderr = %q, want the entry in both", stdout, stderr)
+	}
+	if strings.Contains(w.String(), "captured") {
+		t.Errorf("writer = %q, want no captured entry", w.String())
+	}
+	for _, name := range []string{"app.log", "out.log", "sink.log"} {
+		content, _ := os.ReadFile(filepath.Join(dir, name))
+		if strings.Contains(string(content), "captured") {
+			t.Errorf("%s = %q, want no captured entry", name, content)
+		}
+	}
+}
+
+// TestCaptureOutputConsoleCaseInsensitive 测试大小写不同的 stdout 和 stderr 路径同样被捕获.
+func TestCaptureOutputConsoleCaseInsensitive(t *testing.T) {
+	log.Init(log.WithOutputPaths([]string{"STDOUT"}), log.WithSink([]string{"Stderr"}, "json", "info"))
+	defer log.Init()
+
+	stdout, stderr := log.CaptureOutput(func() {
+		log.Info("captured")
+	})
+
+	if !strings.Contains(stdout, "captured") || !strings.Contains(stderr, "captured") {
+		t.Errorf("stdout = %q, stderr = %q, want the entry in both", stdout, stderr)
+	}
+}
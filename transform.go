@@ -0,0 +1,117 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue 是敏感字段被替换后的值.
+const redactedValue = "***"
+
+// FieldTransform 在编码前改写一个字段，返回 false 时丢弃该字段.
+type FieldTransform func(zapcore.Field) (zapcore.Field, bool)
+
+// fieldMapper 在编码前改写字段，返回 false 时丢弃该字段.
+type fieldMapper func(zapcore.Field) (zapcore.Field, bool)
+
+// transformCore 是在编码前改写字段的 zapcore.Core 包装器.
+// 通过 With 添加的字段和每次调用传入的字段都会被改写，与输出格式无关.
+type transformCore struct {
+	zapcore.Core
+	mapper fieldMapper
+	errOut zapcore.WriteSyncer
+}
+
+// newTransformCore 创建一个使用 mapper 改写字段的 transformCore.
+func newTransformCore(core zapcore.Core, mapper fieldMapper, errOut zapcore.WriteSyncer) zapcore.Core {
+	return &transformCore{Core: core, mapper: mapper, errOut: errOut}
+}
+
+// With 实现 zapcore.Core 接口.
+func (c *transformCore) With(fields []zapcore.Field) zapcore.Core {
+	return &transformCore{
+		Core:   c.Core.With(c.apply(fields)),
+		mapper: c.mapper,
+		errOut: c.errOut,
+	}
+}
+
+// Check 实现 zapcore.Core 接口.
+func (c *transformCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口.
+func (c *transformCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	writeThrough(c.Core, c.errOut, ent, c.apply(fields))
+	return nil
+}
+
+// apply 返回改写后的字段，不修改传入的切片.
+func (c *transformCore) apply(fields []zapcore.Field) []zapcore.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	out := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if mapped, ok := c.mapper(f); ok {
+			out = append(out, mapped)
+		}
+	}
+	return out
+}
+
+// redactMapper 返回将 keys 对应字段的值替换为 *** 的 fieldMapper，键名不区分大小写.
+func redactMapper(keys []string) fieldMapper {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return func(f zapcore.Field) (zapcore.Field, bool) {
+		if f.Type == zapcore.NamespaceType || f.Type == zapcore.SkipType {
+			return f, true
+		}
+		if _, ok := set[strings.ToLower(f.Key)]; ok {
+			return zap.String(f.Key, redactedValue), true
+		}
+		return f, true
+	}
+}
+
+// transformsMapper 返回依次应用 transforms 的 fieldMapper，任意一个返回 false 时丢弃该字段.
+// SkipType 字段是内部使用的标记，不交给 transforms 处理.
+func transformsMapper(transforms []FieldTransform) fieldMapper {
+	return func(f zapcore.Field) (zapcore.Field, bool) {
+		if f.Type == zapcore.SkipType {
+			return f, true
+		}
+		for _, t := range transforms {
+			var ok bool
+			if f, ok = t(f); !ok {
+				return f, false
+			}
+		}
+		return f, true
+	}
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type credentials struct {
+	User string
+}
+
+// TestRedactKeys 测试敏感字段不区分大小写地被脱敏，与字段类型无关.
+func TestRedactKeys(t *testing.T) {
+	inner, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newTransformCore(inner, redactMapper([]string{"password", "token", "Authorization"}), nopSyncer{}))
+
+	logger.With(zap.String("authorization", "Bearer abc")).Info("login",
+		zap.String("Password", "secret"),
+		zap.Int("token", 12345),
+		zap.Any("TOKEN", credentials{User: "alice"}),
+		zap.String("user", "alice"),
+	)
+
+	fields := logs.All()[0].Context
+	want := map[string]interface{}{
+		"authorization": redactedValue,
+		"Password":      redactedValue,
+		"token":         redactedValue,
+		"TOKEN":         redactedValue,
+		"user":          "alice",
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(fields), len(want))
+	}
+	for _, f := range fields {
+		if f.String != want[f.Key] {
+			t.Errorf("field %s = %q, want %q", f.Key, f.String, want[f.Key])
+		}
+	}
+}
+
+// TestFieldTransform 测试字段转换函数作用于调用时的字段、With 添加的字段和自动添加的 traceID，并且在脱敏之后执行.
+func TestFieldTransform(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutputPaths(nil), WithWriter(&buf), WithFormat("json"), WithRedactKeys("password"),
+		WithFieldTransform(func(f zapcore.Field) (zapcore.Field, bool) {
+			return f, !strings.HasPrefix(f.Key, "debug_")
+		}),
+		WithFieldTransform(func(f zapcore.Field) (zapcore.Field, bool) {
+			switch f.Key {
+			case "traceID":
+				f.Key = "trace_id"
+			case "password":
+				f.Key = "pwd"
+			}
+			return f, true
+		}),
+	)
+	defer l.Close()
+
+	ctx := ContextWithTraceID(context.Background(), "t-1")
+	l.FromContext(ctx).With(zap.String("debug_with", "x")).Info("hello",
+		zap.String("debug_call", "y"), zap.String("password", "secret"), zap.String("user", "alice"))
+
+	out := buf.String()
+	for _, want := range []string{`"trace_id":"t-1"`, `"pwd":"***"`, `"user":"alice"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %s", out, want)
+		}
+	}
+	for _, unwanted := range []string{"debug_", "traceID", "secret"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output %q contains %s", out, unwanted)
+		}
+	}
+}
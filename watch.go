@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package log
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// configReloadDelay 是配置文件变化后等待的时间，在此期间的连续写入只会触发一次重新加载.
+const configReloadDelay = 100 * time.Millisecond
+
+// WatchConfig 从 YAML 配置文件 path 中加载 Config 并初始化全局日志记录器，之后监听文件的变化，
+// 在文件被修改时重新加载、验证并通过 Init 应用新的配置. 一次保存引起的多次写入只会触发一次重新加载.
+// 无效的配置会被记录并忽略，继续使用之前的配置. 首次加载失败时返回错误.
+// 返回的 stop 函数停止监听.
+func WatchConfig(path string) (stop func(), err error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// 监听所在目录而不是文件本身，编辑器保存时可能会通过重命名替换文件
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	initOptions(cfg.ToOptions())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchConfigEvents(watcher, path)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			_ = watcher.Close()
+			<-done
+		})
+	}, nil
+}
+
+// watchConfigEvents 处理配置文件所在目录的事件，直到 watcher 被关闭.
+func watchConfigEvents(watcher *fsnotify.Watcher, path string) {
+	name := filepath.Clean(path)
+	reload := time.NewTimer(configReloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == name && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				reload.Reset(configReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			Warn("watch log config failed", zap.String("path", path), zap.Error(err))
+		case <-reload.C:
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				Warn("reload log config failed, keeping previous config", zap.String("path", path), zap.Error(err))
+				continue
+			}
+			initOptions(cfg.ToOptions())
+		}
+	}
+}
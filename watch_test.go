@@ -0,0 +1,61 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatchConfig 测试配置文件变化后重新初始化全局日志记录器，无效的配置被忽略.
+func TestWatchConfig(t *testing.T) {
+	initStd(t, WithOutputPaths(nil))
+
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("写入配置文件失败: %v", err)
+		}
+	}
+	write("level: warn\noutput_paths: []\n")
+
+	stop, err := WatchConfig(path)
+	if err != nil {
+		t.Fatalf("WatchConfig error: %v", err)
+	}
+	defer stop()
+	if GetLevel() != "warn" {
+		t.Fatalf("level = %s, want warn", GetLevel())
+	}
+
+	// 连续多次写入只触发一次重新加载
+	for _, level := range []string{"error", "info", "debug"} {
+		write("level: " + level + "\noutput_paths: []\n")
+	}
+	waitFor(t, func() bool { return GetLevel() == "debug" })
+
+	write("level: verbose\noutput_paths: []\n")
+	time.Sleep(3 * configReloadDelay)
+	if GetLevel() != "debug" {
+		t.Errorf("无效的配置应该被忽略, level = %s", GetLevel())
+	}
+
+	stop()
+	write("level: error\noutput_paths: []\n")
+	time.Sleep(3 * configReloadDelay)
+	if GetLevel() != "debug" {
+		t.Errorf("停止监听后不应该重新加载, level = %s", GetLevel())
+	}
+}
+
+// TestWatchConfigInvalid 测试首次加载无效的配置时返回错误.
+func TestWatchConfigInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	if err := os.WriteFile(path, []byte("format: xml\n"), 0o644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	if _, err := WatchConfig(path); err == nil {
+		t.Error("WatchConfig 应该返回配置无效的错误")
+	}
+}